@@ -0,0 +1,122 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package bench parses the output of go test -bench and compares it with a
+// stored baseline to report performance regressions.
+package bench
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bborbe/errors"
+)
+
+// Result is the median of all runs of a benchmark.
+type Result struct {
+	// Name without the GOMAXPROCS suffix, like BenchmarkGet/boltkv.
+	Name string
+	// Runs is the number of result lines, see go test -count.
+	Runs        int
+	NsPerOp     float64
+	BytesPerOp  float64
+	AllocsPerOp float64
+}
+
+// Results are sorted by name.
+type Results []Result
+
+// Find returns the result with the given name or nil.
+func (r Results) Find(name string) *Result {
+	pos := sort.Search(len(r), func(i int) bool { return r[i].Name >= name })
+	if pos < len(r) && r[pos].Name == name {
+		return &r[pos]
+	}
+	return nil
+}
+
+// Parse reads go test -bench output and returns the median of each benchmark.
+// Lines other than benchmark results are ignored.
+func Parse(ctx context.Context, reader io.Reader) (Results, error) {
+	runs := map[string][]Result{}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		result, ok := parseLine(scanner.Text())
+		if ok {
+			runs[result.Name] = append(runs[result.Name], result)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(ctx, err, "read benchmark output failed")
+	}
+	results := make(Results, 0, len(runs))
+	for name, list := range runs {
+		results = append(results, Result{
+			Name:        name,
+			Runs:        len(list),
+			NsPerOp:     median(list, func(r Result) float64 { return r.NsPerOp }),
+			BytesPerOp:  median(list, func(r Result) float64 { return r.BytesPerOp }),
+			AllocsPerOp: median(list, func(r Result) float64 { return r.AllocsPerOp }),
+		})
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+	return results, nil
+}
+
+// parseLine parses a line like
+// "BenchmarkGet/boltkv-8  1000  786.9 ns/op  248 B/op  6 allocs/op".
+func parseLine(line string) (Result, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+		return Result{}, false
+	}
+	if _, err := strconv.ParseInt(fields[1], 10, 64); err != nil {
+		return Result{}, false
+	}
+	result := Result{Name: trimProcs(fields[0])}
+	for i := 2; i+1 < len(fields); i += 2 {
+		value, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return Result{}, false
+		}
+		switch fields[i+1] {
+		case "ns/op":
+			result.NsPerOp = value
+		case "B/op":
+			result.BytesPerOp = value
+		case "allocs/op":
+			result.AllocsPerOp = value
+		}
+	}
+	return result, true
+}
+
+// trimProcs removes the -GOMAXPROCS suffix go test appends to the name.
+func trimProcs(name string) string {
+	pos := strings.LastIndexByte(name, '-')
+	if pos < 0 {
+		return name
+	}
+	if _, err := strconv.Atoi(name[pos+1:]); err != nil {
+		return name
+	}
+	return name[:pos]
+}
+
+func median(list []Result, value func(Result) float64) float64 {
+	values := make([]float64, len(list))
+	for i, result := range list {
+		values[i] = value(result)
+	}
+	sort.Float64s(values)
+	middle := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[middle-1] + values[middle]) / 2
+	}
+	return values[middle]
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bench
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// Comparison is a benchmark present in the baseline, the current results or both.
+type Comparison struct {
+	Name string
+	// Baseline is nil for a new benchmark.
+	Baseline *Result
+	// Current is nil for a removed benchmark.
+	Current *Result
+}
+
+// Change returns the change of ns/op in percent, zero unless in both results.
+func (c Comparison) Change() float64 {
+	if c.Baseline == nil || c.Current == nil || c.Baseline.NsPerOp == 0 {
+		return 0
+	}
+	return (c.Current.NsPerOp - c.Baseline.NsPerOp) / c.Baseline.NsPerOp * 100
+}
+
+// AllocsChange returns the change of allocs/op in percent, zero unless in both results.
+func (c Comparison) AllocsChange() float64 {
+	if c.Baseline == nil || c.Current == nil || c.Baseline.AllocsPerOp == 0 {
+		return 0
+	}
+	return (c.Current.AllocsPerOp - c.Baseline.AllocsPerOp) / c.Baseline.AllocsPerOp * 100
+}
+
+// Regression returns true if ns/op or allocs/op grew by more than threshold
+// percent. A benchmark without allocations in the baseline regresses with the
+// first allocation.
+func (c Comparison) Regression(threshold float64) bool {
+	if c.Baseline == nil || c.Current == nil {
+		return false
+	}
+	if c.Baseline.AllocsPerOp == 0 && c.Current.AllocsPerOp > 0 {
+		return true
+	}
+	return c.Change() > threshold || c.AllocsChange() > threshold
+}
+
+// Comparisons are sorted by name.
+type Comparisons []Comparison
+
+// Compare pairs the benchmarks of both results by name.
+func Compare(baseline Results, current Results) Comparisons {
+	names := map[string]bool{}
+	for _, result := range baseline {
+		names[result.Name] = true
+	}
+	for _, result := range current {
+		names[result.Name] = true
+	}
+	comparisons := make(Comparisons, 0, len(names))
+	for name := range names {
+		comparisons = append(comparisons, Comparison{
+			Name:     name,
+			Baseline: baseline.Find(name),
+			Current:  current.Find(name),
+		})
+	}
+	sort.Slice(comparisons, func(i, j int) bool { return comparisons[i].Name < comparisons[j].Name })
+	return comparisons
+}
+
+// Regressions returns the comparisons regressed by more than threshold percent.
+func (c Comparisons) Regressions(threshold float64) Comparisons {
+	var result Comparisons
+	for _, comparison := range c {
+		if comparison.Regression(threshold) {
+			result = append(result, comparison)
+		}
+	}
+	return result
+}
+
+// Write prints a table of old and new ns/op and allocs/op, marking
+// regressions by more than threshold percent.
+func (c Comparisons) Write(writer io.Writer, threshold float64) error {
+	buf := &bytes.Buffer{}
+	tw := tabwriter.NewWriter(buf, 0, 0, 2, ' ', 0)
+	_, err := fmt.Fprintln(tw, "name\told ns/op\tnew ns/op\tdelta\told allocs\tnew allocs\tstatus")
+	if err != nil {
+		return err
+	}
+	for _, comparison := range c {
+		var status string
+		switch {
+		case comparison.Baseline == nil:
+			status = "new"
+		case comparison.Current == nil:
+			status = "removed"
+		case comparison.Regression(threshold):
+			status = "REGRESSION"
+		}
+		_, err = fmt.Fprintf(
+			tw,
+			"%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			comparison.Name,
+			formatValue(comparison.Baseline, func(r *Result) float64 { return r.NsPerOp }),
+			formatValue(comparison.Current, func(r *Result) float64 { return r.NsPerOp }),
+			formatChange(comparison),
+			formatValue(comparison.Baseline, func(r *Result) float64 { return r.AllocsPerOp }),
+			formatValue(comparison.Current, func(r *Result) float64 { return r.AllocsPerOp }),
+			status,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	// drop the padding of rows without status
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		if _, err := io.WriteString(writer, strings.TrimRight(line, " ")+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatValue(result *Result, value func(r *Result) float64) string {
+	if result == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", value(result))
+}
+
+func formatChange(comparison Comparison) string {
+	if comparison.Baseline == nil || comparison.Current == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%+.1f%%", comparison.Change())
+}
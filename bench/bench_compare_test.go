@@ -0,0 +1,72 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bench_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv/bench"
+)
+
+var _ = Describe("Compare", func() {
+	var comparisons bench.Comparisons
+	BeforeEach(func() {
+		baseline := bench.Results{
+			{Name: "BenchmarkDelete", NsPerOp: 100, AllocsPerOp: 2},
+			{Name: "BenchmarkGet", NsPerOp: 100, AllocsPerOp: 20},
+			{Name: "BenchmarkPut", NsPerOp: 100, AllocsPerOp: 2},
+			{Name: "BenchmarkStats", NsPerOp: 100, AllocsPerOp: 2},
+		}
+		current := bench.Results{
+			{Name: "BenchmarkBucket", NsPerOp: 50, AllocsPerOp: 1},
+			{Name: "BenchmarkGet", NsPerOp: 105, AllocsPerOp: 21},
+			{Name: "BenchmarkPut", NsPerOp: 120, AllocsPerOp: 2},
+			{Name: "BenchmarkStats", NsPerOp: 80, AllocsPerOp: 3},
+		}
+		comparisons = bench.Compare(baseline, current)
+	})
+	It("pairs the benchmarks by name", func() {
+		Expect(comparisons).To(HaveLen(5))
+		Expect(comparisons[0].Name).To(Equal("BenchmarkBucket"))
+		Expect(comparisons[0].Baseline).To(BeNil())
+		Expect(comparisons[1].Name).To(Equal("BenchmarkDelete"))
+		Expect(comparisons[1].Current).To(BeNil())
+		Expect(comparisons[2].Change()).To(BeNumerically("~", 5, 0.001))
+		Expect(comparisons[2].AllocsChange()).To(BeNumerically("~", 5, 0.001))
+		Expect(comparisons[3].Change()).To(BeNumerically("~", 20, 0.001))
+	})
+	It("reports slowdowns and allocation increases over the threshold", func() {
+		var names []string
+		for _, regression := range comparisons.Regressions(10) {
+			names = append(names, regression.Name)
+		}
+		Expect(names).To(Equal([]string{"BenchmarkPut", "BenchmarkStats"}))
+		Expect(comparisons.Regressions(25)).To(HaveLen(1))
+		Expect(comparisons.Regressions(60)).To(BeEmpty())
+	})
+	It("reports the first allocation of an allocation free benchmark", func() {
+		comparison := bench.Comparison{
+			Name:     "BenchmarkGet",
+			Baseline: &bench.Result{Name: "BenchmarkGet", NsPerOp: 100},
+			Current:  &bench.Result{Name: "BenchmarkGet", NsPerOp: 100, AllocsPerOp: 1},
+		}
+		Expect(comparison.Regression(10)).To(BeTrue())
+	})
+	It("writes a table", func() {
+		buf := &bytes.Buffer{}
+		Expect(comparisons.Write(buf, 10)).To(Succeed())
+		Expect("\n" + buf.String()).To(Equal(`
+name             old ns/op  new ns/op  delta   old allocs  new allocs  status
+BenchmarkBucket  -          50.00      -       -           1.00        new
+BenchmarkDelete  100.00     -          -       2.00        -           removed
+BenchmarkGet     100.00     105.00     +5.0%   20.00       21.00
+BenchmarkPut     100.00     120.00     +20.0%  2.00        2.00        REGRESSION
+BenchmarkStats   100.00     80.00      -20.0%  2.00        3.00        REGRESSION
+`))
+	})
+})
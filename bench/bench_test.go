@@ -0,0 +1,71 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bench_test
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv/bench"
+)
+
+var _ = Describe("Parse", func() {
+	var ctx context.Context
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+	It("returns the median of each benchmark", func() {
+		results, err := bench.Parse(ctx, strings.NewReader(`goos: linux
+goarch: amd64
+pkg: github.com/bborbe/boltkv
+BenchmarkGet/boltkv-8     	  419569	       786.9 ns/op	     248 B/op	       6 allocs/op
+BenchmarkGet/boltkv-8     	  419569	       700.0 ns/op	     248 B/op	       6 allocs/op
+BenchmarkGet/boltkv-8     	  419569	       900.0 ns/op	     248 B/op	       6 allocs/op
+BenchmarkViewParallel     	   87188	      2830 ns/op	    1216 B/op	      19 allocs/op
+BenchmarkGet/bbolt-8      	  375208	       731.1 ns/op
+PASS
+ok  	github.com/bborbe/boltkv	9.611s
+`))
+		Expect(err).To(BeNil())
+		Expect(results).To(Equal(bench.Results{
+			{Name: "BenchmarkGet/bbolt", Runs: 1, NsPerOp: 731.1},
+			{
+				Name:        "BenchmarkGet/boltkv",
+				Runs:        3,
+				NsPerOp:     786.9,
+				BytesPerOp:  248,
+				AllocsPerOp: 6,
+			},
+			{
+				Name:        "BenchmarkViewParallel",
+				Runs:        1,
+				NsPerOp:     2830,
+				BytesPerOp:  1216,
+				AllocsPerOp: 19,
+			},
+		}))
+		Expect(results.Find("BenchmarkGet/bbolt")).NotTo(BeNil())
+		Expect(results.Find("BenchmarkPut")).To(BeNil())
+	})
+	It("averages the middle runs of an even count", func() {
+		results, err := bench.Parse(ctx, strings.NewReader(`BenchmarkPut-4 10 100 ns/op
+BenchmarkPut-4 10 300 ns/op
+`))
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].NsPerOp).To(Equal(200.0))
+	})
+	It("ignores lines not being results", func() {
+		results, err := bench.Parse(ctx, strings.NewReader(`BenchmarkGet
+BenchmarkGet-8 fast 1 ns/op
+--- FAIL: BenchmarkPut
+`))
+		Expect(err).To(BeNil())
+		Expect(results).To(BeEmpty())
+	})
+})
@@ -0,0 +1,47 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/bborbe/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Backup writes a consistent snapshot of the whole database to w.
+// It runs in a read transaction, writers are not blocked.
+func Backup(ctx context.Context, db DB, w io.Writer) (int64, error) {
+	var written int64
+	err := db.DB().View(func(tx *bolt.Tx) error {
+		var err error
+		written, err = tx.WriteTo(w)
+		return err
+	})
+	if err != nil {
+		return written, errors.Wrapf(ctx, err, "backup failed")
+	}
+	return written, nil
+}
+
+// BackupFile writes a consistent snapshot of the whole database to path.
+// The file is written to a temporary file next to path and renamed on
+// success, so path never contains a partial backup.
+func BackupFile(ctx context.Context, db DB, path string) error {
+	tmpPath := path + ".tmp"
+	err := db.DB().View(func(tx *bolt.Tx) error {
+		return tx.CopyFile(tmpPath, 0600)
+	})
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return errors.Wrapf(ctx, err, "backup to %s failed", tmpPath)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return errors.Wrapf(ctx, err, "rename %s to %s failed", tmpPath, path)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+)
+
+var _ = Describe("Backup", func() {
+	var ctx context.Context
+	var db boltkv.DB
+	var dir string
+	BeforeEach(func() {
+		ctx = context.Background()
+		var err error
+		dir, err = os.MkdirTemp("", "")
+		Expect(err).To(BeNil())
+		db, err = boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		Expect(db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.CreateBucket(ctx, libkv.NewBucketName("a"))
+			Expect(err).To(BeNil())
+			return bucket.Put(ctx, []byte("k"), []byte("v"))
+		})).To(Succeed())
+	})
+	AfterEach(func() {
+		_ = db.Close()
+		_ = db.Remove()
+		_ = os.RemoveAll(dir)
+	})
+	expectBackupContent := func(path string) {
+		backup, err := boltkv.OpenFile(ctx, path)
+		Expect(err).To(BeNil())
+		defer backup.Close()
+		Expect(backup.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.Bucket(ctx, libkv.NewBucketName("a"))
+			Expect(err).To(BeNil())
+			item, err := bucket.Get(ctx, []byte("k"))
+			Expect(err).To(BeNil())
+			Expect(item.Exists()).To(BeTrue())
+			return nil
+		})).To(Succeed())
+	}
+	It("writes a snapshot to a writer", func() {
+		buf := &bytes.Buffer{}
+		written, err := boltkv.Backup(ctx, db, buf)
+		Expect(err).To(BeNil())
+		Expect(written).To(Equal(int64(buf.Len())))
+		path := filepath.Join(dir, "backup.db")
+		Expect(os.WriteFile(path, buf.Bytes(), 0600)).To(Succeed())
+		expectBackupContent(path)
+	})
+	It("writes a snapshot to a file", func() {
+		path := filepath.Join(dir, "backup.db")
+		Expect(boltkv.BackupFile(ctx, db, path)).To(Succeed())
+		Expect(fileExists(path + ".tmp")).To(BeFalse())
+		expectBackupContent(path)
+	})
+})
@@ -0,0 +1,430 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	libkv "github.com/bborbe/kv"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bborbe/boltkv"
+	"github.com/bborbe/boltkv/boltkvtest"
+)
+
+// The benchmarks run each operation through boltkv and, where the overhead of
+// the wrapper is of interest, through raw bbolt as "bbolt" sub-benchmark.
+// Run them with boltkv-bench to compare against bench/baseline.txt.
+
+const (
+	benchmarkGroups        = 100
+	benchmarkKeysPerGroup  = 100
+	benchmarkKeys          = benchmarkGroups * benchmarkKeysPerGroup
+	benchmarkLargeBuckets  = 10
+	benchmarkWriteBatch    = 1000
+	benchmarkValueTemplate = "value-%08d-0123456789abcdef0123456789abcdef"
+)
+
+var benchmarkBucketName = libkv.NewBucketName("bench")
+
+func noSync(opts *bolt.Options) {
+	opts.NoSync = true
+}
+
+// benchmarkKey returns key i, grouped by the prefix "%04d/" for prefix scans.
+func benchmarkKey(i int) []byte {
+	return []byte(fmt.Sprintf("%04d/%04d", i/benchmarkKeysPerGroup, i%benchmarkKeysPerGroup))
+}
+
+func benchmarkValue(i int) []byte {
+	return []byte(fmt.Sprintf(benchmarkValueTemplate, i))
+}
+
+// openBenchmarkDB returns a database with keys keys in each of buckets buckets.
+func openBenchmarkDB(b *testing.B, buckets int, keys int) boltkv.DB {
+	b.Helper()
+	db := boltkvtest.OpenDB(b, boltkv.TempOptions{}, noSync)
+	err := db.DB().Update(func(tx *bolt.Tx) error {
+		for n := 0; n < buckets; n++ {
+			name := benchmarkBucketName
+			if n > 0 {
+				name = libkv.NewBucketName(fmt.Sprintf("bench-%d", n))
+			}
+			bucket, err := tx.CreateBucket(name)
+			if err != nil {
+				return err
+			}
+			for i := 0; i < keys; i++ {
+				if err := bucket.Put(benchmarkKey(i), benchmarkValue(i)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		b.Fatalf("fill failed: %v", err)
+	}
+	return db
+}
+
+// viewBucket runs fn with the benchmark bucket in a View.
+func viewBucket(b *testing.B, db boltkv.DB, fn func(ctx context.Context, bucket libkv.Bucket)) {
+	b.Helper()
+	err := db.View(context.Background(), func(ctx context.Context, tx libkv.Tx) error {
+		bucket, err := tx.Bucket(ctx, benchmarkBucketName)
+		if err != nil {
+			return err
+		}
+		fn(ctx, bucket)
+		return nil
+	})
+	if err != nil {
+		b.Fatalf("view failed: %v", err)
+	}
+}
+
+// viewBoltBucket runs fn with the benchmark bucket in a bbolt View.
+func viewBoltBucket(b *testing.B, db boltkv.DB, fn func(bucket *bolt.Bucket)) {
+	b.Helper()
+	err := db.DB().View(func(tx *bolt.Tx) error {
+		fn(tx.Bucket(benchmarkBucketName))
+		return nil
+	})
+	if err != nil {
+		b.Fatalf("view failed: %v", err)
+	}
+}
+
+// updateBatches calls fn for b.N operations, benchmarkWriteBatch in each Update.
+func updateBatches(
+	b *testing.B,
+	db boltkv.DB,
+	fn func(ctx context.Context, bucket libkv.Bucket, i int) error,
+) {
+	b.Helper()
+	for start := 0; start < b.N; start += benchmarkWriteBatch {
+		err := db.Update(context.Background(), func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.CreateBucketIfNotExists(ctx, benchmarkBucketName)
+			if err != nil {
+				return err
+			}
+			for i := start; i < start+benchmarkWriteBatch && i < b.N; i++ {
+				if err := fn(ctx, bucket, i); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			b.Fatalf("update failed: %v", err)
+		}
+	}
+}
+
+// updateBoltBatches is updateBatches with raw bbolt.
+func updateBoltBatches(b *testing.B, db boltkv.DB, fn func(bucket *bolt.Bucket, i int) error) {
+	b.Helper()
+	for start := 0; start < b.N; start += benchmarkWriteBatch {
+		err := db.DB().Update(func(tx *bolt.Tx) error {
+			bucket, err := tx.CreateBucketIfNotExists(benchmarkBucketName)
+			if err != nil {
+				return err
+			}
+			for i := start; i < start+benchmarkWriteBatch && i < b.N; i++ {
+				if err := fn(bucket, i); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			b.Fatalf("update failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkGet(b *testing.B) {
+	db := openBenchmarkDB(b, 1, benchmarkKeys)
+	b.Run("boltkv", func(b *testing.B) {
+		b.ReportAllocs()
+		viewBucket(b, db, func(ctx context.Context, bucket libkv.Bucket) {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				item, err := bucket.Get(ctx, benchmarkKey(i%benchmarkKeys))
+				if err != nil || !item.Exists() {
+					b.Fatalf("get failed: %v", err)
+				}
+			}
+		})
+	})
+	b.Run("bbolt", func(b *testing.B) {
+		b.ReportAllocs()
+		viewBoltBucket(b, db, func(bucket *bolt.Bucket) {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if bucket.Get(benchmarkKey(i%benchmarkKeys)) == nil {
+					b.Fatalf("get failed")
+				}
+			}
+		})
+	})
+}
+
+func BenchmarkPut(b *testing.B) {
+	b.Run("boltkv", func(b *testing.B) {
+		db := openBenchmarkDB(b, 0, 0)
+		b.ReportAllocs()
+		b.ResetTimer()
+		updateBatches(b, db, func(ctx context.Context, bucket libkv.Bucket, i int) error {
+			return bucket.Put(ctx, benchmarkKey(i), benchmarkValue(i))
+		})
+	})
+	b.Run("bbolt", func(b *testing.B) {
+		db := openBenchmarkDB(b, 0, 0)
+		b.ReportAllocs()
+		b.ResetTimer()
+		updateBoltBatches(b, db, func(bucket *bolt.Bucket, i int) error {
+			return bucket.Put(benchmarkKey(i), benchmarkValue(i))
+		})
+	})
+}
+
+func BenchmarkDelete(b *testing.B) {
+	b.Run("boltkv", func(b *testing.B) {
+		db := openBenchmarkDB(b, 1, b.N)
+		b.ReportAllocs()
+		b.ResetTimer()
+		updateBatches(b, db, func(ctx context.Context, bucket libkv.Bucket, i int) error {
+			return bucket.Delete(ctx, benchmarkKey(i))
+		})
+	})
+	b.Run("bbolt", func(b *testing.B) {
+		db := openBenchmarkDB(b, 1, b.N)
+		b.ReportAllocs()
+		b.ResetTimer()
+		updateBoltBatches(b, db, func(bucket *bolt.Bucket, i int) error {
+			return bucket.Delete(benchmarkKey(i))
+		})
+	})
+}
+
+// benchmarkIterate moves the iterator b.N times, starting over at the end.
+func benchmarkIterate(b *testing.B, db boltkv.DB, reverse bool) {
+	b.ReportAllocs()
+	viewBucket(b, db, func(ctx context.Context, bucket libkv.Bucket) {
+		it := bucket.Iterator()
+		if reverse {
+			it = bucket.IteratorReverse()
+		}
+		defer it.Close()
+		b.ResetTimer()
+		it.Rewind()
+		for i := 0; i < b.N; i++ {
+			if !it.Valid() {
+				it.Rewind()
+			}
+			_ = it.Item().Key()
+			it.Next()
+		}
+	})
+}
+
+func BenchmarkIterate(b *testing.B) {
+	db := openBenchmarkDB(b, 1, benchmarkKeys)
+	b.Run("boltkv", func(b *testing.B) {
+		benchmarkIterate(b, db, false)
+	})
+	b.Run("bbolt", func(b *testing.B) {
+		b.ReportAllocs()
+		viewBoltBucket(b, db, func(bucket *bolt.Bucket) {
+			cursor := bucket.Cursor()
+			b.ResetTimer()
+			key, _ := cursor.First()
+			for i := 0; i < b.N; i++ {
+				if key == nil {
+					key, _ = cursor.First()
+				}
+				key, _ = cursor.Next()
+			}
+		})
+	})
+}
+
+func BenchmarkIterateReverse(b *testing.B) {
+	db := openBenchmarkDB(b, 1, benchmarkKeys)
+	b.Run("boltkv", func(b *testing.B) {
+		benchmarkIterate(b, db, true)
+	})
+	b.Run("bbolt", func(b *testing.B) {
+		b.ReportAllocs()
+		viewBoltBucket(b, db, func(bucket *bolt.Bucket) {
+			cursor := bucket.Cursor()
+			b.ResetTimer()
+			key, _ := cursor.Last()
+			for i := 0; i < b.N; i++ {
+				if key == nil {
+					key, _ = cursor.Last()
+				}
+				key, _ = cursor.Prev()
+			}
+		})
+	})
+}
+
+// BenchmarkPrefixScan reads all benchmarkKeysPerGroup keys of one group per operation.
+func BenchmarkPrefixScan(b *testing.B) {
+	db := openBenchmarkDB(b, 1, benchmarkKeys)
+	prefix := func(i int) []byte {
+		return []byte(fmt.Sprintf("%04d/", i%benchmarkGroups))
+	}
+	b.Run("boltkv", func(b *testing.B) {
+		b.ReportAllocs()
+		viewBucket(b, db, func(ctx context.Context, bucket libkv.Bucket) {
+			it := bucket.Iterator()
+			defer it.Close()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				prefix := prefix(i)
+				count := 0
+				for it.Seek(prefix); it.Valid(); it.Next() {
+					if !bytes.HasPrefix(it.Item().Key(), prefix) {
+						break
+					}
+					count++
+				}
+				if count != benchmarkKeysPerGroup {
+					b.Fatalf("scan found %d keys", count)
+				}
+			}
+		})
+	})
+	b.Run("bbolt", func(b *testing.B) {
+		b.ReportAllocs()
+		viewBoltBucket(b, db, func(bucket *bolt.Bucket) {
+			cursor := bucket.Cursor()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				prefix := prefix(i)
+				count := 0
+				for key, _ := cursor.Seek(prefix); key != nil; key, _ = cursor.Next() {
+					if !bytes.HasPrefix(key, prefix) {
+						break
+					}
+					count++
+				}
+				if count != benchmarkKeysPerGroup {
+					b.Fatalf("scan found %d keys", count)
+				}
+			}
+		})
+	})
+}
+
+// BenchmarkBucket looks up the same bucket repeatedly in one transaction,
+// served by the bucket cache, and once per new Tx without it.
+func BenchmarkBucket(b *testing.B) {
+	db := openBenchmarkDB(b, 1, 1)
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+		err := db.View(context.Background(), func(ctx context.Context, tx libkv.Tx) error {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := tx.Bucket(ctx, benchmarkBucketName); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			b.Fatalf("view failed: %v", err)
+		}
+	})
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		ctx := context.Background()
+		err := db.DB().View(func(boltTx *bolt.Tx) error {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := boltkv.NewTx(boltTx).Bucket(ctx, benchmarkBucketName); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			b.Fatalf("view failed: %v", err)
+		}
+	})
+	b.Run("bbolt", func(b *testing.B) {
+		b.ReportAllocs()
+		err := db.DB().View(func(tx *bolt.Tx) error {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if tx.Bucket(benchmarkBucketName) == nil {
+					b.Fatalf("bucket not found")
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			b.Fatalf("view failed: %v", err)
+		}
+	})
+}
+
+// BenchmarkStats compares Stats and StatsDetailed on a file with
+// benchmarkLargeBuckets buckets of benchmarkKeys keys.
+func BenchmarkStats(b *testing.B) {
+	db := openBenchmarkDB(b, benchmarkLargeBuckets, benchmarkKeys)
+	ctx := context.Background()
+	b.Run("Stats", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := db.Stats(ctx); err != nil {
+				b.Fatalf("stats failed: %v", err)
+			}
+		}
+	})
+	b.Run("StatsDetailed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := db.StatsDetailed(ctx); err != nil {
+				b.Fatalf("stats detailed failed: %v", err)
+			}
+		}
+	})
+}
+
+// BenchmarkViewParallel runs a View with a single Get per operation from
+// GOMAXPROCS goroutines.
+func BenchmarkViewParallel(b *testing.B) {
+	db := openBenchmarkDB(b, 1, benchmarkKeys)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		ctx := context.Background()
+		i := 0
+		for pb.Next() {
+			key := benchmarkKey(i % benchmarkKeys)
+			i++
+			err := db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+				bucket, err := tx.Bucket(ctx, benchmarkBucketName)
+				if err != nil {
+					return err
+				}
+				_, err = bucket.Get(ctx, key)
+				return err
+			})
+			if err != nil {
+				b.Errorf("view failed: %v", err)
+				return
+			}
+		}
+	})
+}
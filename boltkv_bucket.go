@@ -11,19 +11,45 @@ import (
 	bolt "go.etcd.io/bbolt"
 )
 
+//counterfeiter:generate -o mocks/boltkv-bucket.go --fake-name BoltkvBucket . Bucket
 type Bucket interface {
 	libkv.Bucket
 	Bucket() *bolt.Bucket
 }
 
 func NewBucket(boltBucket *bolt.Bucket) Bucket {
+	return newBucket(context.Background(), boltBucket, nil, noopHook{}, nil, nil)
+}
+
+func newBucket(
+	ctx context.Context,
+	boltBucket *bolt.Bucket,
+	name libkv.BucketName,
+	hook Hook,
+	fingerprint *fingerprintTracker,
+	recorder *replicationRecorder,
+) Bucket {
 	return &bucket{
-		boltBucket: boltBucket,
+		ctx:         ctx,
+		boltBucket:  boltBucket,
+		name:        name,
+		hook:        hook,
+		fingerprint: fingerprint,
+		recorder:    recorder,
 	}
 }
 
 type bucket struct {
+	// ctx is the context the bucket was opened with, used as parent for
+	// iterators because Iterator() does not take a context
+	ctx        context.Context
 	boltBucket *bolt.Bucket
+	name       libkv.BucketName
+	hook       Hook
+	// fingerprint is nil unless the bucket has a stored fingerprint, see WithFingerprint
+	fingerprint *fingerprintTracker
+	// recorder is nil unless the replication log is enabled, see WithReplicationLog
+	recorder *replicationRecorder
 }
 
 func (b *bucket) Bucket() *bolt.Bucket {
@@ -31,21 +57,84 @@ func (b *bucket) Bucket() *bolt.Bucket {
 }
 
 func (b *bucket) IteratorReverse() libkv.Iterator {
-	return NewIteratorReverse(b.boltBucket.Cursor())
+	return b.hookIterator(NewIteratorReverse(b.boltBucket.Cursor()))
 }
 
 func (b *bucket) Iterator() libkv.Iterator {
-	return NewIterator(b.boltBucket.Cursor())
+	return b.hookIterator(NewIterator(b.boltBucket.Cursor()))
+}
+
+func (b *bucket) hookIterator(iterator Iterator) Iterator {
+	if _, ok := b.hook.(noopHook); ok {
+		return iterator
+	}
+	return &hookIterator{
+		Iterator:   iterator,
+		ctx:        b.hook.Start(b.ctx, OperationIterator, b.name),
+		bucketName: b.name,
+		hook:       b.hook,
+	}
 }
 
 func (b *bucket) Get(ctx context.Context, key []byte) (libkv.Item, error) {
-	return libkv.NewByteItem(key, b.boltBucket.Get(key)), nil
+	ctx = b.hook.Start(ctx, OperationGet, b.name)
+	item := libkv.NewByteItem(key, b.boltBucket.Get(key))
+	b.hook.End(ctx, OperationGet, b.name, nil)
+	return item, nil
 }
 
 func (b *bucket) Put(ctx context.Context, key []byte, value []byte) error {
-	return b.boltBucket.Put(key, value)
+	ctx = b.hook.Start(ctx, OperationPut, b.name)
+	// bolt stores a nil value as empty value on commit
+	stored := value
+	if stored == nil {
+		stored = []byte{}
+	}
+	err := b.write(key, stored, func() error {
+		return b.boltBucket.Put(key, value)
+	})
+	if err == nil {
+		b.recorder.record(ReplicationMutationPut, b.name, key, value)
+	}
+	b.hook.End(ctx, OperationPut, b.name, err)
+	return err
 }
 
 func (b *bucket) Delete(ctx context.Context, key []byte) error {
-	return b.boltBucket.Delete(key)
+	ctx = b.hook.Start(ctx, OperationDelete, b.name)
+	err := b.write(key, nil, func() error {
+		return b.boltBucket.Delete(key)
+	})
+	if err == nil {
+		b.recorder.record(ReplicationMutationDelete, b.name, key, nil)
+	}
+	b.hook.End(ctx, OperationDelete, b.name, err)
+	return err
+}
+
+// write runs the bolt write and updates the fingerprint only if it succeeded,
+// value is the new value of key, nil if deleted.
+func (b *bucket) write(key []byte, value []byte, write func() error) error {
+	if b.fingerprint == nil {
+		return write()
+	}
+	return b.fingerprint.update(b.boltBucket, key, value, write)
+}
+
+// hookIterator reports the iterator lifetime from creation until Close.
+type hookIterator struct {
+	Iterator
+	ctx        context.Context
+	bucketName libkv.BucketName
+	hook       Hook
+	closed     bool
+}
+
+func (h *hookIterator) Close() {
+	h.Iterator.Close()
+	if h.closed {
+		return
+	}
+	h.closed = true
+	h.hook.End(h.ctx, OperationIterator, h.bucketName, nil)
 }
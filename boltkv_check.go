@@ -0,0 +1,70 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv
+
+import (
+	"context"
+
+	"github.com/bborbe/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+// CheckBolt is the CheckFinding.Check value of page-level findings.
+const CheckBolt = "bolt"
+
+// CheckFinding is a single inconsistency reported by a check.
+type CheckFinding struct {
+	// Check is CheckBolt for page-level findings or the name of the invariant.
+	Check   string
+	Message string
+}
+
+func (c CheckFinding) String() string {
+	return c.Check + ": " + c.Message
+}
+
+// CheckFindings is the result of a check, empty if the database is consistent.
+type CheckFindings []CheckFinding
+
+// Corrupted returns true if at least one inconsistency was found.
+func (c CheckFindings) Corrupted() bool {
+	return len(c) > 0
+}
+
+// Check runs bolt's page-level consistency check of db in a single read transaction.
+// It verifies that all pages are reachable exactly once, not freed twice and
+// that keys are ordered. The check walks the whole file — O(total pages).
+func Check(ctx context.Context, db DB) (CheckFindings, error) {
+	var findings CheckFindings
+	err := db.DB().View(func(tx *bolt.Tx) error {
+		var err error
+		findings, err = checkTx(ctx, tx)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "check failed")
+	}
+	return findings, nil
+}
+
+// checkTx runs bolt's page-level check on tx. bolt cannot stop a running check
+// and its channel must be drained before tx closes, so once ctx is canceled the
+// remaining findings are discarded and the cancellation is returned.
+func checkTx(ctx context.Context, tx *bolt.Tx) (CheckFindings, error) {
+	var findings CheckFindings
+	for err := range tx.Check() {
+		if ctx.Err() != nil {
+			continue
+		}
+		findings = append(findings, CheckFinding{
+			Check:   CheckBolt,
+			Message: err.Error(),
+		})
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Wrapf(ctx, err, "check canceled")
+	}
+	return findings, nil
+}
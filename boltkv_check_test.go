@@ -0,0 +1,63 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv_test
+
+import (
+	"context"
+	"fmt"
+
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+)
+
+var _ = Describe("Check", func() {
+	var ctx context.Context
+	var db boltkv.DB
+	BeforeEach(func() {
+		ctx = context.Background()
+		var err error
+		db, err = boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+	})
+	AfterEach(func() {
+		_ = db.Close()
+		_ = db.Remove()
+	})
+	It("returns no findings for an empty db", func() {
+		findings, err := boltkv.Check(ctx, db)
+		Expect(err).To(BeNil())
+		Expect(findings).To(BeEmpty())
+		Expect(findings.Corrupted()).To(BeFalse())
+	})
+	It("returns no findings for a db with data", func() {
+		err := db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.CreateBucket(ctx, libkv.NewBucketName("test"))
+			Expect(err).To(BeNil())
+			for i := 0; i < 1000; i++ {
+				key := []byte(fmt.Sprintf("key-%04d", i))
+				Expect(bucket.Put(ctx, key, key)).To(Succeed())
+			}
+			return nil
+		})
+		Expect(err).To(BeNil())
+		findings, err := boltkv.Check(ctx, db)
+		Expect(err).To(BeNil())
+		Expect(findings).To(BeEmpty())
+	})
+})
+
+var _ = Describe("CheckFindings", func() {
+	It("is corrupted with findings", func() {
+		findings := boltkv.CheckFindings{{
+			Check:   boltkv.CheckBolt,
+			Message: "page 3: unreachable unfreed",
+		}}
+		Expect(findings.Corrupted()).To(BeTrue())
+		Expect(findings[0].String()).To(Equal("bolt: page 3: unreachable unfreed"))
+	})
+})
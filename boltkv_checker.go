@@ -0,0 +1,167 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+)
+
+// Invariant validates an application-level property of the stored data.
+// Check returns one message per violation; an error aborts the check.
+//
+//counterfeiter:generate -o mocks/boltkv-invariant.go --fake-name BoltkvInvariant . Invariant
+type Invariant interface {
+	Name() string
+	Check(ctx context.Context, tx libkv.Tx) ([]string, error)
+}
+
+// NewInvariant returns an Invariant with the given name and check function.
+func NewInvariant(
+	name string,
+	fn func(ctx context.Context, tx libkv.Tx) ([]string, error),
+) Invariant {
+	return &invariant{
+		name: name,
+		fn:   fn,
+	}
+}
+
+type invariant struct {
+	name string
+	fn   func(ctx context.Context, tx libkv.Tx) ([]string, error)
+}
+
+func (i *invariant) Name() string {
+	return i.name
+}
+
+func (i *invariant) Check(ctx context.Context, tx libkv.Tx) ([]string, error) {
+	return i.fn(ctx, tx)
+}
+
+// Checker validates a database: bolt's page-level check followed by all
+// registered invariants, evaluated together in one read transaction.
+//
+//counterfeiter:generate -o mocks/boltkv-checker.go --fake-name BoltkvChecker . Checker
+type Checker interface {
+	Register(invariants ...Invariant)
+	Check(ctx context.Context) (CheckFindings, error)
+}
+
+// NewChecker returns a Checker for the given database.
+func NewChecker(db DB, invariants ...Invariant) Checker {
+	return &checker{
+		db:         db,
+		invariants: invariants,
+	}
+}
+
+type checker struct {
+	db         DB
+	invariants []Invariant
+}
+
+func (c *checker) Register(invariants ...Invariant) {
+	c.invariants = append(c.invariants, invariants...)
+}
+
+func (c *checker) Check(ctx context.Context) (CheckFindings, error) {
+	var findings CheckFindings
+	err := c.db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		boltkvTx, ok := tx.(Tx)
+		if !ok {
+			return errors.Errorf(ctx, "tx is not a bolt transaction")
+		}
+		var err error
+		findings, err = checkTx(ctx, boltkvTx.Tx())
+		if err != nil {
+			return errors.Wrapf(ctx, err, "check db failed")
+		}
+		for _, invariant := range c.invariants {
+			if err := ctx.Err(); err != nil {
+				return errors.Wrapf(ctx, err, "check canceled")
+			}
+			messages, err := invariant.Check(ctx, tx)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "check invariant %s failed", invariant.Name())
+			}
+			for _, message := range messages {
+				findings = append(findings, CheckFinding{
+					Check:   invariant.Name(),
+					Message: message,
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "check failed")
+	}
+	return findings, nil
+}
+
+// NewIndexInvariant returns an Invariant verifying that indexBucket contains
+// exactly the keys indexKeys derives from the entries of primaryBucket.
+// It keeps all expected index keys in memory.
+func NewIndexInvariant(
+	name string,
+	primaryBucket libkv.BucketName,
+	indexBucket libkv.BucketName,
+	indexKeys func(key []byte, value []byte) ([][]byte, error),
+) Invariant {
+	return NewInvariant(name, func(ctx context.Context, tx libkv.Tx) ([]string, error) {
+		primary, err := tx.Bucket(ctx, primaryBucket)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "get bucket %s failed", primaryBucket)
+		}
+		index, err := tx.Bucket(ctx, indexBucket)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "get bucket %s failed", indexBucket)
+		}
+		var messages []string
+		expected := make(map[string]struct{})
+		err = libkv.ForEach(ctx, primary, func(item libkv.Item) error {
+			return item.Value(func(value []byte) error {
+				keys, err := indexKeys(item.Key(), value)
+				if err != nil {
+					return errors.Wrapf(ctx, err, "get index keys of %q failed", item.Key())
+				}
+				for _, key := range keys {
+					expected[string(key)] = struct{}{}
+				}
+				return nil
+			})
+		})
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "read bucket %s failed", primaryBucket)
+		}
+		err = libkv.ForEach(ctx, index, func(item libkv.Item) error {
+			key := string(item.Key())
+			if _, ok := expected[key]; !ok {
+				messages = append(messages, fmt.Sprintf("index key %q has no primary entry", key))
+				return nil
+			}
+			delete(expected, key)
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "read bucket %s failed", indexBucket)
+		}
+		missing := make([]string, 0, len(expected))
+		for key := range expected {
+			missing = append(missing, key)
+		}
+		sort.Strings(missing)
+		for _, key := range missing {
+			messages = append(messages, fmt.Sprintf("index key %q is missing", key))
+		}
+		return messages, nil
+	})
+}
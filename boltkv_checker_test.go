@@ -0,0 +1,112 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv_test
+
+import (
+	"context"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+)
+
+var _ = Describe("Checker", func() {
+	var ctx context.Context
+	var db boltkv.DB
+	var checker boltkv.Checker
+	var primaryBucket libkv.BucketName
+	var indexBucket libkv.BucketName
+	put := func(bucketName libkv.BucketName, key string, value string) {
+		Expect(db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.CreateBucketIfNotExists(ctx, bucketName)
+			Expect(err).To(BeNil())
+			return bucket.Put(ctx, []byte(key), []byte(value))
+		})).To(Succeed())
+	}
+	BeforeEach(func() {
+		ctx = context.Background()
+		var err error
+		db, err = boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		primaryBucket = libkv.NewBucketName("user")
+		indexBucket = libkv.NewBucketName("user-by-email")
+		checker = boltkv.NewChecker(db)
+		checker.Register(boltkv.NewIndexInvariant(
+			"user-email-index",
+			primaryBucket,
+			indexBucket,
+			func(key []byte, value []byte) ([][]byte, error) {
+				return [][]byte{append(append([]byte{}, value...), key...)}, nil
+			},
+		))
+		put(primaryBucket, "1", "a@example.com")
+		put(indexBucket, "a@example.com1", "")
+	})
+	AfterEach(func() {
+		_ = db.Close()
+		_ = db.Remove()
+	})
+	It("returns no findings if the index matches", func() {
+		findings, err := checker.Check(ctx)
+		Expect(err).To(BeNil())
+		Expect(findings).To(BeEmpty())
+	})
+	It("reports missing index keys", func() {
+		put(primaryBucket, "2", "b@example.com")
+		findings, err := checker.Check(ctx)
+		Expect(err).To(BeNil())
+		Expect(findings).To(Equal(boltkv.CheckFindings{{
+			Check:   "user-email-index",
+			Message: `index key "b@example.com2" is missing`,
+		}}))
+	})
+	It("reports orphaned index keys", func() {
+		put(indexBucket, "c@example.com3", "")
+		findings, err := checker.Check(ctx)
+		Expect(err).To(BeNil())
+		Expect(findings).To(Equal(boltkv.CheckFindings{{
+			Check:   "user-email-index",
+			Message: `index key "c@example.com3" has no primary entry`,
+		}}))
+	})
+	It("returns error if an invariant fails", func() {
+		checker.Register(boltkv.NewInvariant(
+			"broken",
+			func(ctx context.Context, tx libkv.Tx) ([]string, error) {
+				return nil, errors.New(ctx, "banana")
+			},
+		))
+		_, err := checker.Check(ctx)
+		Expect(err).NotTo(BeNil())
+	})
+	It("checks in one read transaction", func() {
+		var views int
+		hooked := boltkv.NewDB(db.DB(), boltkv.WithHook(boltkv.HookFuncs{
+			EndFunc: func(ctx context.Context, op boltkv.Operation, name libkv.BucketName, err error) {
+				if op == boltkv.OperationView {
+					views++
+				}
+			},
+		}))
+		findings, err := boltkv.NewChecker(hooked, boltkv.NewInvariant(
+			"empty",
+			func(ctx context.Context, tx libkv.Tx) ([]string, error) {
+				return nil, nil
+			},
+		)).Check(ctx)
+		Expect(err).To(BeNil())
+		Expect(findings.Corrupted()).To(BeFalse())
+		Expect(views).To(Equal(1))
+	})
+	It("returns error if the context is canceled", func() {
+		cancelCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := checker.Check(cancelCtx)
+		Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+	})
+})
@@ -0,0 +1,33 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv
+
+import (
+	"context"
+
+	"github.com/bborbe/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+// DefaultCompactTxMaxSize is the number of bytes copied per transaction by Compact.
+const DefaultCompactTxMaxSize = 64 * 1024 * 1024
+
+// Compact copies all buckets of src into a new database at dstPath, dropping
+// free pages, so the new file is as small as possible. src stays untouched and
+// can be replaced by dstPath after both are closed.
+func Compact(ctx context.Context, src DB, dstPath string, fn ...ChangeOptions) error {
+	dst, err := OpenFile(ctx, dstPath, fn...)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "open %s failed", dstPath)
+	}
+	if err := bolt.Compact(dst.DB(), src.DB(), DefaultCompactTxMaxSize); err != nil {
+		_ = dst.Close()
+		return errors.Wrapf(ctx, err, "compact to %s failed", dstPath)
+	}
+	if err := dst.Close(); err != nil {
+		return errors.Wrapf(ctx, err, "close %s failed", dstPath)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+)
+
+var _ = Describe("Compact", func() {
+	var ctx context.Context
+	var db boltkv.DB
+	var dir string
+	BeforeEach(func() {
+		ctx = context.Background()
+		var err error
+		dir, err = os.MkdirTemp("", "")
+		Expect(err).To(BeNil())
+		db, err = boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		Expect(db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.CreateBucket(ctx, libkv.NewBucketName("a"))
+			Expect(err).To(BeNil())
+			for i := 0; i < 10000; i++ {
+				key := []byte(fmt.Sprintf("key-%05d", i))
+				Expect(bucket.Put(ctx, key, key)).To(Succeed())
+			}
+			return nil
+		})).To(Succeed())
+		Expect(db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.Bucket(ctx, libkv.NewBucketName("a"))
+			Expect(err).To(BeNil())
+			for i := 0; i < 9990; i++ {
+				Expect(bucket.Delete(ctx, []byte(fmt.Sprintf("key-%05d", i)))).To(Succeed())
+			}
+			return nil
+		})).To(Succeed())
+	})
+	AfterEach(func() {
+		_ = db.Close()
+		_ = db.Remove()
+		_ = os.RemoveAll(dir)
+	})
+	It("writes a smaller copy with the same content", func() {
+		path := filepath.Join(dir, "compact.db")
+		Expect(boltkv.Compact(ctx, db, path)).To(Succeed())
+
+		srcInfo, err := os.Stat(db.DB().Path())
+		Expect(err).To(BeNil())
+		dstInfo, err := os.Stat(path)
+		Expect(err).To(BeNil())
+		Expect(dstInfo.Size()).To(BeNumerically("<", srcInfo.Size()))
+
+		compacted, err := boltkv.OpenFile(ctx, path)
+		Expect(err).To(BeNil())
+		defer compacted.Close()
+		stats, err := compacted.StatsDetailed(ctx)
+		Expect(err).To(BeNil())
+		Expect(stats.Buckets).To(HaveLen(1))
+		Expect(stats.Buckets[0].KeyCount).To(Equal(int64(10)))
+	})
+})
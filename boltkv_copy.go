@@ -0,0 +1,324 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	"github.com/golang/glog"
+)
+
+// DefaultCopyBatchSize is the number of keys written per Update.
+const DefaultCopyBatchSize = 1000
+
+// defaultCopyCheckpointBucket is the destination bucket holding the copy checkpoint.
+const defaultCopyCheckpointBucket = InternalBucketPrefix + "copy"
+
+var copyCheckpointKey = []byte("checkpoint")
+
+// CopyProgress is reported after every written batch.
+type CopyProgress struct {
+	// Bucket is the bucket currently copied.
+	Bucket libkv.BucketName
+	// BucketKeys is the number of keys copied from Bucket in this run.
+	BucketKeys int64
+	// TotalKeys is the number of keys copied in this run.
+	TotalKeys int64
+	// Done is true on the last report of a finished bucket.
+	Done bool
+}
+
+// CopyOptions configures Copy.
+type CopyOptions struct {
+	// Buckets to copy, all if empty.
+	Buckets []libkv.BucketName
+	// BatchSize is the number of keys read in one View and written in one Update,
+	// defaults to DefaultCopyBatchSize.
+	BatchSize int
+	// CheckpointBucket in the destination stores the position of the copy,
+	// updated in the same transaction as every batch. A restarted Copy resumes
+	// after the checkpoint. Defaults to the internal bucket _boltkv_copy.
+	CheckpointBucket libkv.BucketName
+	// DisableCheckpoint disables resumability.
+	DisableCheckpoint bool
+	// Progress is called after every batch if set.
+	Progress func(ctx context.Context, progress CopyProgress)
+}
+
+type copyCheckpoint struct {
+	Bucket []byte `json:"bucket"`
+	Key    []byte `json:"key"`
+	Done   bool   `json:"done"`
+}
+
+// Copy copies all keys of the top-level buckets from src to dst. It works on
+// any libkv.DB, nested buckets of backends supporting them are not copied and
+// internal buckets are skipped, see InternalBucketPrefix. Keys are read in
+// chunks, each chunk in its own View on src and written in its own Update on
+// dst, so neither database is locked for the whole copy. After success the
+// checkpoint is removed from dst.
+func Copy(ctx context.Context, src libkv.DB, dst libkv.DB, opts CopyOptions) error {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultCopyBatchSize
+	}
+	if len(opts.CheckpointBucket) == 0 {
+		opts.CheckpointBucket = libkv.NewBucketName(defaultCopyCheckpointBucket)
+	}
+	bucketNames, err := copyBucketNames(ctx, src, opts)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "list buckets failed")
+	}
+	checkpoint, err := readCopyCheckpoint(ctx, dst, opts)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "read checkpoint failed")
+	}
+	c := &copier{
+		src:  src,
+		dst:  dst,
+		opts: opts,
+	}
+	for _, bucketName := range bucketNames {
+		var startKey []byte
+		if checkpoint != nil {
+			switch bytes.Compare(bucketName, checkpoint.Bucket) {
+			case -1:
+				glog.V(3).Infof("skip bucket %s copied before checkpoint", bucketName)
+				continue
+			case 0:
+				if checkpoint.Done {
+					glog.V(3).Infof("skip bucket %s completed at checkpoint", bucketName)
+					continue
+				}
+				startKey = checkpoint.Key
+			}
+		}
+		if err := c.copyBucket(ctx, bucketName, startKey); err != nil {
+			return errors.Wrapf(ctx, err, "copy bucket %s failed", bucketName)
+		}
+	}
+	if opts.DisableCheckpoint {
+		return nil
+	}
+	err = dst.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		return tx.DeleteBucket(ctx, opts.CheckpointBucket)
+	})
+	if err != nil && !errors.Is(err, libkv.BucketNotFoundError) {
+		return errors.Wrapf(ctx, err, "delete checkpoint failed")
+	}
+	return nil
+}
+
+func copyBucketNames(
+	ctx context.Context,
+	src libkv.DB,
+	opts CopyOptions,
+) (libkv.BucketNames, error) {
+	var result libkv.BucketNames
+	if len(opts.Buckets) > 0 {
+		result = append(result, opts.Buckets...)
+	} else {
+		err := src.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucketNames, err := tx.ListBucketNames(ctx)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "list bucket names failed")
+			}
+			for _, bucketName := range bucketNames {
+				if bytes.Equal(bucketName, opts.CheckpointBucket) {
+					continue
+				}
+				result = append(result, bucketName)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "view failed")
+		}
+	}
+	// the checkpoint relies on a stable bucket order
+	sortBucketNames(result)
+	return result, nil
+}
+
+func readCopyCheckpoint(
+	ctx context.Context,
+	dst libkv.DB,
+	opts CopyOptions,
+) (*copyCheckpoint, error) {
+	if opts.DisableCheckpoint {
+		return nil, nil
+	}
+	var checkpoint *copyCheckpoint
+	err := dst.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		bucket, err := tx.Bucket(ctx, opts.CheckpointBucket)
+		if err != nil {
+			if errors.Is(err, libkv.BucketNotFoundError) {
+				return nil
+			}
+			return errors.Wrapf(ctx, err, "get bucket failed")
+		}
+		item, err := bucket.Get(ctx, copyCheckpointKey)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "get checkpoint failed")
+		}
+		if !item.Exists() {
+			return nil
+		}
+		return item.Value(func(value []byte) error {
+			checkpoint = &copyCheckpoint{}
+			return json.Unmarshal(value, checkpoint)
+		})
+	})
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "view failed")
+	}
+	if checkpoint != nil {
+		glog.V(2).Infof("resume copy after bucket %s key %q", checkpoint.Bucket, checkpoint.Key)
+	}
+	return checkpoint, nil
+}
+
+type copyEntry struct {
+	key   []byte
+	value []byte
+}
+
+type copier struct {
+	src        libkv.DB
+	dst        libkv.DB
+	opts       CopyOptions
+	totalCount int64
+}
+
+func (c *copier) copyBucket(
+	ctx context.Context,
+	bucketName libkv.BucketName,
+	startKey []byte,
+) error {
+	var bucketCount int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return errors.Wrapf(ctx, err, "copy canceled")
+		}
+		entries, err := c.readBatch(ctx, bucketName, startKey)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "read batch failed")
+		}
+		done := len(entries) < c.opts.BatchSize
+		if err := c.writeBatch(ctx, bucketName, entries, done); err != nil {
+			return errors.Wrapf(ctx, err, "write batch failed")
+		}
+		bucketCount += int64(len(entries))
+		c.totalCount += int64(len(entries))
+		if c.opts.Progress != nil {
+			c.opts.Progress(ctx, CopyProgress{
+				Bucket:     bucketName,
+				BucketKeys: bucketCount,
+				TotalKeys:  c.totalCount,
+				Done:       done,
+			})
+		}
+		if done {
+			glog.V(2).Infof("copy bucket %s completed (%d keys)", bucketName, bucketCount)
+			return nil
+		}
+		startKey = entries[len(entries)-1].key
+	}
+}
+
+// readBatch returns up to BatchSize entries after startKey, from the beginning if startKey is nil.
+func (c *copier) readBatch(
+	ctx context.Context,
+	bucketName libkv.BucketName,
+	startKey []byte,
+) ([]copyEntry, error) {
+	entries := make([]copyEntry, 0, c.opts.BatchSize)
+	err := c.src.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		bucket, err := tx.Bucket(ctx, bucketName)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "get bucket failed")
+		}
+		it := bucket.Iterator()
+		defer it.Close()
+		if startKey == nil {
+			it.Rewind()
+		} else {
+			it.Seek(startKey)
+			if it.Valid() && bytes.Equal(it.Item().Key(), startKey) {
+				it.Next()
+			}
+		}
+		for ; it.Valid() && len(entries) < c.opts.BatchSize; it.Next() {
+			item := it.Item()
+			err := item.Value(func(value []byte) error {
+				if value == nil {
+					// nested bucket
+					return nil
+				}
+				entries = append(entries, copyEntry{
+					key:   bytes.Clone(item.Key()),
+					value: bytes.Clone(value),
+				})
+				return nil
+			})
+			if err != nil {
+				return errors.Wrapf(ctx, err, "read value failed")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "view failed")
+	}
+	return entries, nil
+}
+
+func (c *copier) writeBatch(
+	ctx context.Context,
+	bucketName libkv.BucketName,
+	entries []copyEntry,
+	done bool,
+) error {
+	return c.dst.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(ctx, bucketName)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "create bucket failed")
+		}
+		for _, entry := range entries {
+			if err := bucket.Put(ctx, entry.key, entry.value); err != nil {
+				return errors.Wrapf(ctx, err, "put failed")
+			}
+		}
+		if c.opts.DisableCheckpoint {
+			return nil
+		}
+		checkpoint := copyCheckpoint{
+			Bucket: bucketName,
+			Done:   done,
+		}
+		if len(entries) > 0 {
+			checkpoint.Key = entries[len(entries)-1].key
+		}
+		value, err := json.Marshal(checkpoint)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "marshal checkpoint failed")
+		}
+		checkpointBucket, err := tx.CreateBucketIfNotExists(ctx, c.opts.CheckpointBucket)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "create checkpoint bucket failed")
+		}
+		return checkpointBucket.Put(ctx, copyCheckpointKey, value)
+	})
+}
+
+func sortBucketNames(bucketNames libkv.BucketNames) {
+	sort.Slice(bucketNames, func(i, j int) bool {
+		return bytes.Compare(bucketNames[i], bucketNames[j]) < 0
+	})
+}
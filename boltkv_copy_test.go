@@ -0,0 +1,141 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv_test
+
+import (
+	"context"
+	"fmt"
+
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bborbe/boltkv"
+)
+
+var _ = Describe("Copy", func() {
+	var ctx context.Context
+	var src boltkv.DB
+	var dst boltkv.DB
+	readAll := func(db libkv.DB) map[string]map[string]string {
+		result := map[string]map[string]string{}
+		Expect(db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucketNames, err := tx.ListBucketNames(ctx)
+			Expect(err).To(BeNil())
+			for _, bucketName := range bucketNames {
+				bucket, err := tx.Bucket(ctx, bucketName)
+				Expect(err).To(BeNil())
+				values := map[string]string{}
+				Expect(libkv.ForEach(ctx, bucket, func(item libkv.Item) error {
+					return item.Value(func(value []byte) error {
+						values[string(item.Key())] = string(value)
+						return nil
+					})
+				})).To(Succeed())
+				result[bucketName.String()] = values
+			}
+			return nil
+		})).To(Succeed())
+		return result
+	}
+	BeforeEach(func() {
+		ctx = context.Background()
+		var err error
+		src, err = boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		dst, err = boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		Expect(src.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			for _, name := range []string{"a", "b"} {
+				bucket, err := tx.CreateBucket(ctx, libkv.NewBucketName(name))
+				Expect(err).To(BeNil())
+				for i := 0; i < 5; i++ {
+					key := []byte(fmt.Sprintf("%s-%d", name, i))
+					Expect(bucket.Put(ctx, key, key)).To(Succeed())
+				}
+			}
+			_, err := tx.CreateBucket(ctx, libkv.NewBucketName("empty"))
+			return err
+		})).To(Succeed())
+	})
+	AfterEach(func() {
+		_ = src.Close()
+		_ = src.Remove()
+		_ = dst.Close()
+		_ = dst.Remove()
+	})
+	It("copies all buckets and removes the checkpoint", func() {
+		var progress []boltkv.CopyProgress
+		err := boltkv.Copy(ctx, src, dst, boltkv.CopyOptions{
+			BatchSize: 2,
+			Progress: func(ctx context.Context, p boltkv.CopyProgress) {
+				progress = append(progress, p)
+			},
+		})
+		Expect(err).To(BeNil())
+		Expect(readAll(dst)).To(Equal(readAll(src)))
+		Expect(progress).To(HaveLen(7))
+		Expect(progress[len(progress)-1]).To(Equal(boltkv.CopyProgress{
+			Bucket:     libkv.NewBucketName("empty"),
+			BucketKeys: 0,
+			TotalKeys:  10,
+			Done:       true,
+		}))
+	})
+	It("skips nested and internal buckets", func() {
+		Expect(src.DB().Update(func(tx *bolt.Tx) error {
+			if _, err := tx.Bucket([]byte("a")).CreateBucket([]byte("nested")); err != nil {
+				return err
+			}
+			internal, err := tx.CreateBucket([]byte("_boltkv_internal"))
+			if err != nil {
+				return err
+			}
+			return internal.Put([]byte("k"), []byte("v"))
+		})).To(Succeed())
+		Expect(boltkv.Copy(ctx, src, dst, boltkv.CopyOptions{})).To(Succeed())
+		Expect(dst.DB().View(func(tx *bolt.Tx) error {
+			Expect(tx.Bucket([]byte("a")).Stats().KeyN).To(Equal(5))
+			Expect(tx.Bucket([]byte("_boltkv_internal"))).To(BeNil())
+			return nil
+		})).To(Succeed())
+	})
+	It("copies only the given buckets", func() {
+		err := boltkv.Copy(ctx, src, dst, boltkv.CopyOptions{
+			Buckets: []libkv.BucketName{libkv.NewBucketName("b")},
+		})
+		Expect(err).To(BeNil())
+		Expect(readAll(dst)).To(HaveLen(1))
+		Expect(readAll(dst)).To(HaveKey("b"))
+	})
+	It("resumes after the checkpoint", func() {
+		cancelCtx, cancel := context.WithCancel(ctx)
+		err := boltkv.Copy(cancelCtx, src, dst, boltkv.CopyOptions{
+			BatchSize: 2,
+			Progress: func(ctx context.Context, p boltkv.CopyProgress) {
+				if p.TotalKeys == 4 {
+					cancel()
+				}
+			},
+		})
+		Expect(err).NotTo(BeNil())
+		Expect(dst.DB().View(func(tx *bolt.Tx) error {
+			Expect(tx.Bucket([]byte("_boltkv_copy"))).NotTo(BeNil())
+			return nil
+		})).To(Succeed())
+
+		var total int64
+		err = boltkv.Copy(ctx, src, dst, boltkv.CopyOptions{
+			BatchSize: 2,
+			Progress: func(ctx context.Context, p boltkv.CopyProgress) {
+				total = p.TotalKeys
+			},
+		})
+		Expect(err).To(BeNil())
+		Expect(total).To(Equal(int64(6)))
+		Expect(readAll(dst)).To(Equal(readAll(src)))
+	})
+})
@@ -8,6 +8,7 @@ import (
 	"context"
 	"os"
 	"path"
+	"time"
 
 	"github.com/bborbe/errors"
 	libkv "github.com/bborbe/kv"
@@ -15,10 +16,13 @@ import (
 	bolt "go.etcd.io/bbolt"
 )
 
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6@v6.12.2 -generate
+
 type contextKey string
 
 const stateCtxKey contextKey = "state"
 
+//counterfeiter:generate -o mocks/boltkv-db.go --fake-name BoltkvDB . DB
 type DB interface {
 	libkv.DB
 	DB() *bolt.DB
@@ -26,52 +30,136 @@ type DB interface {
 
 type ChangeOptions func(opts *bolt.Options)
 
+// DBOptions holds the settings of the boltkv layer on top of bolt.Options.
+type DBOptions struct {
+	// Hook is notified about every operation, defaults to a no-op.
+	Hook Hook
+	// SlowTransactionThreshold enables logging of Update and View calls running
+	// longer than the threshold, including the stack captured at their start.
+	// Zero disables it.
+	SlowTransactionThreshold time.Duration
+	// FingerprintBuckets get a fingerprint maintained on every write, see WithFingerprint.
+	FingerprintBuckets []libkv.BucketName
+	// ReplicationLog records the mutations of every Update, see WithReplicationLog.
+	ReplicationLog bool
+	// RemoveOnClose deletes the database file after Close, see WithRemoveOnClose.
+	RemoveOnClose bool
+}
+
+// ChangeDBOptions modifies DBOptions, see NewDB.
+type ChangeDBOptions func(opts *DBOptions)
+
+// WithHook registers the given hook on the DB.
+func WithHook(hook Hook) ChangeDBOptions {
+	return func(opts *DBOptions) {
+		opts.Hook = hook
+	}
+}
+
+// WithSlowTransactionThreshold sets DBOptions.SlowTransactionThreshold.
+func WithSlowTransactionThreshold(threshold time.Duration) ChangeDBOptions {
+	return func(opts *DBOptions) {
+		opts.SlowTransactionThreshold = threshold
+	}
+}
+
+// OpenOptions configures OpenFileWithOptions and OpenDirWithOptions.
+type OpenOptions struct {
+	// BoltOptions change a copy of bolt.DefaultOptions.
+	BoltOptions []ChangeOptions
+	// DBOptions configure the boltkv layer, e.g. WithHook or WithFingerprint.
+	DBOptions []ChangeDBOptions
+}
+
 func OpenFile(ctx context.Context, path string, fn ...ChangeOptions) (DB, error) {
+	return OpenFileWithOptions(ctx, path, OpenOptions{BoltOptions: fn})
+}
+
+// OpenFileWithOptions opens the database file at path with the bolt and boltkv options.
+func OpenFileWithOptions(ctx context.Context, path string, opts OpenOptions) (DB, error) {
 	options := *bolt.DefaultOptions
-	for _, f := range fn {
+	for _, f := range opts.BoltOptions {
 		f(&options)
 	}
 	db, err := bolt.Open(path, 0600, &options)
 	if err != nil {
 		return nil, errors.Wrapf(ctx, err, "open %s failed", path)
 	}
-	return NewDB(db), nil
+	return NewDB(db, opts.DBOptions...), nil
 }
 
 func OpenDir(ctx context.Context, dir string, fn ...ChangeOptions) (DB, error) {
+	return OpenDirWithOptions(ctx, dir, OpenOptions{BoltOptions: fn})
+}
+
+// OpenDirWithOptions opens bolt.db in dir, created if missing, with the bolt and boltkv options.
+func OpenDirWithOptions(ctx context.Context, dir string, opts OpenOptions) (DB, error) {
 	if _, err := os.Stat(dir); os.IsNotExist(err) {
 		glog.V(4).Infof("dir '%s' does exists => create", dir)
 		if err := os.MkdirAll(dir, 0700); err != nil {
 			return nil, err
 		}
 	}
-	return OpenFile(ctx, path.Join(dir, "bolt.db"), fn...)
+	return OpenFileWithOptions(ctx, path.Join(dir, "bolt.db"), opts)
 }
 
-func OpenTemp(ctx context.Context, fn ...ChangeOptions) (DB, error) {
-	file, err := os.CreateTemp("", "")
-	if err != nil {
-		return nil, err
+// WithRemoveOnClose deletes the database file after Close.
+func WithRemoveOnClose() ChangeDBOptions {
+	return func(opts *DBOptions) {
+		opts.RemoveOnClose = true
 	}
-	return OpenFile(ctx, file.Name(), fn...)
 }
 
-func NewDB(db *bolt.DB) DB {
+// NewDB wraps an already opened bolt database. To open and configure a
+// database in one step use OpenFileWithOptions, OpenDirWithOptions or
+// OpenTempWithOptions with DBOptions.
+func NewDB(db *bolt.DB, fn ...ChangeDBOptions) DB {
+	options := DBOptions{
+		Hook: noopHook{},
+	}
+	for _, f := range fn {
+		f(&options)
+	}
+	if options.Hook == nil {
+		options.Hook = noopHook{}
+	}
+	fingerprints := make(map[string]bool, len(options.FingerprintBuckets))
+	for _, bucketName := range options.FingerprintBuckets {
+		fingerprints[string(bucketName)] = true
+	}
 	return &boltdb{
-		db:   db,
-		path: db.Path(),
+		fingerprints:   fingerprints,
+		replicationLog: options.ReplicationLog,
+		removeOnClose:  options.RemoveOnClose,
+		db:             db,
+		path:           db.Path(),
+		hook:           options.Hook,
+		transactions:   newTransactionRegistry(options.SlowTransactionThreshold, options.Hook),
 	}
 }
 
 type boltdb struct {
-	db   *bolt.DB
-	path string
+	db             *bolt.DB
+	path           string
+	hook           Hook
+	transactions   *transactionRegistry
+	fingerprints   map[string]bool
+	replicationLog bool
+	removeOnClose  bool
 }
 
 func (b *boltdb) DB() *bolt.DB {
 	return b.db
 }
 
+func (b *boltdb) openTransactions() []TransactionInfo {
+	return b.transactions.List()
+}
+
+func (b *boltdb) fingerprinted(bucketName libkv.BucketName) bool {
+	return b.fingerprints[string(bucketName)]
+}
+
 func (b *boltdb) Sync() error {
 	return b.db.Sync()
 }
@@ -80,23 +168,42 @@ func (b *boltdb) Close() error {
 	if b.db.NoSync {
 		_ = b.db.Sync()
 	}
-	return b.db.Close()
+	if err := b.db.Close(); err != nil {
+		return err
+	}
+	if b.removeOnClose {
+		return b.Remove()
+	}
+	return nil
 }
 
 func (b *boltdb) Update( //nolint:dupl
 	ctx context.Context,
 	fn func(ctx context.Context, tx libkv.Tx) error,
-) error {
+) (err error) {
 	glog.V(4).Infof("db update started")
 	if IsTransactionOpen(ctx) {
 		return errors.Wrapf(ctx, libkv.TransactionAlreadyOpenError, "transaction already open")
 	}
-	err := b.db.Update(func(tx *bolt.Tx) error {
+	ctx = b.hook.Start(ctx, OperationUpdate, nil)
+	defer func() {
+		b.hook.End(ctx, OperationUpdate, nil, err)
+	}()
+	err = b.db.Update(func(tx *bolt.Tx) error {
 		glog.V(4).Infof("db update started")
-		ctx = SetOpenState(ctx)
-		if err := fn(ctx, NewTx(tx)); err != nil {
+		info := b.transactions.Start(OperationUpdate)
+		defer b.transactions.End(ctx, info)
+		ctx := SetOpenState(ctx)
+		var recorder *replicationRecorder
+		if b.replicationLog {
+			recorder = &replicationRecorder{}
+		}
+		if err := fn(ctx, newTx(tx, b.hook, b.fingerprints, recorder)); err != nil {
 			return errors.Wrapf(ctx, err, "db update failed")
 		}
+		if err := recorder.append(tx); err != nil {
+			return errors.Wrapf(ctx, err, "append replication log failed")
+		}
 		glog.V(4).Infof("db update completed")
 		return nil
 	})
@@ -110,15 +217,21 @@ func (b *boltdb) Update( //nolint:dupl
 func (b *boltdb) View( //nolint:dupl
 	ctx context.Context,
 	fn func(ctx context.Context, tx libkv.Tx) error,
-) error {
+) (err error) {
 	glog.V(4).Infof("db view started")
 	if IsTransactionOpen(ctx) {
 		return errors.Wrapf(ctx, libkv.TransactionAlreadyOpenError, "transaction already open")
 	}
-	err := b.db.View(func(tx *bolt.Tx) error {
+	ctx = b.hook.Start(ctx, OperationView, nil)
+	defer func() {
+		b.hook.End(ctx, OperationView, nil, err)
+	}()
+	err = b.db.View(func(tx *bolt.Tx) error {
 		glog.V(4).Infof("db view started")
-		ctx = SetOpenState(ctx)
-		if err := fn(ctx, NewTx(tx)); err != nil {
+		info := b.transactions.Start(OperationView)
+		defer b.transactions.End(ctx, info)
+		ctx := SetOpenState(ctx)
+		if err := fn(ctx, newTx(tx, b.hook, b.fingerprints, nil)); err != nil {
 			return errors.Wrapf(ctx, err, "db view failed")
 		}
 		glog.V(4).Infof("db view completed")
@@ -132,7 +245,12 @@ func (b *boltdb) View( //nolint:dupl
 }
 
 func (b *boltdb) Remove() error {
-	return os.Remove(b.path)
+	err := os.Remove(b.path)
+	if b.removeOnClose && os.IsNotExist(err) {
+		// already removed by Close
+		return nil
+	}
+	return err
 }
 
 func IsTransactionOpen(ctx context.Context) bool {
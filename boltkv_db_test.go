@@ -13,6 +13,7 @@ import (
 	libkv "github.com/bborbe/kv"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	bolt "go.etcd.io/bbolt"
 
 	"github.com/bborbe/boltkv"
 )
@@ -100,6 +101,46 @@ var _ = Describe("DB", func() {
 			Expect(fileExists(tempDir)).To(BeTrue())
 		})
 	})
+	Context("OpenDirWithOptions", func() {
+		var tempDir string
+		var puts int
+		BeforeEach(func() {
+			tempDir = GinkgoT().TempDir()
+			puts = 0
+			db, err = boltkv.OpenDirWithOptions(ctx, tempDir, boltkv.OpenOptions{
+				BoltOptions: []boltkv.ChangeOptions{func(opts *bolt.Options) {
+					opts.NoSync = true
+				}},
+				DBOptions: []boltkv.ChangeDBOptions{boltkv.WithHook(boltkv.HookFuncs{
+					EndFunc: func(
+						ctx context.Context,
+						op boltkv.Operation,
+						name libkv.BucketName,
+						err error,
+					) {
+						if op == boltkv.OperationPut {
+							puts++
+						}
+					},
+				})},
+			})
+			Expect(err).To(BeNil())
+		})
+		AfterEach(func() {
+			_ = db.Close()
+		})
+		It("applies bolt and boltkv options", func() {
+			Expect(db.DB().NoSync).To(BeTrue())
+			Expect(db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+				bucket, err := tx.CreateBucket(ctx, libkv.NewBucketName("a"))
+				if err != nil {
+					return err
+				}
+				return bucket.Put(ctx, []byte("k"), []byte("v"))
+			})).To(Succeed())
+			Expect(puts).To(Equal(1))
+		})
+	})
 	Context("OpenTemp", func() {
 		JustBeforeEach(func() {
 			db, err = boltkv.OpenTemp(ctx)
@@ -0,0 +1,450 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+)
+
+// DiffType is the kind of difference of a key.
+type DiffType string
+
+const (
+	// DiffTypeAdded marks a key only existing in the new database.
+	DiffTypeAdded DiffType = "added"
+	// DiffTypeRemoved marks a key only existing in the old database.
+	DiffTypeRemoved DiffType = "removed"
+	// DiffTypeChanged marks a key with different values.
+	DiffTypeChanged DiffType = "changed"
+)
+
+// DefaultApplyDiffBatchSize is the number of records ApplyDiff writes per Update.
+const DefaultApplyDiffBatchSize = 1000
+
+// DiffOptions configures Diff.
+type DiffOptions struct {
+	// Buckets to compare, all buckets of both databases if empty.
+	Buckets []libkv.BucketName
+	// HashValues reports SHA-256 hashes instead of values,
+	// so large values are not copied. Such diffs cannot be applied.
+	HashValues bool
+}
+
+// DiffEntry is a key differing between the old and the new database.
+type DiffEntry struct {
+	Type   DiffType
+	Bucket libkv.BucketName
+	Key    []byte
+	// OldValue is the value in the old database, nil for added keys or with HashValues.
+	OldValue []byte
+	// NewValue is the value in the new database, nil for removed keys or with HashValues.
+	NewValue []byte
+	// OldHash and NewHash are the SHA-256 of the values if HashValues is set.
+	OldHash []byte
+	NewHash []byte
+}
+
+// DiffResult counts the differences found by Diff.
+type DiffResult struct {
+	Added   int64
+	Removed int64
+	Changed int64
+}
+
+// Equal returns true if no difference was found.
+func (d DiffResult) Equal() bool {
+	return d.Added == 0 && d.Removed == 0 && d.Changed == 0
+}
+
+// Diff compares the top-level buckets of oldDB and newDB and calls fn for every
+// differing key, ordered by bucket and key. Keys of nested buckets are skipped.
+// Both databases are read in a single View each by merging their ordered
+// iterators, so memory does not grow with the bucket size. A bucket missing on
+// one side is compared as empty bucket.
+func Diff(
+	ctx context.Context,
+	oldDB libkv.DB,
+	newDB libkv.DB,
+	opts DiffOptions,
+	fn func(ctx context.Context, entry DiffEntry) error,
+) (DiffResult, error) {
+	var result DiffResult
+	err := oldDB.View(ctx, func(oldCtx context.Context, oldTx libkv.Tx) error {
+		// the outer ctx is used, the other database has no open transaction
+		return newDB.View(ctx, func(newCtx context.Context, newTx libkv.Tx) error {
+			bucketNames, err := diffBucketNames(oldCtx, oldTx, newTx, opts.Buckets)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "list buckets failed")
+			}
+			d := &differ{opts: opts, fn: fn, result: &result}
+			for _, bucketName := range bucketNames {
+				if err := d.diffBucket(newCtx, oldTx, newTx, bucketName); err != nil {
+					return errors.Wrapf(ctx, err, "diff bucket %s failed", bucketName)
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return result, errors.Wrapf(ctx, err, "diff failed")
+	}
+	return result, nil
+}
+
+func diffBucketNames(
+	ctx context.Context,
+	oldTx libkv.Tx,
+	newTx libkv.Tx,
+	buckets []libkv.BucketName,
+) (libkv.BucketNames, error) {
+	if len(buckets) > 0 {
+		result := append(libkv.BucketNames{}, buckets...)
+		sortBucketNames(result)
+		return result, nil
+	}
+	seen := map[string]bool{}
+	var result libkv.BucketNames
+	for _, tx := range []libkv.Tx{oldTx, newTx} {
+		bucketNames, err := tx.ListBucketNames(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "list bucket names failed")
+		}
+		for _, bucketName := range bucketNames {
+			if seen[string(bucketName)] {
+				continue
+			}
+			seen[string(bucketName)] = true
+			result = append(result, bucketName)
+		}
+	}
+	sortBucketNames(result)
+	return result, nil
+}
+
+type differ struct {
+	opts   DiffOptions
+	fn     func(ctx context.Context, entry DiffEntry) error
+	result *DiffResult
+}
+
+func (d *differ) diffBucket(
+	ctx context.Context,
+	oldTx libkv.Tx,
+	newTx libkv.Tx,
+	bucketName libkv.BucketName,
+) error {
+	oldIt, err := diffIterator(ctx, oldTx, bucketName)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "open old bucket failed")
+	}
+	defer oldIt.Close()
+	newIt, err := diffIterator(ctx, newTx, bucketName)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "open new bucket failed")
+	}
+	defer newIt.Close()
+	for oldIt.Valid() || newIt.Valid() {
+		if err := ctx.Err(); err != nil {
+			return errors.Wrapf(ctx, err, "diff canceled")
+		}
+		skipped, err := skipNestedBucket(ctx, oldIt, newIt)
+		if err != nil {
+			return err
+		}
+		if skipped {
+			continue
+		}
+		var cmp int
+		switch {
+		case !newIt.Valid():
+			cmp = -1
+		case !oldIt.Valid():
+			cmp = 1
+		default:
+			cmp = bytes.Compare(oldIt.Item().Key(), newIt.Item().Key())
+		}
+		switch {
+		case cmp < 0:
+			err = d.report(ctx, DiffTypeRemoved, bucketName, oldIt.Item(), nil)
+			oldIt.Next()
+		case cmp > 0:
+			err = d.report(ctx, DiffTypeAdded, bucketName, nil, newIt.Item())
+			newIt.Next()
+		default:
+			err = d.compare(ctx, bucketName, oldIt.Item(), newIt.Item())
+			oldIt.Next()
+			newIt.Next()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skipNestedBucket moves the iterators past a key of a nested bucket, which
+// has a nil value. Nested buckets are not compared, like by Copy.
+func skipNestedBucket(ctx context.Context, iterators ...libkv.Iterator) (bool, error) {
+	for _, it := range iterators {
+		if !it.Valid() {
+			continue
+		}
+		var nested bool
+		err := it.Item().Value(func(value []byte) error {
+			nested = value == nil
+			return nil
+		})
+		if err != nil {
+			return false, errors.Wrapf(ctx, err, "read value failed")
+		}
+		if nested {
+			it.Next()
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// diffIterator returns an iterator at the first key, an empty one if the bucket does not exist.
+func diffIterator(
+	ctx context.Context,
+	tx libkv.Tx,
+	bucketName libkv.BucketName,
+) (libkv.Iterator, error) {
+	bucket, err := tx.Bucket(ctx, bucketName)
+	if errors.Is(err, libkv.BucketNotFoundError) {
+		return emptyIterator{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "get bucket failed")
+	}
+	it := bucket.Iterator()
+	it.Rewind()
+	return it, nil
+}
+
+func (d *differ) compare(
+	ctx context.Context,
+	bucketName libkv.BucketName,
+	oldItem libkv.Item,
+	newItem libkv.Item,
+) error {
+	var equal bool
+	err := oldItem.Value(func(oldValue []byte) error {
+		return newItem.Value(func(newValue []byte) error {
+			equal = bytes.Equal(oldValue, newValue)
+			return nil
+		})
+	})
+	if err != nil {
+		return errors.Wrapf(ctx, err, "read values failed")
+	}
+	if equal {
+		return nil
+	}
+	return d.report(ctx, DiffTypeChanged, bucketName, oldItem, newItem)
+}
+
+func (d *differ) report(
+	ctx context.Context,
+	diffType DiffType,
+	bucketName libkv.BucketName,
+	oldItem libkv.Item,
+	newItem libkv.Item,
+) error {
+	entry := DiffEntry{
+		Type:   diffType,
+		Bucket: bucketName,
+	}
+	read := func(item libkv.Item, value *[]byte, hash *[]byte) error {
+		if item == nil {
+			return nil
+		}
+		entry.Key = bytes.Clone(item.Key())
+		return item.Value(func(v []byte) error {
+			if d.opts.HashValues {
+				sum := sha256.Sum256(v)
+				*hash = sum[:]
+				return nil
+			}
+			*value = bytes.Clone(v)
+			return nil
+		})
+	}
+	if err := read(oldItem, &entry.OldValue, &entry.OldHash); err != nil {
+		return errors.Wrapf(ctx, err, "read old value failed")
+	}
+	if err := read(newItem, &entry.NewValue, &entry.NewHash); err != nil {
+		return errors.Wrapf(ctx, err, "read new value failed")
+	}
+	switch diffType {
+	case DiffTypeAdded:
+		d.result.Added++
+	case DiffTypeRemoved:
+		d.result.Removed++
+	case DiffTypeChanged:
+		d.result.Changed++
+	}
+	return d.fn(ctx, entry)
+}
+
+type emptyIterator struct{}
+
+func (emptyIterator) Close()           {}
+func (emptyIterator) Item() libkv.Item { return nil }
+func (emptyIterator) Next()            {}
+func (emptyIterator) Valid() bool      { return false }
+func (emptyIterator) Rewind()          {}
+func (emptyIterator) Seek(key []byte)  {}
+
+// DiffRecord is one line of the JSON Lines diff format written by WriteDiff.
+// Value holds the new value, OldValue the old value, both encoded with Encoding.
+type DiffRecord struct {
+	Type     DiffType `json:"type"`
+	Bucket   string   `json:"bucket"`
+	Key      string   `json:"key"`
+	OldValue *string  `json:"old_value,omitempty"`
+	Value    *string  `json:"value,omitempty"`
+	OldHash  string   `json:"old_hash,omitempty"`
+	NewHash  string   `json:"new_hash,omitempty"`
+	Encoding Encoding `json:"encoding"`
+}
+
+// NewDiffRecord encodes entry, with base64 if any part is not valid UTF-8.
+func NewDiffRecord(entry DiffEntry) DiffRecord {
+	encoding := recordEncoding(entry.Bucket, entry.Key, entry.OldValue, entry.NewValue)
+	record := DiffRecord{
+		Type:     entry.Type,
+		Bucket:   encoding.encode(entry.Bucket),
+		Key:      encoding.encode(entry.Key),
+		OldHash:  hex.EncodeToString(entry.OldHash),
+		NewHash:  hex.EncodeToString(entry.NewHash),
+		Encoding: encoding,
+	}
+	if entry.OldValue != nil {
+		value := encoding.encode(entry.OldValue)
+		record.OldValue = &value
+	}
+	if entry.NewValue != nil {
+		value := encoding.encode(entry.NewValue)
+		record.Value = &value
+	}
+	return record
+}
+
+// WriteDiff runs Diff and writes every entry as DiffRecord line to w.
+func WriteDiff(
+	ctx context.Context,
+	oldDB libkv.DB,
+	newDB libkv.DB,
+	opts DiffOptions,
+	w io.Writer,
+) (DiffResult, error) {
+	bw := bufio.NewWriter(w)
+	encoder := json.NewEncoder(bw)
+	result, err := Diff(ctx, oldDB, newDB, opts, func(ctx context.Context, entry DiffEntry) error {
+		return encoder.Encode(NewDiffRecord(entry))
+	})
+	if err != nil {
+		return result, err
+	}
+	if err := bw.Flush(); err != nil {
+		return result, errors.Wrapf(ctx, err, "flush failed")
+	}
+	return result, nil
+}
+
+// ApplyDiff reads DiffRecord lines from r and applies them to db in Updates
+// of batchSize records: added and changed keys are written with the new value,
+// removed keys are deleted. Applying a diff of old and new to old yields new.
+// It returns the number of applied records.
+func ApplyDiff(ctx context.Context, db libkv.DB, r io.Reader, batchSize int) (int64, error) {
+	if batchSize <= 0 {
+		batchSize = DefaultApplyDiffBatchSize
+	}
+	var applied int64
+	decoder := json.NewDecoder(bufio.NewReader(r))
+	batch := make([]DiffRecord, 0, batchSize)
+	for {
+		var record DiffRecord
+		err := decoder.Decode(&record)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return applied, errors.Wrapf(ctx, err, "decode diff record failed")
+		}
+		batch = append(batch, record)
+		if len(batch) < batchSize {
+			continue
+		}
+		if err := applyDiffBatch(ctx, db, batch); err != nil {
+			return applied, errors.Wrapf(ctx, err, "apply batch failed")
+		}
+		applied += int64(len(batch))
+		batch = batch[:0]
+	}
+	if err := applyDiffBatch(ctx, db, batch); err != nil {
+		return applied, errors.Wrapf(ctx, err, "apply batch failed")
+	}
+	return applied + int64(len(batch)), nil
+}
+
+func applyDiffBatch(ctx context.Context, db libkv.DB, batch []DiffRecord) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	return db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		for _, record := range batch {
+			decode := func(value string) ([]byte, error) {
+				return record.Encoding.Decode(ctx, value)
+			}
+			bucketName, err := decode(record.Bucket)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "decode bucket failed")
+			}
+			key, err := decode(record.Key)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "decode key failed")
+			}
+			bucket, err := tx.CreateBucketIfNotExists(ctx, bucketName)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "create bucket failed")
+			}
+			switch record.Type {
+			case DiffTypeRemoved:
+				err = bucket.Delete(ctx, key)
+			case DiffTypeAdded, DiffTypeChanged:
+				if record.Value == nil {
+					return errors.Errorf(
+						ctx,
+						"record of key %q has no value, diffs with hashed values cannot be applied",
+						record.Key,
+					)
+				}
+				var value []byte
+				if value, err = decode(*record.Value); err != nil {
+					return errors.Wrapf(ctx, err, "decode value failed")
+				}
+				err = bucket.Put(ctx, key, value)
+			default:
+				return errors.Errorf(ctx, "unknown diff type '%s'", record.Type)
+			}
+			if err != nil {
+				return errors.Wrapf(ctx, err, "apply %s %q failed", record.Type, record.Key)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"strings"
+
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bborbe/boltkv"
+)
+
+var _ = Describe("Diff", func() {
+	var ctx context.Context
+	var oldDB boltkv.DB
+	var newDB boltkv.DB
+	put := func(db libkv.DB, bucketName string, pairs ...string) {
+		Expect(db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.CreateBucketIfNotExists(ctx, libkv.NewBucketName(bucketName))
+			Expect(err).To(BeNil())
+			for i := 0; i < len(pairs); i += 2 {
+				Expect(bucket.Put(ctx, []byte(pairs[i]), []byte(pairs[i+1]))).To(Succeed())
+			}
+			return nil
+		})).To(Succeed())
+	}
+	diff := func(opts boltkv.DiffOptions) ([]boltkv.DiffEntry, boltkv.DiffResult) {
+		var entries []boltkv.DiffEntry
+		fn := func(ctx context.Context, entry boltkv.DiffEntry) error {
+			entries = append(entries, entry)
+			return nil
+		}
+		result, err := boltkv.Diff(ctx, oldDB, newDB, opts, fn)
+		Expect(err).To(BeNil())
+		return entries, result
+	}
+	BeforeEach(func() {
+		ctx = context.Background()
+		var err error
+		oldDB, err = boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		newDB, err = boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		put(oldDB, "a", "same", "1", "changed", "old", "removed", "x")
+		put(newDB, "a", "same", "1", "changed", "new", "added", "y")
+		put(oldDB, "only-old", "k", "v")
+		put(newDB, "only-new", "k", "v")
+	})
+	AfterEach(func() {
+		_ = oldDB.Close()
+		_ = oldDB.Remove()
+		_ = newDB.Close()
+		_ = newDB.Remove()
+	})
+	It("reports added, removed and changed keys in order", func() {
+		entries, result := diff(boltkv.DiffOptions{})
+		Expect(result).To(Equal(boltkv.DiffResult{Added: 2, Removed: 2, Changed: 1}))
+		Expect(result.Equal()).To(BeFalse())
+		entry := func(
+			diffType boltkv.DiffType,
+			bucket, key string,
+			oldValue, newValue []byte,
+		) boltkv.DiffEntry {
+			return boltkv.DiffEntry{
+				Type:     diffType,
+				Bucket:   libkv.NewBucketName(bucket),
+				Key:      []byte(key),
+				OldValue: oldValue,
+				NewValue: newValue,
+			}
+		}
+		Expect(entries).To(Equal([]boltkv.DiffEntry{
+			entry(boltkv.DiffTypeAdded, "a", "added", nil, []byte("y")),
+			entry(boltkv.DiffTypeChanged, "a", "changed", []byte("old"), []byte("new")),
+			entry(boltkv.DiffTypeRemoved, "a", "removed", []byte("x"), nil),
+			entry(boltkv.DiffTypeAdded, "only-new", "k", nil, []byte("v")),
+			entry(boltkv.DiffTypeRemoved, "only-old", "k", []byte("v"), nil),
+		}))
+	})
+	It("limits the diff to buckets", func() {
+		entries, _ := diff(boltkv.DiffOptions{
+			Buckets: []libkv.BucketName{libkv.NewBucketName("only-old")},
+		})
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0].Type).To(Equal(boltkv.DiffTypeRemoved))
+	})
+	It("reports hashes instead of values", func() {
+		entries, _ := diff(boltkv.DiffOptions{
+			Buckets:    []libkv.BucketName{libkv.NewBucketName("a")},
+			HashValues: true,
+		})
+		Expect(entries[1].OldValue).To(BeNil())
+		Expect(entries[1].NewValue).To(BeNil())
+		oldHash := sha256.Sum256([]byte("old"))
+		Expect(entries[1].OldHash).To(Equal(oldHash[:]))
+	})
+	It("finds no differences in equal databases", func() {
+		_, result := diff(boltkv.DiffOptions{
+			Buckets: []libkv.BucketName{libkv.NewBucketName("missing")},
+		})
+		Expect(result.Equal()).To(BeTrue())
+	})
+	It("applies a written diff", func() {
+		buf := &bytes.Buffer{}
+		_, err := boltkv.WriteDiff(ctx, oldDB, newDB, boltkv.DiffOptions{}, buf)
+		Expect(err).To(BeNil())
+		Expect(strings.Split(buf.String(), "\n")[0]).To(Equal(
+			`{"type":"added","bucket":"a","key":"added","value":"y","encoding":"utf8"}`,
+		))
+		applied, err := boltkv.ApplyDiff(ctx, oldDB, buf, 2)
+		Expect(err).To(BeNil())
+		Expect(applied).To(Equal(int64(5)))
+		entries, _ := diff(boltkv.DiffOptions{})
+		Expect(entries).To(BeEmpty())
+	})
+	It("skips nested and internal buckets", func() {
+		Expect(oldDB.DB().Update(func(tx *bolt.Tx) error {
+			if _, err := tx.Bucket([]byte("a")).CreateBucket([]byte("nested")); err != nil {
+				return err
+			}
+			internal, err := tx.CreateBucket([]byte("_boltkv_internal"))
+			if err != nil {
+				return err
+			}
+			return internal.Put([]byte("k"), []byte("v"))
+		})).To(Succeed())
+		buf := &bytes.Buffer{}
+		result, err := boltkv.WriteDiff(ctx, oldDB, newDB, boltkv.DiffOptions{}, buf)
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal(boltkv.DiffResult{Added: 2, Removed: 2, Changed: 1}))
+		_, err = boltkv.ApplyDiff(ctx, oldDB, buf, 0)
+		Expect(err).To(BeNil())
+	})
+	It("encodes binary data as base64", func() {
+		record := boltkv.NewDiffRecord(boltkv.DiffEntry{
+			Type:     boltkv.DiffTypeChanged,
+			Bucket:   libkv.NewBucketName("a"),
+			Key:      []byte("k"),
+			OldValue: []byte{0xff},
+			NewValue: []byte("v"),
+		})
+		Expect(record.Encoding).To(Equal(boltkv.EncodingBase64))
+		Expect(record.Key).To(Equal("aw=="))
+		Expect(*record.OldValue).To(Equal("/w=="))
+	})
+	It("refuses to apply hashed diffs", func() {
+		buf := &bytes.Buffer{}
+		_, err := boltkv.WriteDiff(ctx, oldDB, newDB, boltkv.DiffOptions{HashValues: true}, buf)
+		Expect(err).To(BeNil())
+		_, err = boltkv.ApplyDiff(ctx, oldDB, buf, 0)
+		Expect(err).To(MatchError(ContainSubstring("cannot be applied")))
+	})
+})
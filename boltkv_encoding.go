@@ -0,0 +1,121 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bborbe/errors"
+)
+
+// Encoding describes how bytes are represented as text, e.g. in a Record,
+// a DiffRecord, the CLI and the HTTP server.
+type Encoding string
+
+const (
+	EncodingUTF8   Encoding = "utf8"
+	EncodingHex    Encoding = "hex"
+	EncodingBase64 Encoding = "base64"
+	// EncodingUint64 represents 8 byte keys as big-endian unsigned integer, used for sequences.
+	EncodingUint64 Encoding = "uint64"
+)
+
+// KeyEncodings contains all encodings supported for keys.
+var KeyEncodings = []Encoding{EncodingUTF8, EncodingHex, EncodingUint64}
+
+// ParseKeyEncoding returns the key Encoding for the given name.
+func ParseKeyEncoding(ctx context.Context, name string) (Encoding, error) {
+	for _, encoding := range KeyEncodings {
+		if string(encoding) == name {
+			return encoding, nil
+		}
+	}
+	return "", errors.Errorf(
+		ctx,
+		"unknown key encoding '%s', expected one of %v",
+		name,
+		KeyEncodings,
+	)
+}
+
+// Decode returns the bytes represented by value.
+func (e Encoding) Decode(ctx context.Context, value string) ([]byte, error) {
+	switch e {
+	case EncodingUTF8, "":
+		return []byte(value), nil
+	case EncodingHex:
+		result, err := hex.DecodeString(value)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "decode hex '%s' failed", value)
+		}
+		return result, nil
+	case EncodingBase64:
+		result, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "decode base64 '%s' failed", value)
+		}
+		return result, nil
+	case EncodingUint64:
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "decode uint64 '%s' failed", value)
+		}
+		return binary.BigEndian.AppendUint64(nil, n), nil
+	default:
+		return nil, errors.Errorf(ctx, "unknown encoding '%s'", e)
+	}
+}
+
+// Encode returns value as text and the encoding actually used.
+// Values not representable in e fall back to base64 for utf8 and to hex for uint64.
+func (e Encoding) Encode(value []byte) (string, Encoding) {
+	switch e {
+	case EncodingHex:
+		return hex.EncodeToString(value), EncodingHex
+	case EncodingBase64:
+		return base64.StdEncoding.EncodeToString(value), EncodingBase64
+	case EncodingUint64:
+		if len(value) != 8 {
+			return hex.EncodeToString(value), EncodingHex
+		}
+		return strconv.FormatUint(binary.BigEndian.Uint64(value), 10), EncodingUint64
+	default:
+		if !utf8.Valid(value) {
+			return base64.StdEncoding.EncodeToString(value), EncodingBase64
+		}
+		return string(value), EncodingUTF8
+	}
+}
+
+// encode returns value as text, see Encode.
+func (e Encoding) encode(value []byte) string {
+	result, _ := e.Encode(value)
+	return result
+}
+
+// Text returns value encoded with e for human readers.
+// Invalid UTF-8 and control characters other than tab and newline are printed as
+// quoted Go string instead.
+func (e Encoding) Text(value []byte) string {
+	result, encoding := e.Encode(value)
+	if encoding == EncodingBase64 && e != EncodingBase64 {
+		return strconv.Quote(string(value))
+	}
+	if encoding == EncodingUTF8 && strings.IndexFunc(result, isControl) >= 0 {
+		return strconv.Quote(result)
+	}
+	return result
+}
+
+func isControl(r rune) bool {
+	return r != '\t' && r != '\n' && unicode.IsControl(r)
+}
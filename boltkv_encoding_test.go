@@ -0,0 +1,66 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+)
+
+var _ = Describe("Encoding", func() {
+	var ctx context.Context
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+	DescribeTable("Decode",
+		func(encoding boltkv.Encoding, value string, expected []byte) {
+			result, err := encoding.Decode(ctx, value)
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal(expected))
+		},
+		Entry("utf8", boltkv.EncodingUTF8, "abc", []byte("abc")),
+		Entry("hex", boltkv.EncodingHex, "ff00", []byte{0xff, 0}),
+		Entry("base64", boltkv.EncodingBase64, "/wA=", []byte{0xff, 0}),
+		Entry("uint64", boltkv.EncodingUint64, "258", []byte{0, 0, 0, 0, 0, 0, 1, 2}),
+	)
+	DescribeTable("Decode invalid",
+		func(encoding boltkv.Encoding, value string) {
+			_, err := encoding.Decode(ctx, value)
+			Expect(err).NotTo(BeNil())
+		},
+		Entry("hex", boltkv.EncodingHex, "xyz"),
+		Entry("base64", boltkv.EncodingBase64, "%%%"),
+		Entry("uint64", boltkv.EncodingUint64, "-1"),
+	)
+	DescribeTable("Encode",
+		func(encoding boltkv.Encoding, value []byte, expected string, used boltkv.Encoding) {
+			result, resultEncoding := encoding.Encode(value)
+			Expect(result).To(Equal(expected))
+			Expect(resultEncoding).To(Equal(used))
+		},
+		Entry("utf8", boltkv.EncodingUTF8, []byte("abc"), "abc", boltkv.EncodingUTF8),
+		Entry("utf8 binary", boltkv.EncodingUTF8, []byte{0xff}, "/w==", boltkv.EncodingBase64),
+		Entry("hex", boltkv.EncodingHex, []byte{0xff}, "ff", boltkv.EncodingHex),
+		Entry(
+			"uint64",
+			boltkv.EncodingUint64,
+			[]byte{0, 0, 0, 0, 0, 0, 1, 2},
+			"258",
+			boltkv.EncodingUint64,
+		),
+		Entry("uint64 short", boltkv.EncodingUint64, []byte{1, 2}, "0102", boltkv.EncodingHex),
+	)
+	It("parses key encodings", func() {
+		encoding, err := boltkv.ParseKeyEncoding(ctx, "uint64")
+		Expect(err).To(BeNil())
+		Expect(encoding).To(Equal(boltkv.EncodingUint64))
+		_, err = boltkv.ParseKeyEncoding(ctx, "base64")
+		Expect(err).NotTo(BeNil())
+	})
+})
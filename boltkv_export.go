@@ -0,0 +1,160 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"unicode/utf8"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Record is one line of the JSON Lines export format.
+// A record without key marks a (possibly empty) bucket, all other records
+// hold one key/value pair. Bucket is the path from the top-level bucket to
+// the nested bucket containing the key.
+type Record struct {
+	Bucket   []string `json:"bucket"`
+	Key      *string  `json:"key,omitempty"`
+	Value    string   `json:"value,omitempty"`
+	Encoding Encoding `json:"encoding"`
+}
+
+// IsBucket returns true if the record marks a bucket instead of a key/value pair.
+func (r Record) IsBucket() bool {
+	return r.Key == nil
+}
+
+// Decode returns the raw bucket path, key and value of the record.
+func (r Record) Decode(ctx context.Context) ([][]byte, []byte, []byte, error) {
+	decode := func(value string) ([]byte, error) {
+		return r.Encoding.Decode(ctx, value)
+	}
+	bucketPath := make([][]byte, 0, len(r.Bucket))
+	for _, name := range r.Bucket {
+		bucketName, err := decode(name)
+		if err != nil {
+			return nil, nil, nil, errors.Wrapf(ctx, err, "decode bucket name failed")
+		}
+		bucketPath = append(bucketPath, bucketName)
+	}
+	if r.IsBucket() {
+		return bucketPath, nil, nil, nil
+	}
+	key, err := decode(*r.Key)
+	if err != nil {
+		return nil, nil, nil, errors.Wrapf(ctx, err, "decode key failed")
+	}
+	value, err := decode(r.Value)
+	if err != nil {
+		return nil, nil, nil, errors.Wrapf(ctx, err, "decode value failed")
+	}
+	return bucketPath, key, value, nil
+}
+
+// NewRecord encodes the given bucket path, key and value.
+// UTF-8 is used if all parts are valid UTF-8, base64 otherwise.
+// A nil key creates a bucket record.
+func NewRecord(bucketPath [][]byte, key []byte, value []byte) Record {
+	encoding := recordEncoding(append(append([][]byte{}, bucketPath...), key, value)...)
+	record := Record{
+		Bucket:   make([]string, 0, len(bucketPath)),
+		Encoding: encoding,
+	}
+	for _, name := range bucketPath {
+		record.Bucket = append(record.Bucket, encoding.encode(name))
+	}
+	if key != nil {
+		encodedKey := encoding.encode(key)
+		record.Key = &encodedKey
+		record.Value = encoding.encode(value)
+	}
+	return record
+}
+
+// recordEncoding returns EncodingUTF8 if all parts are valid UTF-8, EncodingBase64 otherwise.
+func recordEncoding(parts ...[]byte) Encoding {
+	for _, part := range parts {
+		if !utf8.Valid(part) {
+			return EncodingBase64
+		}
+	}
+	return EncodingUTF8
+}
+
+// Export writes all given top-level buckets, including nested buckets, as
+// JSON Lines to w. All buckets are exported if none are given.
+// The export runs in a single read transaction.
+func Export(ctx context.Context, db DB, w io.Writer, buckets ...libkv.BucketName) error {
+	bw := bufio.NewWriter(w)
+	encoder := json.NewEncoder(bw)
+	err := db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		boltTx, ok := tx.(Tx)
+		if !ok {
+			return errors.Errorf(ctx, "tx is not a bolt transaction")
+		}
+		if len(buckets) == 0 {
+			names, err := tx.ListBucketNames(ctx)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "list bucket names failed")
+			}
+			buckets = names
+		}
+		for _, name := range buckets {
+			bucket := boltTx.Tx().Bucket(name)
+			if bucket == nil {
+				return errors.Wrapf(ctx, libkv.BucketNotFoundError, "bucket %s not found", name)
+			}
+			if err := exportBucket(ctx, encoder, [][]byte{name}, bucket); err != nil {
+				return errors.Wrapf(ctx, err, "export bucket %s failed", name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(ctx, err, "export failed")
+	}
+	if err := bw.Flush(); err != nil {
+		return errors.Wrapf(ctx, err, "flush failed")
+	}
+	return nil
+}
+
+func exportBucket(
+	ctx context.Context,
+	encoder *json.Encoder,
+	bucketPath [][]byte,
+	bucket *bolt.Bucket,
+) error {
+	if err := encoder.Encode(NewRecord(bucketPath, nil, nil)); err != nil {
+		return errors.Wrapf(ctx, err, "encode bucket failed")
+	}
+	cursor := bucket.Cursor()
+	for key, value := cursor.First(); key != nil; key, value = cursor.Next() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		if value == nil {
+			if nested := bucket.Bucket(key); nested != nil {
+				nestedPath := append(append([][]byte{}, bucketPath...), key)
+				if err := exportBucket(ctx, encoder, nestedPath, nested); err != nil {
+					return errors.Wrapf(ctx, err, "export nested bucket failed")
+				}
+				continue
+			}
+		}
+		if err := encoder.Encode(NewRecord(bucketPath, key, value)); err != nil {
+			return errors.Wrapf(ctx, err, "encode record failed")
+		}
+	}
+	return nil
+}
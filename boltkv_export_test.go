@@ -0,0 +1,79 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv_test
+
+import (
+	"bytes"
+	"context"
+
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+)
+
+var _ = Describe("Export", func() {
+	var ctx context.Context
+	var db boltkv.DB
+	var buf *bytes.Buffer
+	BeforeEach(func() {
+		ctx = context.Background()
+		buf = &bytes.Buffer{}
+		var err error
+		db, err = boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		err = db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.CreateBucket(ctx, libkv.NewBucketName("a"))
+			Expect(err).To(BeNil())
+			Expect(bucket.Put(ctx, []byte("k1"), []byte("v1"))).To(Succeed())
+			Expect(bucket.Put(ctx, []byte("k2"), []byte{0xff, 0x00})).To(Succeed())
+			nested, err := bucket.(boltkv.Bucket).Bucket().CreateBucket([]byte("n"))
+			Expect(err).To(BeNil())
+			Expect(nested.Put([]byte("k3"), []byte("v3"))).To(Succeed())
+			_, err = tx.CreateBucket(ctx, libkv.NewBucketName("b"))
+			Expect(err).To(BeNil())
+			return nil
+		})
+		Expect(err).To(BeNil())
+	})
+	AfterEach(func() {
+		_ = db.Close()
+		_ = db.Remove()
+	})
+	It("exports all buckets as json lines", func() {
+		Expect(boltkv.Export(ctx, db, buf)).To(Succeed())
+		Expect(buf.String()).To(Equal(`{"bucket":["a"],"encoding":"utf8"}
+{"bucket":["a"],"key":"k1","value":"v1","encoding":"utf8"}
+{"bucket":["YQ=="],"key":"azI=","value":"/wA=","encoding":"base64"}
+{"bucket":["a","n"],"encoding":"utf8"}
+{"bucket":["a","n"],"key":"k3","value":"v3","encoding":"utf8"}
+{"bucket":["b"],"encoding":"utf8"}
+`))
+	})
+	It("exports only the given buckets", func() {
+		Expect(boltkv.Export(ctx, db, buf, libkv.NewBucketName("b"))).To(Succeed())
+		Expect(buf.String()).To(Equal(`{"bucket":["b"],"encoding":"utf8"}
+`))
+	})
+	It("returns error for missing bucket", func() {
+		Expect(boltkv.Export(ctx, db, buf, libkv.NewBucketName("missing"))).NotTo(Succeed())
+	})
+	It("round trips through import", func() {
+		Expect(boltkv.Export(ctx, db, buf)).To(Succeed())
+		target, err := boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		defer func() {
+			_ = target.Close()
+			_ = target.Remove()
+		}()
+		result, err := boltkv.Import(ctx, target, bytes.NewReader(buf.Bytes()), boltkv.ImportOptions{})
+		Expect(err).To(BeNil())
+		Expect(result.Written).To(Equal(int64(3)))
+		exported := &bytes.Buffer{}
+		Expect(boltkv.Export(ctx, target, exported)).To(Succeed())
+		Expect(exported.String()).To(Equal(buf.String()))
+	})
+})
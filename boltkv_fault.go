@@ -0,0 +1,368 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Operations only matched by FaultRules, hooks are not notified about them.
+const (
+	OperationSync  Operation = "Sync"
+	OperationClose Operation = "Close"
+)
+
+// FaultCrashedError is returned by all calls of a FaultDB after a crash.
+var FaultCrashedError = errors.New(context.Background(), "db crashed")
+
+// FaultRule describes calls of a FaultDB to fail or delay.
+type FaultRule struct {
+	// Operation is the call to match:
+	// OperationUpdate fails an Update after its callback returned nil, so
+	// nothing is committed like on a failed commit; OperationView fails a View
+	// before its callback; OperationBucket matches Bucket, CreateBucket,
+	// CreateBucketIfNotExists and DeleteBucket; OperationGet, OperationPut,
+	// OperationDelete, OperationSync and OperationClose match the calls of the
+	// same name.
+	Operation Operation
+	// Bucket restricts the rule to calls on the bucket if not empty. With
+	// OperationUpdate it matches Updates touching the bucket.
+	Bucket libkv.BucketName
+	// Nth fires the rule only on the nth matching call, starting at 1.
+	// Zero fires it on every matching call.
+	Nth int
+	// Latency delays the matching calls.
+	Latency time.Duration
+	// Err is returned by the matching calls, nil only adds Latency.
+	Err error
+	// Crash, with OperationUpdate, discards the Update and closes the
+	// database without committing like a process crash. All further calls
+	// return FaultCrashedError, reopen the file to inspect the state.
+	Crash bool
+}
+
+// FaultDB is a DB failing calls matching its rules, for testing error paths.
+type FaultDB interface {
+	DB
+	// Inject adds rules, each with its own count of matching calls.
+	Inject(rules ...FaultRule)
+	// Reset removes all rules.
+	Reset()
+	// Crashed reports whether a Crash rule fired.
+	Crashed() bool
+}
+
+// NewFaultDB returns db failing calls as programmed with Inject.
+func NewFaultDB(db DB) FaultDB {
+	return &faultDB{db: db}
+}
+
+type faultDB struct {
+	db DB
+
+	mux     sync.Mutex
+	rules   []*faultRuleState
+	crashed bool
+}
+
+type faultRuleState struct {
+	FaultRule
+	calls int
+}
+
+func (f *faultDB) Inject(rules ...FaultRule) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	for _, rule := range rules {
+		f.rules = append(f.rules, &faultRuleState{FaultRule: rule})
+	}
+}
+
+func (f *faultDB) Reset() {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	f.rules = nil
+}
+
+func (f *faultDB) Crashed() bool {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	return f.crashed
+}
+
+// match counts the call for all matching rules and returns the fired ones.
+// touched reports whether a bucket was accessed, nil matches any bucket.
+func (f *faultDB) match(op Operation, touched func(libkv.BucketName) bool) []FaultRule {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	var result []FaultRule
+	for _, rule := range f.rules {
+		if rule.Operation != op {
+			continue
+		}
+		if len(rule.Bucket) > 0 && (touched == nil || !touched(rule.Bucket)) {
+			continue
+		}
+		rule.calls++
+		if rule.Nth == 0 || rule.Nth == rule.calls {
+			result = append(result, rule.FaultRule)
+		}
+	}
+	return result
+}
+
+// inject matches the call, sleeps for the latency of the fired rules and
+// returns the error of the first fired rule with one.
+func (f *faultDB) inject(
+	ctx context.Context,
+	op Operation,
+	touched func(libkv.BucketName) bool,
+) (crash bool, err error) {
+	if f.Crashed() {
+		return false, errors.Wrapf(ctx, FaultCrashedError, "%s failed", op)
+	}
+	for _, rule := range f.match(op, touched) {
+		if rule.Latency > 0 {
+			select {
+			case <-ctx.Done():
+				return false, errors.Wrapf(ctx, ctx.Err(), "%s canceled", op)
+			case <-time.After(rule.Latency):
+			}
+		}
+		if rule.Crash {
+			crash = true
+		}
+		if err == nil && rule.Err != nil {
+			err = errors.Wrapf(ctx, rule.Err, "injected %s fault", op)
+		}
+	}
+	return crash, err
+}
+
+// bucketMatcher returns a matcher for a single bucket.
+func bucketMatcher(bucketName libkv.BucketName) func(libkv.BucketName) bool {
+	return func(name libkv.BucketName) bool {
+		return name.Equal(bucketName)
+	}
+}
+
+func (f *faultDB) Update(
+	ctx context.Context,
+	fn func(ctx context.Context, tx libkv.Tx) error,
+) error {
+	if f.Crashed() {
+		return errors.Wrapf(ctx, FaultCrashedError, "db update failed")
+	}
+	var crash bool
+	err := f.db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		faultTx := &faultTx{tx: tx, db: f, touched: map[string]bool{}}
+		if err := fn(ctx, faultTx); err != nil {
+			return err
+		}
+		var err error
+		crash, err = f.inject(ctx, OperationUpdate, faultTx.isTouched)
+		if crash {
+			return errors.Wrapf(ctx, FaultCrashedError, "crash before commit")
+		}
+		return err
+	})
+	if crash {
+		f.mux.Lock()
+		f.crashed = true
+		f.mux.Unlock()
+		// close bolt directly to skip the sync and removal of Close
+		_ = f.db.DB().Close()
+	}
+	return err
+}
+
+func (f *faultDB) View(
+	ctx context.Context,
+	fn func(ctx context.Context, tx libkv.Tx) error,
+) error {
+	if _, err := f.inject(ctx, OperationView, nil); err != nil {
+		return errors.Wrapf(ctx, err, "db view failed")
+	}
+	return f.db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		return fn(ctx, &faultTx{tx: tx, db: f, touched: map[string]bool{}})
+	})
+}
+
+func (f *faultDB) Sync() error {
+	ctx := context.Background()
+	if _, err := f.inject(ctx, OperationSync, nil); err != nil {
+		return err
+	}
+	return f.db.Sync()
+}
+
+// Close closes the wrapped DB even if a rule fails it, so tests do not leak it.
+func (f *faultDB) Close() error {
+	ctx := context.Background()
+	if f.Crashed() {
+		return nil
+	}
+	_, injected := f.inject(ctx, OperationClose, nil)
+	if err := f.db.Close(); err != nil {
+		return err
+	}
+	return injected
+}
+
+func (f *faultDB) DB() *bolt.DB {
+	return f.db.DB()
+}
+
+func (f *faultDB) openTransactions() []TransactionInfo {
+	return OpenTransactions(f.db)
+}
+
+func (f *faultDB) fingerprinted(bucketName libkv.BucketName) bool {
+	config, ok := f.db.(fingerprintConfig)
+	return ok && config.fingerprinted(bucketName)
+}
+
+func (f *faultDB) Remove() error {
+	return f.db.Remove()
+}
+
+func (f *faultDB) Stats(ctx context.Context) (*libkv.Stats, error) {
+	return f.db.Stats(ctx)
+}
+
+func (f *faultDB) StatsDetailed(ctx context.Context) (*libkv.Stats, error) {
+	return f.db.StatsDetailed(ctx)
+}
+
+type faultTx struct {
+	tx libkv.Tx
+	db *faultDB
+
+	mux     sync.Mutex
+	touched map[string]bool
+}
+
+// Tx returns the bolt transaction of a wrapped boltkv Tx.
+func (t *faultTx) Tx() *bolt.Tx {
+	if tx, ok := t.tx.(Tx); ok {
+		return tx.Tx()
+	}
+	return nil
+}
+
+func (t *faultTx) touch(name libkv.BucketName) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.touched[name.String()] = true
+}
+
+func (t *faultTx) isTouched(name libkv.BucketName) bool {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return t.touched[name.String()]
+}
+
+// bucketOperation records the access to the bucket and injects its faults.
+func (t *faultTx) bucketOperation(ctx context.Context, name libkv.BucketName) error {
+	t.touch(name)
+	_, err := t.db.inject(ctx, OperationBucket, bucketMatcher(name))
+	return err
+}
+
+func (t *faultTx) Bucket(ctx context.Context, name libkv.BucketName) (libkv.Bucket, error) {
+	if err := t.bucketOperation(ctx, name); err != nil {
+		return nil, err
+	}
+	return t.wrap(name)(t.tx.Bucket(ctx, name))
+}
+
+func (t *faultTx) CreateBucket(ctx context.Context, name libkv.BucketName) (libkv.Bucket, error) {
+	if err := t.bucketOperation(ctx, name); err != nil {
+		return nil, err
+	}
+	return t.wrap(name)(t.tx.CreateBucket(ctx, name))
+}
+
+func (t *faultTx) CreateBucketIfNotExists(
+	ctx context.Context,
+	name libkv.BucketName,
+) (libkv.Bucket, error) {
+	if err := t.bucketOperation(ctx, name); err != nil {
+		return nil, err
+	}
+	return t.wrap(name)(t.tx.CreateBucketIfNotExists(ctx, name))
+}
+
+func (t *faultTx) DeleteBucket(ctx context.Context, name libkv.BucketName) error {
+	if err := t.bucketOperation(ctx, name); err != nil {
+		return err
+	}
+	return t.tx.DeleteBucket(ctx, name)
+}
+
+func (t *faultTx) ListBucketNames(ctx context.Context) (libkv.BucketNames, error) {
+	return t.tx.ListBucketNames(ctx)
+}
+
+// wrap returns a function wrapping the result of a bucket lookup.
+func (t *faultTx) wrap(name libkv.BucketName) func(libkv.Bucket, error) (libkv.Bucket, error) {
+	return func(bucket libkv.Bucket, err error) (libkv.Bucket, error) {
+		if err != nil {
+			return nil, err
+		}
+		return &faultBucket{bucket: bucket, tx: t, name: name}, nil
+	}
+}
+
+type faultBucket struct {
+	bucket libkv.Bucket
+	tx     *faultTx
+	name   libkv.BucketName
+}
+
+// Bucket returns the bolt bucket of a wrapped boltkv Bucket.
+func (b *faultBucket) Bucket() *bolt.Bucket {
+	if bucket, ok := b.bucket.(Bucket); ok {
+		return bucket.Bucket()
+	}
+	return nil
+}
+
+func (b *faultBucket) Get(ctx context.Context, key []byte) (libkv.Item, error) {
+	if _, err := b.tx.db.inject(ctx, OperationGet, bucketMatcher(b.name)); err != nil {
+		return nil, err
+	}
+	return b.bucket.Get(ctx, key)
+}
+
+func (b *faultBucket) Put(ctx context.Context, key []byte, value []byte) error {
+	b.tx.touch(b.name)
+	if _, err := b.tx.db.inject(ctx, OperationPut, bucketMatcher(b.name)); err != nil {
+		return err
+	}
+	return b.bucket.Put(ctx, key, value)
+}
+
+func (b *faultBucket) Delete(ctx context.Context, key []byte) error {
+	b.tx.touch(b.name)
+	if _, err := b.tx.db.inject(ctx, OperationDelete, bucketMatcher(b.name)); err != nil {
+		return err
+	}
+	return b.bucket.Delete(ctx, key)
+}
+
+func (b *faultBucket) Iterator() libkv.Iterator {
+	return b.bucket.Iterator()
+}
+
+func (b *faultBucket) IteratorReverse() libkv.Iterator {
+	return b.bucket.IteratorReverse()
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv_test
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+)
+
+var _ = Describe("FaultDB", func() {
+	var ctx context.Context
+	var path string
+	var db boltkv.FaultDB
+	var injectedError error
+	bucketName := libkv.NewBucketName("bucket")
+	BeforeEach(func() {
+		ctx = context.Background()
+		path = filepath.Join(GinkgoT().TempDir(), "fault.db")
+		boltDB, err := boltkv.OpenFile(ctx, path)
+		Expect(err).To(BeNil())
+		db = boltkv.NewFaultDB(boltDB)
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+		injectedError = errors.New(ctx, "disk full")
+	})
+	put := func(key string) error {
+		return db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.CreateBucketIfNotExists(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			return bucket.Put(ctx, []byte(key), []byte("value"))
+		})
+	}
+	exists := func(db boltkv.DB, key string) bool {
+		var result bool
+		err := db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if errors.Is(err, libkv.BucketNotFoundError) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			item, err := bucket.Get(ctx, []byte(key))
+			if err != nil {
+				return err
+			}
+			result = item.Exists()
+			return nil
+		})
+		Expect(err).To(BeNil())
+		return result
+	}
+	It("passes calls through without rules", func() {
+		Expect(put("a")).To(BeNil())
+		Expect(exists(db, "a")).To(BeTrue())
+		Expect(db.Sync()).To(BeNil())
+		Expect(db.Crashed()).To(BeFalse())
+	})
+	It("fails the nth put only", func() {
+		db.Inject(boltkv.FaultRule{
+			Operation: boltkv.OperationPut,
+			Nth:       2,
+			Err:       injectedError,
+		})
+		Expect(put("a")).To(BeNil())
+		err := put("b")
+		Expect(errors.Is(err, injectedError)).To(BeTrue())
+		Expect(put("c")).To(BeNil())
+		Expect(exists(db, "a")).To(BeTrue())
+		Expect(exists(db, "b")).To(BeFalse())
+		Expect(exists(db, "c")).To(BeTrue())
+	})
+	It("rolls back an update touching the bucket", func() {
+		db.Inject(boltkv.FaultRule{
+			Operation: boltkv.OperationUpdate,
+			Bucket:    bucketName,
+			Err:       injectedError,
+		})
+		err := db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			_, err := tx.CreateBucket(ctx, libkv.NewBucketName("other"))
+			return err
+		})
+		Expect(err).To(BeNil())
+		err = put("a")
+		Expect(errors.Is(err, injectedError)).To(BeTrue())
+		Expect(exists(db, "a")).To(BeFalse())
+	})
+	It("ignores rules of other buckets", func() {
+		db.Inject(boltkv.FaultRule{
+			Operation: boltkv.OperationPut,
+			Bucket:    libkv.NewBucketName("other"),
+			Err:       injectedError,
+		})
+		Expect(put("a")).To(BeNil())
+	})
+	It("fails a bucket lookup", func() {
+		db.Inject(boltkv.FaultRule{
+			Operation: boltkv.OperationBucket,
+			Err:       injectedError,
+		})
+		Expect(errors.Is(put("a"), injectedError)).To(BeTrue())
+	})
+	It("fails a view before its callback", func() {
+		db.Inject(boltkv.FaultRule{Operation: boltkv.OperationView, Err: injectedError})
+		var called bool
+		err := db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			called = true
+			return nil
+		})
+		Expect(errors.Is(err, injectedError)).To(BeTrue())
+		Expect(called).To(BeFalse())
+	})
+	It("fails sync and close", func() {
+		db.Inject(
+			boltkv.FaultRule{Operation: boltkv.OperationSync, Err: injectedError},
+			boltkv.FaultRule{Operation: boltkv.OperationClose, Err: injectedError},
+		)
+		Expect(errors.Is(db.Sync(), injectedError)).To(BeTrue())
+		Expect(errors.Is(db.Close(), injectedError)).To(BeTrue())
+		reopened, err := boltkv.OpenFile(ctx, path)
+		Expect(err).To(BeNil())
+		Expect(reopened.Close()).To(BeNil())
+	})
+	It("delays matching calls", func() {
+		db.Inject(boltkv.FaultRule{Operation: boltkv.OperationGet, Latency: 50 * time.Millisecond})
+		Expect(put("a")).To(BeNil())
+		start := time.Now()
+		Expect(exists(db, "a")).To(BeTrue())
+		Expect(time.Since(start)).To(BeNumerically(">=", 50*time.Millisecond))
+	})
+	It("returns the context error while delaying", func() {
+		db.Inject(boltkv.FaultRule{Operation: boltkv.OperationView, Latency: time.Minute})
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		err := db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			return nil
+		})
+		Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+	})
+	It("crashes before commit", func() {
+		Expect(put("a")).To(BeNil())
+		db.Inject(boltkv.FaultRule{Operation: boltkv.OperationUpdate, Crash: true})
+		err := put("b")
+		Expect(errors.Is(err, boltkv.FaultCrashedError)).To(BeTrue())
+		Expect(db.Crashed()).To(BeTrue())
+
+		Expect(errors.Is(put("c"), boltkv.FaultCrashedError)).To(BeTrue())
+		err = db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			return nil
+		})
+		Expect(errors.Is(err, boltkv.FaultCrashedError)).To(BeTrue())
+		Expect(errors.Is(db.Sync(), boltkv.FaultCrashedError)).To(BeTrue())
+
+		reopened, err := boltkv.OpenFile(ctx, path)
+		Expect(err).To(BeNil())
+		defer reopened.Close()
+		Expect(exists(reopened, "a")).To(BeTrue())
+		Expect(exists(reopened, "b")).To(BeFalse())
+		findings, err := boltkv.Check(ctx, reopened)
+		Expect(err).To(BeNil())
+		Expect(findings).To(BeEmpty())
+	})
+	It("removes all rules on reset", func() {
+		db.Inject(boltkv.FaultRule{Operation: boltkv.OperationPut, Err: injectedError})
+		Expect(put("a")).NotTo(BeNil())
+		db.Reset()
+		Expect(put("a")).To(BeNil())
+	})
+	It("exposes the wrapped bolt tx and bucket", func() {
+		err := db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			Expect(tx.(boltkv.Tx).Tx()).NotTo(BeNil())
+			bucket, err := tx.CreateBucket(ctx, bucketName)
+			Expect(err).To(BeNil())
+			Expect(bucket.(boltkv.Bucket).Bucket()).NotTo(BeNil())
+			return nil
+		})
+		Expect(err).To(BeNil())
+	})
+})
@@ -0,0 +1,300 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	bolt "go.etcd.io/bbolt"
+)
+
+// FingerprintAlgorithm identifies how a Fingerprint was computed.
+// Fingerprints are only comparable if their algorithms are equal.
+type FingerprintAlgorithm string
+
+const (
+	// FingerprintAlgorithmSHA256 hashes all pairs in key order, computed by a full scan.
+	FingerprintAlgorithmSHA256 FingerprintAlgorithm = "sha256"
+	// FingerprintAlgorithmSHA256Sum adds the SHA-256 of every pair modulo 2^256,
+	// which allows updating it on every write, see WithFingerprint.
+	FingerprintAlgorithmSHA256Sum FingerprintAlgorithm = "sha256-sum"
+)
+
+// fingerprintBucket holds the incrementally maintained fingerprints, see WithFingerprint.
+var fingerprintBucket = libkv.NewBucketName(InternalBucketPrefix + "fingerprint")
+
+// Fingerprint is a content hash of a bucket. Buckets with equal keys and
+// values have equal fingerprints. Keys of nested buckets are not included.
+type Fingerprint struct {
+	Bucket    libkv.BucketName
+	Algorithm FingerprintAlgorithm
+	Keys      int64
+	Hash      []byte
+}
+
+// String returns algorithm:hex.
+func (f Fingerprint) String() string {
+	return string(f.Algorithm) + ":" + hex.EncodeToString(f.Hash)
+}
+
+// WithFingerprint maintains a FingerprintAlgorithmSHA256Sum fingerprint of the
+// given top-level buckets in the internal bucket _boltkv_fingerprint, updated in
+// the same transaction as every Put and Delete, so BucketFingerprint reads it in
+// O(1). The first write after enabling scans the bucket once. Once stored, every DB keeps it updated,
+// with or without this option. Writes through the raw bolt.Tx or bolt.Bucket
+// bypass the update and make the stored fingerprint stale.
+func WithFingerprint(buckets ...libkv.BucketName) ChangeDBOptions {
+	return func(opts *DBOptions) {
+		opts.FingerprintBuckets = append(opts.FingerprintBuckets, buckets...)
+	}
+}
+
+// fingerprintConfig is implemented by the DBs returned by NewDB.
+type fingerprintConfig interface {
+	// fingerprinted reports whether the bucket is configured with WithFingerprint.
+	fingerprinted(bucketName libkv.BucketName) bool
+}
+
+// BucketFingerprint returns the fingerprint of the bucket of db in a single View.
+// Buckets with a stored fingerprint or configured with WithFingerprint return
+// FingerprintAlgorithmSHA256Sum, all others are scanned with FingerprintAlgorithmSHA256.
+// Wrappers of a DB do not know its WithFingerprint buckets and only see stored fingerprints.
+func BucketFingerprint(
+	ctx context.Context,
+	db DB,
+	bucketName libkv.BucketName,
+) (Fingerprint, error) {
+	var configured bool
+	if config, ok := db.(fingerprintConfig); ok {
+		configured = config.fingerprinted(bucketName)
+	}
+	var result Fingerprint
+	err := db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		boltkvTx, ok := tx.(Tx)
+		if !ok {
+			return errors.Errorf(ctx, "tx is not a bolt transaction")
+		}
+		boltTx := boltkvTx.Tx()
+		tracker := &fingerprintTracker{tx: boltTx, name: bucketName}
+		if !configured && !tracker.stored() {
+			var err error
+			result, err = ComputeFingerprint(ctx, tx, bucketName, FingerprintAlgorithmSHA256)
+			return err
+		}
+		boltBucket := boltTx.Bucket(bucketName)
+		if boltBucket == nil {
+			return errors.Wrapf(ctx, libkv.BucketNotFoundError, "bucket %s not found", bucketName)
+		}
+		state, err := tracker.load(boltBucket)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "load fingerprint failed")
+		}
+		result = state.fingerprint(bucketName)
+		return nil
+	})
+	if err != nil {
+		return Fingerprint{}, errors.Wrapf(ctx, err, "fingerprint %s failed", bucketName)
+	}
+	return result, nil
+}
+
+// ComputeFingerprint scans the bucket and returns its fingerprint with the given algorithm.
+// It works on any libkv.Tx, e.g. to verify a stored fingerprint or compare other backends.
+func ComputeFingerprint(
+	ctx context.Context,
+	tx libkv.Tx,
+	bucketName libkv.BucketName,
+	algorithm FingerprintAlgorithm,
+) (Fingerprint, error) {
+	bucket, err := tx.Bucket(ctx, bucketName)
+	if err != nil {
+		return Fingerprint{}, errors.Wrapf(ctx, err, "get bucket failed")
+	}
+	var sum fingerprintState
+	h := sha256.New()
+	var keys int64
+	it := bucket.Iterator()
+	defer it.Close()
+	for it.Rewind(); it.Valid(); it.Next() {
+		item := it.Item()
+		var nested bool
+		err := item.Value(func(value []byte) error {
+			if value == nil {
+				// nested bucket, skipped like by the stored fingerprint
+				nested = true
+				return nil
+			}
+			switch algorithm {
+			case FingerprintAlgorithmSHA256:
+				writePair(h, item.Key(), value)
+			case FingerprintAlgorithmSHA256Sum:
+				sum.add(item.Key(), value)
+			default:
+				return errors.Errorf(ctx, "unknown fingerprint algorithm '%s'", algorithm)
+			}
+			return nil
+		})
+		if err != nil {
+			return Fingerprint{}, errors.Wrapf(ctx, err, "read value failed")
+		}
+		if !nested {
+			keys++
+		}
+	}
+	if algorithm == FingerprintAlgorithmSHA256Sum {
+		return sum.fingerprint(bucketName), nil
+	}
+	return Fingerprint{
+		Bucket:    bucketName,
+		Algorithm: algorithm,
+		Keys:      keys,
+		Hash:      h.Sum(nil),
+	}, nil
+}
+
+// writePair writes key and value length-prefixed, so pair boundaries are unambiguous.
+func writePair(h hash.Hash, key []byte, value []byte) {
+	var length [binary.MaxVarintLen64]byte
+	h.Write(length[:binary.PutUvarint(length[:], uint64(len(key)))])
+	h.Write(key)
+	h.Write(length[:binary.PutUvarint(length[:], uint64(len(value)))])
+	h.Write(value)
+}
+
+func pairHash(key []byte, value []byte) [sha256.Size]byte {
+	h := sha256.New()
+	writePair(h, key, value)
+	var result [sha256.Size]byte
+	h.Sum(result[:0])
+	return result
+}
+
+// fingerprintState is the stored form of a FingerprintAlgorithmSHA256Sum fingerprint.
+type fingerprintState struct {
+	keys int64
+	sum  [sha256.Size]byte
+}
+
+func (s *fingerprintState) add(key []byte, value []byte) {
+	h := pairHash(key, value)
+	carry := 0
+	for i := len(s.sum) - 1; i >= 0; i-- {
+		v := int(s.sum[i]) + int(h[i]) + carry
+		s.sum[i] = byte(v)
+		carry = v >> 8
+	}
+	s.keys++
+}
+
+func (s *fingerprintState) remove(key []byte, value []byte) {
+	h := pairHash(key, value)
+	borrow := 0
+	for i := len(s.sum) - 1; i >= 0; i-- {
+		v := int(s.sum[i]) - int(h[i]) - borrow
+		borrow = 0
+		if v < 0 {
+			v += 256
+			borrow = 1
+		}
+		s.sum[i] = byte(v)
+	}
+	s.keys--
+}
+
+func (s fingerprintState) fingerprint(bucketName libkv.BucketName) Fingerprint {
+	return Fingerprint{
+		Bucket:    bucketName,
+		Algorithm: FingerprintAlgorithmSHA256Sum,
+		Keys:      s.keys,
+		Hash:      append([]byte{}, s.sum[:]...),
+	}
+}
+
+func (s fingerprintState) marshal() []byte {
+	return append(binary.BigEndian.AppendUint64(nil, uint64(s.keys)), s.sum[:]...)
+}
+
+// fingerprintTracker updates the stored fingerprint of one bucket in a writable transaction.
+type fingerprintTracker struct {
+	tx   *bolt.Tx
+	name []byte
+}
+
+// stored reports whether a fingerprint is stored for the bucket.
+func (f *fingerprintTracker) stored() bool {
+	meta := f.tx.Bucket(fingerprintBucket)
+	return meta != nil && meta.Get(f.name) != nil
+}
+
+// load returns the stored state, computed from boltBucket if nothing is stored.
+func (f *fingerprintTracker) load(boltBucket *bolt.Bucket) (fingerprintState, error) {
+	var state fingerprintState
+	if meta := f.tx.Bucket(fingerprintBucket); meta != nil {
+		if value := meta.Get(f.name); len(value) == 8+sha256.Size {
+			state.keys = int64(binary.BigEndian.Uint64(value))
+			copy(state.sum[:], value[8:])
+			return state, nil
+		}
+	}
+	err := boltBucket.ForEach(func(key []byte, value []byte) error {
+		if value != nil {
+			state.add(key, value)
+		}
+		return nil
+	})
+	return state, err
+}
+
+func (f *fingerprintTracker) store(state fingerprintState) error {
+	meta, err := f.tx.CreateBucketIfNotExists(fingerprintBucket)
+	if err != nil {
+		return err
+	}
+	return meta.Put(f.name, state.marshal())
+}
+
+// update calls write and, if it succeeds, replaces the pair of key in the
+// stored fingerprint, a nil value removes it. The state and the old value are
+// read before write changes the bucket.
+func (f *fingerprintTracker) update(
+	boltBucket *bolt.Bucket,
+	key []byte,
+	value []byte,
+	write func() error,
+) error {
+	state, err := f.load(boltBucket)
+	if err != nil {
+		return err
+	}
+	var old []byte
+	if current := boltBucket.Get(key); current != nil {
+		old = append([]byte{}, current...)
+	}
+	if err := write(); err != nil {
+		return err
+	}
+	if old != nil {
+		state.remove(key, old)
+	}
+	if value != nil {
+		state.add(key, value)
+	}
+	return f.store(state)
+}
+
+// clear removes the stored fingerprint, used when the bucket is deleted.
+func (f *fingerprintTracker) clear() error {
+	meta := f.tx.Bucket(fingerprintBucket)
+	if meta == nil {
+		return nil
+	}
+	return meta.Delete(f.name)
+}
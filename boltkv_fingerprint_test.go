@@ -0,0 +1,191 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv_test
+
+import (
+	"context"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bborbe/boltkv"
+)
+
+var _ = Describe("Fingerprint", func() {
+	var ctx context.Context
+	var db boltkv.DB
+	var bucketName libkv.BucketName
+	put := func(db libkv.DB, pairs ...string) {
+		Expect(db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.CreateBucketIfNotExists(ctx, bucketName)
+			Expect(err).To(BeNil())
+			for i := 0; i < len(pairs); i += 2 {
+				Expect(bucket.Put(ctx, []byte(pairs[i]), []byte(pairs[i+1]))).To(Succeed())
+			}
+			return nil
+		})).To(Succeed())
+	}
+	del := func(db libkv.DB, keys ...string) {
+		Expect(db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			Expect(err).To(BeNil())
+			for _, key := range keys {
+				Expect(bucket.Delete(ctx, []byte(key))).To(Succeed())
+			}
+			return nil
+		})).To(Succeed())
+	}
+	compute := func(db libkv.DB, algorithm boltkv.FingerprintAlgorithm) boltkv.Fingerprint {
+		var result boltkv.Fingerprint
+		Expect(db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			var err error
+			result, err = boltkv.ComputeFingerprint(ctx, tx, bucketName, algorithm)
+			return err
+		})).To(Succeed())
+		return result
+	}
+	BeforeEach(func() {
+		ctx = context.Background()
+		bucketName = libkv.NewBucketName("a")
+		var err error
+		db, err = boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		put(db, "k1", "v1", "k2", "v2")
+	})
+	AfterEach(func() {
+		_ = db.Close()
+		_ = db.Remove()
+	})
+	It("computes a sha256 over all pairs", func() {
+		fingerprint, err := boltkv.BucketFingerprint(ctx, db, bucketName)
+		Expect(err).To(BeNil())
+		Expect(fingerprint.Bucket).To(Equal(bucketName))
+		Expect(fingerprint.Algorithm).To(Equal(boltkv.FingerprintAlgorithmSHA256))
+		Expect(fingerprint.Keys).To(Equal(int64(2)))
+		Expect(fingerprint.Hash).To(HaveLen(32))
+		Expect(fingerprint.String()).To(HavePrefix("sha256:"))
+	})
+	It("changes with the content", func() {
+		before, err := boltkv.BucketFingerprint(ctx, db, bucketName)
+		Expect(err).To(BeNil())
+		put(db, "k2", "changed")
+		after, err := boltkv.BucketFingerprint(ctx, db, bucketName)
+		Expect(err).To(BeNil())
+		Expect(after.Hash).NotTo(Equal(before.Hash))
+		put(db, "k2", "v2")
+		again, err := boltkv.BucketFingerprint(ctx, db, bucketName)
+		Expect(err).To(BeNil())
+		Expect(again).To(Equal(before))
+	})
+	It("distinguishes pair boundaries", func() {
+		before, err := boltkv.BucketFingerprint(ctx, db, bucketName)
+		Expect(err).To(BeNil())
+		del(db, "k1", "k2")
+		put(db, "k1v", "1", "k2", "v2")
+		after, err := boltkv.BucketFingerprint(ctx, db, bucketName)
+		Expect(err).To(BeNil())
+		Expect(after.Hash).NotTo(Equal(before.Hash))
+	})
+	It("returns BucketNotFoundError for missing buckets", func() {
+		_, err := boltkv.BucketFingerprint(ctx, db, libkv.NewBucketName("missing"))
+		Expect(errors.Is(err, libkv.BucketNotFoundError)).To(BeTrue())
+	})
+	Context("WithFingerprint", func() {
+		var tracked boltkv.DB
+		BeforeEach(func() {
+			tracked = boltkv.NewDB(db.DB(), boltkv.WithFingerprint(bucketName))
+		})
+		It("computes the sum before the first write", func() {
+			fingerprint, err := boltkv.BucketFingerprint(ctx, tracked, bucketName)
+			Expect(err).To(BeNil())
+			Expect(fingerprint).To(Equal(compute(db, boltkv.FingerprintAlgorithmSHA256Sum)))
+		})
+		It("maintains the sum on every write", func() {
+			put(tracked, "k3", "v3", "k1", "changed")
+			del(tracked, "k2", "missing")
+			fingerprint, err := boltkv.BucketFingerprint(ctx, tracked, bucketName)
+			Expect(err).To(BeNil())
+			Expect(fingerprint.Algorithm).To(Equal(boltkv.FingerprintAlgorithmSHA256Sum))
+			Expect(fingerprint.Keys).To(Equal(int64(2)))
+			Expect(fingerprint).To(Equal(compute(db, boltkv.FingerprintAlgorithmSHA256Sum)))
+		})
+		It("is kept updated by DBs without the option", func() {
+			put(tracked, "k3", "v3")
+			put(db, "k4", "v4")
+			fingerprint, err := boltkv.BucketFingerprint(ctx, db, bucketName)
+			Expect(err).To(BeNil())
+			Expect(fingerprint.Keys).To(Equal(int64(4)))
+			Expect(fingerprint).To(Equal(compute(db, boltkv.FingerprintAlgorithmSHA256Sum)))
+		})
+		It("hides the fingerprint bucket from ListBucketNames", func() {
+			put(tracked, "k3", "v3")
+			Expect(db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+				names, err := tx.ListBucketNames(ctx)
+				Expect(err).To(BeNil())
+				Expect(names).To(Equal(libkv.BucketNames{bucketName}))
+				return nil
+			})).To(Succeed())
+		})
+		It("keeps the sum if a write fails", func() {
+			Expect(tracked.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+				bucket, err := tx.Bucket(ctx, bucketName)
+				Expect(err).To(BeNil())
+				Expect(bucket.Put(ctx, []byte{}, []byte("v"))).NotTo(Succeed())
+				return bucket.Put(ctx, []byte("k3"), []byte("v3"))
+			})).To(Succeed())
+			fingerprint, err := boltkv.BucketFingerprint(ctx, tracked, bucketName)
+			Expect(err).To(BeNil())
+			Expect(fingerprint.Keys).To(Equal(int64(3)))
+			Expect(fingerprint).To(Equal(compute(db, boltkv.FingerprintAlgorithmSHA256Sum)))
+		})
+		It("skips nested buckets like ComputeFingerprint", func() {
+			Expect(db.DB().Update(func(tx *bolt.Tx) error {
+				_, err := tx.Bucket(bucketName).CreateBucket([]byte("nested"))
+				return err
+			})).To(Succeed())
+			put(tracked, "k3", "v3")
+			fingerprint, err := boltkv.BucketFingerprint(ctx, tracked, bucketName)
+			Expect(err).To(BeNil())
+			Expect(fingerprint.Keys).To(Equal(int64(3)))
+			Expect(fingerprint).To(Equal(compute(db, boltkv.FingerprintAlgorithmSHA256Sum)))
+		})
+		It("copies into a fingerprinted DB", func() {
+			put(tracked, "k3", "v3")
+			dst, err := boltkv.OpenTemp(ctx)
+			Expect(err).To(BeNil())
+			defer func() {
+				_ = dst.Close()
+				_ = dst.Remove()
+			}()
+			dstTracked := boltkv.NewDB(dst.DB(), boltkv.WithFingerprint(bucketName))
+			Expect(boltkv.Copy(ctx, tracked, dstTracked, boltkv.CopyOptions{})).To(Succeed())
+			fingerprint, err := boltkv.BucketFingerprint(ctx, dstTracked, bucketName)
+			Expect(err).To(BeNil())
+			Expect(fingerprint.Keys).To(Equal(int64(3)))
+			Expect(fingerprint).To(Equal(compute(dst, boltkv.FingerprintAlgorithmSHA256Sum)))
+			Expect(fingerprint).To(Equal(compute(db, boltkv.FingerprintAlgorithmSHA256Sum)))
+		})
+		It("removes the stored sum with the bucket", func() {
+			put(tracked, "k3", "v3")
+			Expect(tracked.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+				return tx.DeleteBucket(ctx, bucketName)
+			})).To(Succeed())
+			put(db, "k1", "v1")
+			fingerprint, err := boltkv.BucketFingerprint(ctx, db, bucketName)
+			Expect(err).To(BeNil())
+			Expect(fingerprint.Algorithm).To(Equal(boltkv.FingerprintAlgorithmSHA256))
+			Expect(fingerprint.Keys).To(Equal(int64(1)))
+		})
+	})
+	It("returns error for unknown algorithms", func() {
+		Expect(db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			_, err := boltkv.ComputeFingerprint(ctx, tx, bucketName, "md5")
+			return err
+		})).NotTo(Succeed())
+	})
+})
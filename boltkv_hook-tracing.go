@@ -0,0 +1,72 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv
+
+import (
+	"context"
+
+	libkv "github.com/bborbe/kv"
+)
+
+// Tracer is the minimal tracing API the tracing hook needs.
+// Adapt your tracing SDK (e.g. OpenTelemetry) to it, so boltkv does not
+// depend on the SDK directly.
+//
+//counterfeiter:generate -o mocks/boltkv-tracer.go --fake-name BoltkvTracer . Tracer
+type Tracer interface {
+	Start(ctx context.Context, spanName string) (context.Context, Span)
+}
+
+// Span is a single traced operation created by Tracer.
+//
+//counterfeiter:generate -o mocks/boltkv-span.go --fake-name BoltkvSpan . Span
+type Span interface {
+	SetAttribute(key string, value string)
+	RecordError(err error)
+	End()
+}
+
+const spanCtxKey contextKey = "span"
+
+// NewTracingHook returns a Hook that emits one span per operation.
+// Spans are named "boltkv.<Operation>" and carry the bucket name as
+// attribute "boltkv.bucket" if the operation is bucket scoped.
+func NewTracingHook(tracer Tracer) Hook {
+	return &tracingHook{
+		tracer: tracer,
+	}
+}
+
+type tracingHook struct {
+	tracer Tracer
+}
+
+func (t *tracingHook) Start(
+	ctx context.Context,
+	op Operation,
+	bucketName libkv.BucketName,
+) context.Context {
+	ctx, span := t.tracer.Start(ctx, "boltkv."+op.String())
+	if len(bucketName) > 0 {
+		span.SetAttribute("boltkv.bucket", bucketName.String())
+	}
+	return context.WithValue(ctx, spanCtxKey, span)
+}
+
+func (t *tracingHook) End(
+	ctx context.Context,
+	op Operation,
+	bucketName libkv.BucketName,
+	err error,
+) {
+	span, ok := ctx.Value(spanCtxKey).(Span)
+	if !ok {
+		return
+	}
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
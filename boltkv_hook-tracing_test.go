@@ -0,0 +1,89 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv_test
+
+import (
+	"context"
+
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+)
+
+type testTracer struct {
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, spanName string) (context.Context, boltkv.Span) {
+	span := &testSpan{
+		name:       spanName,
+		attributes: map[string]string{},
+	}
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+type testSpan struct {
+	name       string
+	attributes map[string]string
+	err        error
+	ended      bool
+}
+
+func (t *testSpan) SetAttribute(key string, value string) {
+	t.attributes[key] = value
+}
+
+func (t *testSpan) RecordError(err error) {
+	t.err = err
+}
+
+func (t *testSpan) End() {
+	t.ended = true
+}
+
+var _ = Describe("TracingHook", func() {
+	var ctx context.Context
+	var db boltkv.DB
+	var tracer *testTracer
+	BeforeEach(func() {
+		ctx = context.Background()
+		tracer = &testTracer{}
+		tempDB, err := boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		db = boltkv.NewDB(tempDB.DB(), boltkv.WithHook(boltkv.NewTracingHook(tracer)))
+	})
+	AfterEach(func() {
+		_ = db.Close()
+		_ = db.Remove()
+	})
+	It("emits a span per operation", func() {
+		err := db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.CreateBucket(ctx, libkv.NewBucketName("my-bucket"))
+			Expect(err).To(BeNil())
+			return bucket.Put(ctx, []byte("a"), []byte("1"))
+		})
+		Expect(err).To(BeNil())
+		Expect(tracer.spans).To(HaveLen(2))
+		Expect(tracer.spans[0].name).To(Equal("boltkv.Update"))
+		Expect(tracer.spans[0].ended).To(BeTrue())
+		Expect(tracer.spans[1].name).To(Equal("boltkv.Put"))
+		Expect(tracer.spans[1].attributes).To(HaveKeyWithValue("boltkv.bucket", "my-bucket"))
+		Expect(tracer.spans[1].ended).To(BeTrue())
+	})
+	It("records errors on the span", func() {
+		err := db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			_, err := tx.Bucket(ctx, libkv.NewBucketName("missing"))
+			return err
+		})
+		Expect(err).NotTo(BeNil())
+		Expect(tracer.spans).To(HaveLen(2))
+		Expect(tracer.spans[0].err).NotTo(BeNil())
+		Expect(tracer.spans[1].name).To(Equal("boltkv.Bucket"))
+		Expect(tracer.spans[1].err).NotTo(BeNil())
+	})
+})
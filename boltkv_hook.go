@@ -0,0 +1,141 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv
+
+import (
+	"context"
+	"time"
+
+	libkv "github.com/bborbe/kv"
+)
+
+// Operation identifies the boltkv call a Hook is notified about.
+type Operation string
+
+const (
+	OperationUpdate   Operation = "Update"
+	OperationView     Operation = "View"
+	OperationBucket   Operation = "Bucket"
+	OperationGet      Operation = "Get"
+	OperationPut      Operation = "Put"
+	OperationDelete   Operation = "Delete"
+	OperationIterator Operation = "Iterator"
+)
+
+func (o Operation) String() string {
+	return string(o)
+}
+
+// Hook is notified when a boltkv operation starts and ends.
+// The context returned by Start is passed to the matching End call and,
+// for Update and View, to the transaction callback, so nested operations
+// see it as their parent. BucketName is empty for Update and View.
+// Iterator operations start when the iterator is created and end on Close.
+//
+//counterfeiter:generate -o mocks/boltkv-hook.go --fake-name BoltkvHook . Hook
+type Hook interface {
+	Start(ctx context.Context, op Operation, bucketName libkv.BucketName) context.Context
+	End(ctx context.Context, op Operation, bucketName libkv.BucketName, err error)
+}
+
+// HookFuncs adapts plain functions to Hook and SlowTransactionHook.
+// Nil functions are skipped.
+type HookFuncs struct {
+	StartFunc func(
+		ctx context.Context,
+		op Operation,
+		bucketName libkv.BucketName,
+	) context.Context
+	EndFunc             func(ctx context.Context, op Operation, bucketName libkv.BucketName, err error)
+	SlowTransactionFunc func(ctx context.Context, info TransactionInfo, duration time.Duration)
+}
+
+func (h HookFuncs) Start(
+	ctx context.Context,
+	op Operation,
+	bucketName libkv.BucketName,
+) context.Context {
+	if h.StartFunc == nil {
+		return ctx
+	}
+	return h.StartFunc(ctx, op, bucketName)
+}
+
+func (h HookFuncs) End(
+	ctx context.Context,
+	op Operation,
+	bucketName libkv.BucketName,
+	err error,
+) {
+	if h.EndFunc == nil {
+		return
+	}
+	h.EndFunc(ctx, op, bucketName, err)
+}
+
+func (h HookFuncs) SlowTransaction(
+	ctx context.Context,
+	info TransactionInfo,
+	duration time.Duration,
+) {
+	if h.SlowTransactionFunc == nil {
+		return
+	}
+	h.SlowTransactionFunc(ctx, info, duration)
+}
+
+// NewHookList returns a Hook that calls all given hooks.
+// Start is called in order, End in reverse order.
+func NewHookList(hooks ...Hook) Hook {
+	return hookList(hooks)
+}
+
+type hookList []Hook
+
+func (h hookList) Start(
+	ctx context.Context,
+	op Operation,
+	bucketName libkv.BucketName,
+) context.Context {
+	for _, hook := range h {
+		ctx = hook.Start(ctx, op, bucketName)
+	}
+	return ctx
+}
+
+func (h hookList) SlowTransaction(
+	ctx context.Context,
+	info TransactionInfo,
+	duration time.Duration,
+) {
+	for _, hook := range h {
+		if slowTransactionHook, ok := hook.(SlowTransactionHook); ok {
+			slowTransactionHook.SlowTransaction(ctx, info, duration)
+		}
+	}
+}
+
+func (h hookList) End(
+	ctx context.Context,
+	op Operation,
+	bucketName libkv.BucketName,
+	err error,
+) {
+	for i := len(h) - 1; i >= 0; i-- {
+		h[i].End(ctx, op, bucketName, err)
+	}
+}
+
+type noopHook struct{}
+
+func (noopHook) Start(
+	ctx context.Context,
+	op Operation,
+	bucketName libkv.BucketName,
+) context.Context {
+	return ctx
+}
+
+func (noopHook) End(ctx context.Context, op Operation, bucketName libkv.BucketName, err error) {}
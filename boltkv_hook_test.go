@@ -0,0 +1,146 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+)
+
+var _ = Describe("Hook", func() {
+	var ctx context.Context
+	var db boltkv.DB
+	var events []string
+	var mux sync.Mutex
+	var bucketName libkv.BucketName
+	BeforeEach(func() {
+		ctx = context.Background()
+		events = nil
+		bucketName = libkv.NewBucketName("my-bucket")
+		tempDB, err := boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		db = boltkv.NewDB(tempDB.DB(), boltkv.WithHook(boltkv.HookFuncs{
+			StartFunc: func(
+				ctx context.Context,
+				op boltkv.Operation,
+				name libkv.BucketName,
+			) context.Context {
+				mux.Lock()
+				defer mux.Unlock()
+				events = append(events, fmt.Sprintf("start %s %s", op, name))
+				return context.WithValue(ctx, op, true)
+			},
+			EndFunc: func(ctx context.Context, op boltkv.Operation, name libkv.BucketName, err error) {
+				mux.Lock()
+				defer mux.Unlock()
+				Expect(ctx.Value(op)).To(Equal(true))
+				events = append(events, fmt.Sprintf("end %s %s %v", op, name, err != nil))
+			},
+		}))
+	})
+	AfterEach(func() {
+		_ = db.Close()
+		_ = db.Remove()
+	})
+	It("reports update, bucket, put and delete", func() {
+		err := db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			Expect(ctx.Value(boltkv.OperationUpdate)).To(Equal(true))
+			bucket, err := tx.CreateBucket(ctx, bucketName)
+			Expect(err).To(BeNil())
+			Expect(bucket.Put(ctx, []byte("a"), []byte("1"))).To(Succeed())
+			Expect(bucket.Delete(ctx, []byte("a"))).To(Succeed())
+			return nil
+		})
+		Expect(err).To(BeNil())
+		Expect(events).To(Equal([]string{
+			"start Update ",
+			"start Put my-bucket",
+			"end Put my-bucket false",
+			"start Delete my-bucket",
+			"end Delete my-bucket false",
+			"end Update  false",
+		}))
+	})
+	It("reports view, bucket, get and iterator lifetime", func() {
+		Expect(db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			_, err := tx.CreateBucket(ctx, bucketName)
+			return err
+		})).To(Succeed())
+		events = nil
+		err := db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			Expect(err).To(BeNil())
+			_, err = bucket.Get(ctx, []byte("a"))
+			Expect(err).To(BeNil())
+			it := bucket.Iterator()
+			it.Rewind()
+			it.Close()
+			it.Close()
+			return nil
+		})
+		Expect(err).To(BeNil())
+		Expect(events).To(Equal([]string{
+			"start View ",
+			"start Bucket my-bucket",
+			"end Bucket my-bucket false",
+			"start Get my-bucket",
+			"end Get my-bucket false",
+			"start Iterator my-bucket",
+			"end Iterator my-bucket false",
+			"end View  false",
+		}))
+	})
+	It("reports errors", func() {
+		err := db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			_, err := tx.Bucket(ctx, bucketName)
+			return err
+		})
+		Expect(errors.Is(err, libkv.BucketNotFoundError)).To(BeTrue())
+		Expect(events).To(Equal([]string{
+			"start View ",
+			"start Bucket my-bucket",
+			"end Bucket my-bucket true",
+			"end View  true",
+		}))
+	})
+})
+
+var _ = Describe("NewHookList", func() {
+	It("calls start in order and end in reverse order", func() {
+		var calls []string
+		newHook := func(name string) boltkv.Hook {
+			return boltkv.HookFuncs{
+				StartFunc: func(
+					ctx context.Context,
+					op boltkv.Operation,
+					bucketName libkv.BucketName,
+				) context.Context {
+					calls = append(calls, "start "+name)
+					return ctx
+				},
+				EndFunc: func(
+					ctx context.Context,
+					op boltkv.Operation,
+					bucketName libkv.BucketName,
+					err error,
+				) {
+					calls = append(calls, "end "+name)
+				},
+			}
+		}
+		hook := boltkv.NewHookList(newHook("a"), newHook("b"))
+		ctx := hook.Start(context.Background(), boltkv.OperationView, nil)
+		hook.End(ctx, boltkv.OperationView, nil, nil)
+		Expect(calls).To(Equal([]string{"start a", "start b", "end b", "end a"}))
+	})
+})
@@ -0,0 +1,240 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	"github.com/golang/glog"
+	bolt "go.etcd.io/bbolt"
+)
+
+// ImportMode defines how Import handles keys that already exist.
+type ImportMode string
+
+const (
+	// ImportModeMerge keeps the existing value and skips the imported one.
+	ImportModeMerge ImportMode = "merge"
+	// ImportModeOverwrite replaces the existing value with the imported one.
+	ImportModeOverwrite ImportMode = "overwrite"
+	// ImportModeFailOnConflict fails if an existing value differs from the imported one.
+	ImportModeFailOnConflict ImportMode = "fail-on-conflict"
+)
+
+// ImportConflictError is returned by Import in ImportModeFailOnConflict.
+var ImportConflictError = errors.New(context.Background(), "import conflict")
+
+// DefaultImportBatchSize is the number of records written per Update.
+const DefaultImportBatchSize = 1000
+
+// ImportOptions configures Import.
+type ImportOptions struct {
+	// Mode defaults to ImportModeFailOnConflict.
+	Mode ImportMode
+	// BatchSize is the number of records written per Update transaction,
+	// defaults to DefaultImportBatchSize.
+	BatchSize int
+}
+
+// ImportResult counts what Import did.
+type ImportResult struct {
+	Written int64
+	Skipped int64
+}
+
+// Import reads JSON Lines written by Export from r and writes them to db.
+// Records are written in batches of ImportOptions.BatchSize, each batch in
+// its own Update transaction. The import is therefore not atomic: if it fails,
+// all batches before the failing one stay committed. Records of top-level
+// buckets are written through libkv.Bucket and run hooks, fingerprints and the
+// replication log; records of nested buckets are written through bolt directly
+// and bypass them.
+func Import(ctx context.Context, db DB, r io.Reader, opts ImportOptions) (ImportResult, error) {
+	if opts.Mode == "" {
+		opts.Mode = ImportModeFailOnConflict
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultImportBatchSize
+	}
+	var result ImportResult
+	decoder := json.NewDecoder(bufio.NewReader(r))
+	batch := make([]Record, 0, opts.BatchSize)
+	for {
+		var record Record
+		err := decoder.Decode(&record)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return result, errors.Wrapf(ctx, err, "decode record failed")
+		}
+		batch = append(batch, record)
+		if len(batch) < opts.BatchSize {
+			continue
+		}
+		if err := importBatch(ctx, db, batch, opts.Mode, &result); err != nil {
+			return result, errors.Wrapf(ctx, err, "import batch failed")
+		}
+		batch = batch[:0]
+	}
+	if err := importBatch(ctx, db, batch, opts.Mode, &result); err != nil {
+		return result, errors.Wrapf(ctx, err, "import batch failed")
+	}
+	return result, nil
+}
+
+func importBatch(
+	ctx context.Context,
+	db DB,
+	batch []Record,
+	mode ImportMode,
+	result *ImportResult,
+) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	var written, skipped int64
+	err := db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		for _, record := range batch {
+			bucketPath, key, value, err := record.Decode(ctx)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "decode record failed")
+			}
+			target, err := openImportTarget(ctx, tx, bucketPath)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "create bucket failed")
+			}
+			if record.IsBucket() {
+				continue
+			}
+			existing, err := target.get(ctx, key)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "get failed")
+			}
+			if existing != nil {
+				switch mode {
+				case ImportModeMerge:
+					skipped++
+					continue
+				case ImportModeFailOnConflict:
+					if bytes.Equal(existing, value) {
+						skipped++
+						continue
+					}
+					return errors.Wrapf(ctx, ImportConflictError, "key %q exists with different value", key)
+				case ImportModeOverwrite:
+				default:
+					return errors.Errorf(ctx, "unknown import mode '%s'", mode)
+				}
+			}
+			if err := target.put(ctx, key, value); err != nil {
+				return errors.Wrapf(ctx, err, "put failed")
+			}
+			written++
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(ctx, err, "update failed")
+	}
+	result.Written += written
+	result.Skipped += skipped
+	glog.V(3).Infof(
+		"imported batch of %d records (written %d, skipped %d)",
+		len(batch),
+		written,
+		skipped,
+	)
+	return nil
+}
+
+// importTarget is the bucket a record is written to.
+type importTarget interface {
+	// get returns the value of key, nil if it does not exist.
+	get(ctx context.Context, key []byte) ([]byte, error)
+	put(ctx context.Context, key []byte, value []byte) error
+}
+
+// openImportTarget creates the bucket path. Top-level buckets are written
+// through libkv, so hooks, fingerprints and the replication log see the import.
+// libkv has no nested buckets, they are written through the raw bolt.Tx.
+func openImportTarget(
+	ctx context.Context,
+	tx libkv.Tx,
+	bucketPath [][]byte,
+) (importTarget, error) {
+	if len(bucketPath) == 1 {
+		bucket, err := tx.CreateBucketIfNotExists(ctx, bucketPath[0])
+		if err != nil {
+			return nil, err
+		}
+		return libkvImportTarget{bucket: bucket}, nil
+	}
+	boltTx, ok := tx.(Tx)
+	if !ok {
+		return nil, errors.Errorf(ctx, "tx is not a bolt transaction")
+	}
+	bucket, err := createBucketPath(boltTx.Tx(), bucketPath)
+	if err != nil {
+		return nil, err
+	}
+	return boltImportTarget{bucket: bucket}, nil
+}
+
+type libkvImportTarget struct {
+	bucket libkv.Bucket
+}
+
+func (t libkvImportTarget) get(ctx context.Context, key []byte) ([]byte, error) {
+	item, err := t.bucket.Get(ctx, key)
+	if err != nil || !item.Exists() {
+		return nil, err
+	}
+	var result []byte
+	err = item.Value(func(value []byte) error {
+		result = append([]byte{}, value...)
+		return nil
+	})
+	return result, err
+}
+
+func (t libkvImportTarget) put(ctx context.Context, key []byte, value []byte) error {
+	return t.bucket.Put(ctx, key, value)
+}
+
+type boltImportTarget struct {
+	bucket *bolt.Bucket
+}
+
+func (t boltImportTarget) get(ctx context.Context, key []byte) ([]byte, error) {
+	return t.bucket.Get(key), nil
+}
+
+func (t boltImportTarget) put(ctx context.Context, key []byte, value []byte) error {
+	return t.bucket.Put(key, value)
+}
+
+func createBucketPath(tx *bolt.Tx, bucketPath [][]byte) (*bolt.Bucket, error) {
+	if len(bucketPath) == 0 {
+		return nil, bolt.ErrBucketNameRequired
+	}
+	bucket, err := tx.CreateBucketIfNotExists(bucketPath[0])
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range bucketPath[1:] {
+		bucket, err = bucket.CreateBucketIfNotExists(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return bucket, nil
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bborbe/boltkv"
+)
+
+var _ = Describe("Import", func() {
+	var ctx context.Context
+	var db boltkv.DB
+	var bucketName libkv.BucketName
+	get := func(key string) string {
+		var result string
+		Expect(db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			Expect(err).To(BeNil())
+			item, err := bucket.Get(ctx, []byte(key))
+			Expect(err).To(BeNil())
+			return item.Value(func(value []byte) error {
+				result = string(value)
+				return nil
+			})
+		})).To(Succeed())
+		return result
+	}
+	BeforeEach(func() {
+		ctx = context.Background()
+		bucketName = libkv.NewBucketName("a")
+		var err error
+		db, err = boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		Expect(db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.CreateBucket(ctx, bucketName)
+			Expect(err).To(BeNil())
+			return bucket.Put(ctx, []byte("k1"), []byte("old"))
+		})).To(Succeed())
+	})
+	AfterEach(func() {
+		_ = db.Close()
+		_ = db.Remove()
+	})
+	input := `{"bucket":["a"],"key":"k1","value":"new","encoding":"utf8"}
+{"bucket":["a"],"key":"k2","value":"v2","encoding":"utf8"}
+`
+	It("keeps existing values in merge mode", func() {
+		result, err := boltkv.Import(ctx, db, strings.NewReader(input), boltkv.ImportOptions{
+			Mode: boltkv.ImportModeMerge,
+		})
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal(boltkv.ImportResult{Written: 1, Skipped: 1}))
+		Expect(get("k1")).To(Equal("old"))
+		Expect(get("k2")).To(Equal("v2"))
+	})
+	It("replaces existing values in overwrite mode", func() {
+		result, err := boltkv.Import(ctx, db, strings.NewReader(input), boltkv.ImportOptions{
+			Mode: boltkv.ImportModeOverwrite,
+		})
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal(boltkv.ImportResult{Written: 2}))
+		Expect(get("k1")).To(Equal("new"))
+		Expect(get("k2")).To(Equal("v2"))
+	})
+	It("fails on conflict by default", func() {
+		_, err := boltkv.Import(ctx, db, strings.NewReader(input), boltkv.ImportOptions{})
+		Expect(errors.Is(err, boltkv.ImportConflictError)).To(BeTrue())
+		Expect(get("k1")).To(Equal("old"))
+		Expect(get("k2")).To(Equal(""))
+	})
+	It("accepts equal values in fail-on-conflict mode", func() {
+		result, err := boltkv.Import(
+			ctx,
+			db,
+			strings.NewReader(`{"bucket":["a"],"key":"k1","value":"old","encoding":"utf8"}`),
+			boltkv.ImportOptions{},
+		)
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal(boltkv.ImportResult{Skipped: 1}))
+	})
+	It("writes in batches", func() {
+		lines := &strings.Builder{}
+		for i := 0; i < 25; i++ {
+			fmt.Fprintf(lines, `{"bucket":["b"],"key":"k%02d","value":"v","encoding":"utf8"}`+"\n", i)
+		}
+		var updates int
+		hooked := boltkv.NewDB(db.DB(), boltkv.WithHook(boltkv.HookFuncs{
+			EndFunc: func(ctx context.Context, op boltkv.Operation, name libkv.BucketName, err error) {
+				if op == boltkv.OperationUpdate {
+					updates++
+				}
+			},
+		}))
+		result, err := boltkv.Import(ctx, hooked, strings.NewReader(lines.String()), boltkv.ImportOptions{
+			BatchSize: 10,
+		})
+		Expect(err).To(BeNil())
+		Expect(result.Written).To(Equal(int64(25)))
+		Expect(updates).To(Equal(3))
+	})
+	It("writes top-level buckets through libkv", func() {
+		tracked := boltkv.NewDB(db.DB(), boltkv.WithFingerprint(bucketName))
+		_, err := boltkv.Import(ctx, tracked, strings.NewReader(input), boltkv.ImportOptions{
+			Mode: boltkv.ImportModeOverwrite,
+		})
+		Expect(err).To(BeNil())
+		fingerprint, err := boltkv.BucketFingerprint(ctx, tracked, bucketName)
+		Expect(err).To(BeNil())
+		Expect(fingerprint.Keys).To(Equal(int64(2)))
+		Expect(db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			computed, err := boltkv.ComputeFingerprint(
+				ctx,
+				tx,
+				bucketName,
+				boltkv.FingerprintAlgorithmSHA256Sum,
+			)
+			Expect(err).To(BeNil())
+			Expect(fingerprint).To(Equal(computed))
+			return nil
+		})).To(Succeed())
+	})
+	It("writes nested buckets through bolt, bypassing hooks", func() {
+		var puts []string
+		hooked := boltkv.NewDB(db.DB(), boltkv.WithHook(boltkv.HookFuncs{
+			EndFunc: func(ctx context.Context, op boltkv.Operation, name libkv.BucketName, err error) {
+				if op == boltkv.OperationPut {
+					puts = append(puts, name.String())
+				}
+			},
+		}))
+		_, err := boltkv.Import(ctx, hooked, strings.NewReader(
+			`{"bucket":["a"],"key":"k2","value":"v2","encoding":"utf8"}
+{"bucket":["a","nested"],"key":"k3","value":"v3","encoding":"utf8"}
+`), boltkv.ImportOptions{})
+		Expect(err).To(BeNil())
+		Expect(puts).To(Equal([]string{"a"}))
+		Expect(db.DB().View(func(tx *bolt.Tx) error {
+			Expect(tx.Bucket(bucketName).Bucket([]byte("nested")).Get([]byte("k3"))).
+				To(Equal([]byte("v3")))
+			return nil
+		})).To(Succeed())
+	})
+	It("returns error on invalid input", func() {
+		_, err := boltkv.Import(ctx, db, strings.NewReader("{"), boltkv.ImportOptions{})
+		Expect(err).NotTo(BeNil())
+	})
+})
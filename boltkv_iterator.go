@@ -9,6 +9,7 @@ import (
 	bolt "go.etcd.io/bbolt"
 )
 
+//counterfeiter:generate -o mocks/boltkv-iterator.go --fake-name BoltkvIterator . Iterator
 type Iterator interface {
 	libkv.Iterator
 	Cursor() *bolt.Cursor
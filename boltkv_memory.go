@@ -0,0 +1,490 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	bolt "go.etcd.io/bbolt"
+)
+
+// MemoryBackend is reported in libkv.Stats of a memory DB.
+const MemoryBackend = "memory"
+
+// NewMemoryDB returns a libkv.DB holding all data in memory with the semantics
+// of the bolt backend: keys are sorted bytewise, buckets return the libkv
+// bucket errors, writes inside View fail with bolt.ErrTxNotWritable and
+// invalid keys with the bolt errors, reverse iterators seek to the last key
+// <= key, and a value put as nil reads as nil until the Update commits and as
+// empty afterwards. Iterators behave like bolt cursors on a single page when
+// deleting while iterating, see memoryIterator. Like bolt, Updates are
+// serialized and each View sees the data committed before it started. Nested
+// buckets do not exist, because libkv cannot create them. Hooks, fingerprints
+// and the replication log are not supported. Remove drops all data.
+func NewMemoryDB() libkv.DB {
+	return &memoryDB{
+		data: map[string]*memoryBucketData{},
+	}
+}
+
+type memoryDB struct {
+	// writer serializes Updates
+	writer sync.Mutex
+
+	mux    sync.RWMutex
+	data   map[string]*memoryBucketData
+	closed bool
+}
+
+// memoryBucketData holds the sorted pairs of a bucket. Committed data is never
+// modified, an Update copies a bucket before its first write.
+type memoryBucketData struct {
+	keys   [][]byte
+	values [][]byte
+}
+
+func (m *memoryBucketData) clone() *memoryBucketData {
+	return &memoryBucketData{
+		keys:   append([][]byte(nil), m.keys...),
+		values: append([][]byte(nil), m.values...),
+	}
+}
+
+// search returns the index of the first key >= key.
+func (m *memoryBucketData) search(key []byte) int {
+	return sort.Search(len(m.keys), func(i int) bool {
+		return bytes.Compare(m.keys[i], key) >= 0
+	})
+}
+
+func (m *memoryBucketData) get(key []byte) ([]byte, bool) {
+	i := m.search(key)
+	if i < len(m.keys) && bytes.Equal(m.keys[i], key) {
+		return m.values[i], true
+	}
+	return nil, false
+}
+
+func (m *memoryBucketData) put(key []byte, value []byte) {
+	i := m.search(key)
+	if i < len(m.keys) && bytes.Equal(m.keys[i], key) {
+		m.values[i] = value
+		return
+	}
+	m.keys = append(m.keys, nil)
+	m.values = append(m.values, nil)
+	copy(m.keys[i+1:], m.keys[i:])
+	copy(m.values[i+1:], m.values[i:])
+	m.keys[i], m.values[i] = key, value
+}
+
+func (m *memoryBucketData) delete(key []byte) {
+	i := m.search(key)
+	if i < len(m.keys) && bytes.Equal(m.keys[i], key) {
+		m.keys = append(m.keys[:i], m.keys[i+1:]...)
+		m.values = append(m.values[:i], m.values[i+1:]...)
+	}
+}
+
+func (m *memoryDB) snapshot(ctx context.Context) (map[string]*memoryBucketData, error) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	if m.closed {
+		return nil, errors.Wrapf(ctx, bolt.ErrDatabaseNotOpen, "db closed")
+	}
+	return m.data, nil
+}
+
+func (m *memoryDB) Update(
+	ctx context.Context,
+	fn func(ctx context.Context, tx libkv.Tx) error,
+) error {
+	if IsTransactionOpen(ctx) {
+		return errors.Wrapf(ctx, libkv.TransactionAlreadyOpenError, "transaction already open")
+	}
+	m.writer.Lock()
+	defer m.writer.Unlock()
+	data, err := m.snapshot(ctx)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "db update failed")
+	}
+	tx := newMemoryTx(data, true)
+	if err := fn(SetOpenState(ctx), tx); err != nil {
+		return errors.Wrapf(ctx, err, "db update failed")
+	}
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.closed {
+		return errors.Wrapf(ctx, bolt.ErrDatabaseNotOpen, "db update failed")
+	}
+	m.data = tx.commit()
+	return nil
+}
+
+func (m *memoryDB) View(
+	ctx context.Context,
+	fn func(ctx context.Context, tx libkv.Tx) error,
+) error {
+	if IsTransactionOpen(ctx) {
+		return errors.Wrapf(ctx, libkv.TransactionAlreadyOpenError, "transaction already open")
+	}
+	data, err := m.snapshot(ctx)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "db view failed")
+	}
+	if err := fn(SetOpenState(ctx), newMemoryTx(data, false)); err != nil {
+		return errors.Wrapf(ctx, err, "db view failed")
+	}
+	return nil
+}
+
+func (m *memoryDB) Sync() error {
+	return nil
+}
+
+func (m *memoryDB) Close() error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.closed = true
+	return nil
+}
+
+func (m *memoryDB) Remove() error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.data = map[string]*memoryBucketData{}
+	return nil
+}
+
+// Stats returns the bucket names, SizeB is the total length of keys and values.
+func (m *memoryDB) Stats(ctx context.Context) (*libkv.Stats, error) {
+	return m.stats(ctx, false)
+}
+
+// StatsDetailed returns Stats plus per-bucket KeyCount and SizeB.
+func (m *memoryDB) StatsDetailed(ctx context.Context) (*libkv.Stats, error) {
+	return m.stats(ctx, true)
+}
+
+func (m *memoryDB) stats(ctx context.Context, detailed bool) (*libkv.Stats, error) {
+	data, err := m.snapshot(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "stats failed")
+	}
+	s := &libkv.Stats{Backend: MemoryBackend, Detailed: detailed}
+	for _, name := range sortedBucketNames(data) {
+		bucketStats := libkv.BucketStats{Name: name}
+		var size int64
+		for i, key := range data[string(name)].keys {
+			size += int64(len(key) + len(data[string(name)].values[i]))
+		}
+		s.SizeB += size
+		if detailed {
+			bucketStats.KeyCount = int64(len(data[string(name)].keys))
+			bucketStats.SizeB = size
+		}
+		s.Buckets = append(s.Buckets, bucketStats)
+	}
+	return s, nil
+}
+
+func sortedBucketNames(data map[string]*memoryBucketData) libkv.BucketNames {
+	result := make(libkv.BucketNames, 0, len(data))
+	for name := range data {
+		result = append(result, libkv.BucketName(name))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return bytes.Compare(result[i], result[j]) < 0
+	})
+	return result
+}
+
+func newMemoryTx(data map[string]*memoryBucketData, writable bool) *memoryTx {
+	t := &memoryTx{
+		writable: writable,
+		data:     data,
+		owned:    map[*memoryBucketData]bool{},
+		cache:    map[string]*memoryBucket{},
+	}
+	if writable {
+		t.data = make(map[string]*memoryBucketData, len(data))
+		for name, bucketData := range data {
+			t.data[name] = bucketData
+		}
+	}
+	return t
+}
+
+type memoryTx struct {
+	writable bool
+
+	mux  sync.Mutex
+	data map[string]*memoryBucketData
+	// owned contains the bucket data copied or created by this tx
+	owned map[*memoryBucketData]bool
+	cache map[string]*memoryBucket
+}
+
+// commit returns the data of the tx, values put as nil become empty like in bolt.
+func (t *memoryTx) commit() map[string]*memoryBucketData {
+	for bucketData := range t.owned {
+		for i, value := range bucketData.values {
+			if value == nil {
+				bucketData.values[i] = []byte{}
+			}
+		}
+	}
+	return t.data
+}
+
+func (t *memoryTx) ListBucketNames(ctx context.Context) (libkv.BucketNames, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	result := libkv.BucketNames{}
+	for _, name := range sortedBucketNames(t.data) {
+		if !IsInternalBucket(name) {
+			result = append(result, name)
+		}
+	}
+	return result, nil
+}
+
+func (t *memoryTx) Bucket(ctx context.Context, name libkv.BucketName) (libkv.Bucket, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if bucket, ok := t.cache[name.String()]; ok {
+		return bucket, nil
+	}
+	bucketData, ok := t.data[name.String()]
+	if !ok {
+		return nil, errors.Wrapf(ctx, libkv.BucketNotFoundError, "bucket %s not found", name)
+	}
+	return t.newBucket(name, bucketData), nil
+}
+
+func (t *memoryTx) CreateBucket(ctx context.Context, name libkv.BucketName) (libkv.Bucket, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if err := t.checkCreate(ctx, name); err != nil {
+		return nil, errors.Wrapf(ctx, err, "create bucket failed")
+	}
+	if _, ok := t.data[name.String()]; ok {
+		return nil, errors.Wrapf(
+			ctx,
+			libkv.BucketAlreadyExistsError,
+			"bucket already exists: %v",
+			bolt.ErrBucketExists,
+		)
+	}
+	return t.create(name), nil
+}
+
+func (t *memoryTx) CreateBucketIfNotExists(
+	ctx context.Context,
+	name libkv.BucketName,
+) (libkv.Bucket, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if bucket, ok := t.cache[name.String()]; ok {
+		return bucket, nil
+	}
+	if err := t.checkCreate(ctx, name); err != nil {
+		return nil, errors.Wrapf(ctx, err, "create bucket if not exists failed")
+	}
+	if bucketData, ok := t.data[name.String()]; ok {
+		return t.newBucket(name, bucketData), nil
+	}
+	return t.create(name), nil
+}
+
+// checkCreate returns the errors of bolt's CreateBucket besides ErrBucketExists.
+func (t *memoryTx) checkCreate(ctx context.Context, name libkv.BucketName) error {
+	if !t.writable {
+		return errors.Wrapf(ctx, bolt.ErrTxNotWritable, "create bucket %s", name)
+	}
+	if len(name) == 0 {
+		return errors.Wrapf(ctx, bolt.ErrBucketNameRequired, "create bucket")
+	}
+	return nil
+}
+
+func (t *memoryTx) create(name libkv.BucketName) *memoryBucket {
+	bucketData := &memoryBucketData{}
+	t.data[name.String()] = bucketData
+	t.owned[bucketData] = true
+	return t.newBucket(name, bucketData)
+}
+
+func (t *memoryTx) DeleteBucket(ctx context.Context, name libkv.BucketName) error {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if !t.writable {
+		return errors.Wrapf(ctx, bolt.ErrTxNotWritable, "delete bucket failed")
+	}
+	if _, ok := t.data[name.String()]; !ok {
+		return errors.Wrapf(
+			ctx,
+			libkv.BucketNotFoundError,
+			"delete bucket failed: %v",
+			bolt.ErrBucketNotFound,
+		)
+	}
+	delete(t.data, name.String())
+	delete(t.cache, name.String())
+	return nil
+}
+
+func (t *memoryTx) newBucket(name libkv.BucketName, bucketData *memoryBucketData) *memoryBucket {
+	bucket := &memoryBucket{tx: t, name: name.String(), data: bucketData}
+	t.cache[name.String()] = bucket
+	return bucket
+}
+
+type memoryBucket struct {
+	tx   *memoryTx
+	name string
+	// data is replaced by a copy on the first write of the tx
+	data *memoryBucketData
+}
+
+// writableData returns the data of the bucket owned by the tx.
+func (b *memoryBucket) writableData(ctx context.Context) (*memoryBucketData, error) {
+	if !b.tx.writable {
+		return nil, errors.Wrapf(ctx, bolt.ErrTxNotWritable, "write bucket %s", b.name)
+	}
+	b.tx.mux.Lock()
+	defer b.tx.mux.Unlock()
+	if !b.tx.owned[b.data] {
+		bucketData := b.data.clone()
+		if b.tx.data[b.name] == b.data {
+			b.tx.data[b.name] = bucketData
+		}
+		b.data = bucketData
+		b.tx.owned[bucketData] = true
+	}
+	return b.data, nil
+}
+
+func (b *memoryBucket) Put(ctx context.Context, key []byte, value []byte) error {
+	bucketData, err := b.writableData(ctx)
+	if err != nil {
+		return err
+	}
+	switch {
+	case len(key) == 0:
+		return errors.Wrapf(ctx, bolt.ErrKeyRequired, "put failed")
+	case len(key) > bolt.MaxKeySize:
+		return errors.Wrapf(ctx, bolt.ErrKeyTooLarge, "put failed")
+	case int64(len(value)) > bolt.MaxValueSize:
+		return errors.Wrapf(ctx, bolt.ErrValueTooLarge, "put failed")
+	}
+	if value != nil {
+		value = bytes.Clone(value)
+	}
+	bucketData.put(bytes.Clone(key), value)
+	return nil
+}
+
+func (b *memoryBucket) Get(ctx context.Context, key []byte) (libkv.Item, error) {
+	value, _ := b.current().get(key)
+	return libkv.NewByteItem(key, value), nil
+}
+
+func (b *memoryBucket) Delete(ctx context.Context, key []byte) error {
+	bucketData, err := b.writableData(ctx)
+	if err != nil {
+		return err
+	}
+	bucketData.delete(key)
+	return nil
+}
+
+func (b *memoryBucket) current() *memoryBucketData {
+	b.tx.mux.Lock()
+	defer b.tx.mux.Unlock()
+	return b.data
+}
+
+func (b *memoryBucket) Iterator() libkv.Iterator {
+	return &memoryIterator{bucket: b}
+}
+
+func (b *memoryBucket) IteratorReverse() libkv.Iterator {
+	return &memoryIterator{bucket: b, reverse: true}
+}
+
+// memoryIterator keeps a position like a bolt cursor on a single leaf page.
+// Data written earlier in the Update is iterated live, so deleting the current
+// key makes Next skip the following one. Data not yet written in the Update is
+// iterated as committed, like bolt reads an unmodified page, so writes while
+// iterating are not seen.
+type memoryIterator struct {
+	bucket  *memoryBucket
+	reverse bool
+	data    *memoryBucketData
+	index   int
+	key     []byte
+	value   []byte
+}
+
+func (i *memoryIterator) Close() {
+}
+
+func (i *memoryIterator) Item() libkv.Item {
+	return libkv.NewByteItem(i.key, i.value)
+}
+
+func (i *memoryIterator) Valid() bool {
+	return i.key != nil
+}
+
+func (i *memoryIterator) Rewind() {
+	i.data = i.bucket.current()
+	if i.reverse {
+		i.set(len(i.data.keys) - 1)
+	} else {
+		i.set(0)
+	}
+}
+
+// Seek moves to the first key >= key, or the last key <= key for reverse
+// iterators like iteratorReverse.
+func (i *memoryIterator) Seek(key []byte) {
+	i.data = i.bucket.current()
+	index := i.data.search(key)
+	if i.reverse && (index == len(i.data.keys) || !bytes.Equal(i.data.keys[index], key)) {
+		index--
+	}
+	i.set(index)
+}
+
+func (i *memoryIterator) Next() {
+	if i.key == nil {
+		return
+	}
+	if i.reverse {
+		i.set(i.index - 1)
+	} else {
+		i.set(i.index + 1)
+	}
+}
+
+func (i *memoryIterator) set(index int) {
+	i.index = index
+	if index < 0 || index >= len(i.data.keys) {
+		i.key, i.value = nil, nil
+		return
+	}
+	i.key, i.value = i.data.keys[index], i.data.values[index]
+}
@@ -0,0 +1,297 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bborbe/boltkv"
+)
+
+// describeItem formats an item as key=value, key=<nil> or <invalid>.
+func describeItem(valid bool, item libkv.Item) string {
+	if !valid {
+		return "<invalid>"
+	}
+	result := string(item.Key()) + "=<nil>"
+	if item.Exists() {
+		_ = item.Value(func(value []byte) error {
+			result = fmt.Sprintf("%s=%q", item.Key(), value)
+			return nil
+		})
+	}
+	return result
+}
+
+// semanticsTrace runs operations with edge cases on db and records their results.
+func semanticsTrace(ctx context.Context, db libkv.DB) []string {
+	var trace []string
+	record := func(name string, err error) {
+		switch {
+		case err == nil:
+			trace = append(trace, name+": ok")
+		case errors.Is(err, libkv.BucketNotFoundError):
+			trace = append(trace, name+": bucket not found")
+		case errors.Is(err, libkv.BucketAlreadyExistsError):
+			trace = append(trace, name+": bucket exists")
+		case errors.Is(err, libkv.TransactionAlreadyOpenError):
+			trace = append(trace, name+": transaction open")
+		case errors.Is(err, bolt.ErrKeyRequired):
+			trace = append(trace, name+": key required")
+		case errors.Is(err, bolt.ErrTxNotWritable):
+			trace = append(trace, name+": not writable")
+		case errors.Is(err, bolt.ErrBucketNameRequired):
+			trace = append(trace, name+": bucket name required")
+		default:
+			trace = append(trace, name+": failed")
+		}
+	}
+	get := func(name string, bucket libkv.Bucket, key string) {
+		item, err := bucket.Get(ctx, []byte(key))
+		record(name, err)
+		trace = append(trace, name+" "+describeItem(true, item))
+	}
+	seek := func(name string, it libkv.Iterator, keys ...string) {
+		for _, key := range keys {
+			it.Seek([]byte(key))
+			trace = append(trace, fmt.Sprintf("%s %q %s", name, key, describeItem(it.Valid(), it.Item())))
+		}
+		var all []string
+		for it.Rewind(); it.Valid(); it.Next() {
+			all = append(all, string(it.Item().Key()))
+		}
+		trace = append(trace, fmt.Sprintf("%s all %v", name, all))
+		it.Close()
+	}
+	record("update", db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		_, err := tx.Bucket(ctx, libkv.NewBucketName("a"))
+		record("bucket missing", err)
+		record("delete missing bucket", tx.DeleteBucket(ctx, libkv.NewBucketName("a")))
+		_, err = tx.CreateBucket(ctx, libkv.NewBucketName(""))
+		record("create empty bucket name", err)
+		bucket, err := tx.CreateBucket(ctx, libkv.NewBucketName("a"))
+		record("create", err)
+		_, err = tx.CreateBucket(ctx, libkv.NewBucketName("a"))
+		record("create existing", err)
+		_, err = tx.CreateBucketIfNotExists(ctx, libkv.NewBucketName("a"))
+		record("create if not exists", err)
+		record("put empty key", bucket.Put(ctx, []byte{}, []byte("v")))
+		record("delete missing key", bucket.Delete(ctx, []byte("zz")))
+		for _, key := range []string{"f", "b", "d"} {
+			record("put "+key, bucket.Put(ctx, []byte(key), []byte("v"+key)))
+		}
+		record("put nil", bucket.Put(ctx, []byte("n"), nil))
+		record("put empty", bucket.Put(ctx, []byte("e"), []byte{}))
+		get("get nil in update", bucket, "n")
+		get("get empty in update", bucket, "e")
+		get("get empty key", bucket, "")
+		_, err = tx.CreateBucket(ctx, libkv.NewBucketName("c"))
+		record("create c", err)
+		names, err := tx.ListBucketNames(ctx)
+		record("list", err)
+		trace = append(trace, fmt.Sprintf("names %q", names))
+		record("nested view", db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			return nil
+		}))
+		return nil
+	}))
+	record("update", db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		bucket, err := tx.Bucket(ctx, libkv.NewBucketName("a"))
+		if err != nil {
+			return err
+		}
+		record("put rolled back", bucket.Put(ctx, []byte("r"), []byte("vr")))
+		return errors.New(ctx, "rollback")
+	}))
+	record("view", db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		bucket, err := tx.Bucket(ctx, libkv.NewBucketName("a"))
+		if err != nil {
+			return err
+		}
+		get("get nil after commit", bucket, "n")
+		get("get empty after commit", bucket, "e")
+		get("get rolled back", bucket, "r")
+		record("put in view", bucket.Put(ctx, []byte("x"), []byte("vx")))
+		record("delete in view", bucket.Delete(ctx, []byte("b")))
+		_, err = tx.CreateBucketIfNotExists(ctx, libkv.NewBucketName("a"))
+		record("create in view", err)
+		record("delete bucket in view", tx.DeleteBucket(ctx, libkv.NewBucketName("a")))
+		seek("forward", bucket.Iterator(), "", "a", "b", "c", "n", "z")
+		seek("reverse", bucket.IteratorReverse(), "", "a", "b", "c", "n", "z")
+		return nil
+	}))
+	// bolt cursors skip the key after a deleted one if the bucket was written
+	// before the cursor was positioned, otherwise they read the unmodified page
+	deleteWhileIterating := func(name string, bucket libkv.Bucket, it libkv.Iterator) {
+		var visited []string
+		for it.Rewind(); it.Valid(); it.Next() {
+			key := string(it.Item().Key())
+			visited = append(visited, key)
+			record(name+" delete "+key, bucket.Delete(ctx, []byte(key)))
+		}
+		it.Close()
+		trace = append(trace, fmt.Sprintf("%s visited %v", name, visited))
+		var remaining []string
+		it = bucket.Iterator()
+		for it.Rewind(); it.Valid(); it.Next() {
+			remaining = append(remaining, string(it.Item().Key()))
+		}
+		it.Close()
+		trace = append(trace, fmt.Sprintf("%s remaining %v", name, remaining))
+	}
+	putKeys := func(name string, bucket libkv.Bucket) {
+		for _, key := range []string{"k1", "k2", "k3", "k4", "k5"} {
+			record(name+" put "+key, bucket.Put(ctx, []byte(key), []byte(key)))
+		}
+	}
+	record("update", db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		bucket, err := tx.CreateBucket(ctx, libkv.NewBucketName("w"))
+		record("create w", err)
+		putKeys("written", bucket)
+		deleteWhileIterating("written forward", bucket, bucket.Iterator())
+		putKeys("written", bucket)
+		deleteWhileIterating("written reverse", bucket, bucket.IteratorReverse())
+		putKeys("committed", bucket)
+		return nil
+	}))
+	record("update", db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		bucket, err := tx.Bucket(ctx, libkv.NewBucketName("w"))
+		if err != nil {
+			return err
+		}
+		deleteWhileIterating("committed forward", bucket, bucket.Iterator())
+		return errors.New(ctx, "rollback")
+	}))
+	record("update", db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		bucket, err := tx.Bucket(ctx, libkv.NewBucketName("w"))
+		if err != nil {
+			return err
+		}
+		deleteWhileIterating("committed reverse", bucket, bucket.IteratorReverse())
+		return errors.New(ctx, "rollback")
+	}))
+	record("update", db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		record("delete bucket", tx.DeleteBucket(ctx, libkv.NewBucketName("a")))
+		bucket, err := tx.CreateBucket(ctx, libkv.NewBucketName("a"))
+		record("recreate", err)
+		seek("recreated", bucket.Iterator())
+		return nil
+	}))
+	return trace
+}
+
+var _ = Describe("Memory", func() {
+	var ctx context.Context
+	var db libkv.DB
+	var provider libkv.ProviderFunc = func(ctx context.Context) (libkv.DB, error) {
+		return db, nil
+	}
+	BeforeEach(func() {
+		ctx = context.Background()
+		db = boltkv.NewMemoryDB()
+	})
+	AfterEach(func() {
+		_ = db.Close()
+		_ = db.Remove()
+	})
+	libkv.BucketTestSuite(provider)
+	libkv.BasicTestSuite(provider)
+	libkv.IteratorTestSuite(provider)
+	libkv.RelationStoreTestSuite(provider)
+
+	It("behaves like bolt", func() {
+		boltDB, err := boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		defer func() {
+			_ = boltDB.Close()
+			_ = boltDB.Remove()
+		}()
+		expected := semanticsTrace(ctx, boltDB)
+		Expect(expected).To(ContainElement(`get nil in update n=<nil>`))
+		Expect(expected).To(ContainElement(`get nil after commit n=""`))
+		Expect(expected).To(ContainElement(`written forward visited [k1 k3 k5]`))
+		Expect(expected).To(ContainElement(`committed forward visited [k1 k2 k3 k4 k5]`))
+		Expect(semanticsTrace(ctx, db)).To(Equal(expected))
+	})
+	It("isolates views from later updates", func() {
+		put := func(value string) error {
+			return db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+				bucket, err := tx.CreateBucketIfNotExists(ctx, libkv.NewBucketName("a"))
+				if err != nil {
+					return err
+				}
+				return bucket.Put(ctx, []byte("k"), []byte(value))
+			})
+		}
+		Expect(put("v1")).To(Succeed())
+		err := db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			Expect(put("v2")).To(Succeed())
+			bucket, err := tx.Bucket(ctx, libkv.NewBucketName("a"))
+			Expect(err).To(BeNil())
+			item, err := bucket.Get(ctx, []byte("k"))
+			Expect(err).To(BeNil())
+			Expect(describeItem(true, item)).To(Equal(`k="v1"`))
+			return nil
+		})
+		// the nested Update runs with a fresh context
+		Expect(err).To(BeNil())
+	})
+	It("serializes updates", func() {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		done := make(chan error, 2)
+		go func() {
+			done <- db.Update(context.Background(), func(ctx context.Context, tx libkv.Tx) error {
+				close(started)
+				<-release
+				return nil
+			})
+		}()
+		<-started
+		second := make(chan struct{})
+		go func() {
+			done <- db.Update(context.Background(), func(ctx context.Context, tx libkv.Tx) error {
+				close(second)
+				return nil
+			})
+		}()
+		Consistently(second, 50*time.Millisecond).ShouldNot(BeClosed())
+		close(release)
+		Eventually(second).Should(BeClosed())
+		Expect(<-done).To(BeNil())
+		Expect(<-done).To(BeNil())
+	})
+	It("returns stats", func() {
+		err := db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.CreateBucket(ctx, libkv.NewBucketName("a"))
+			if err != nil {
+				return err
+			}
+			return bucket.Put(ctx, []byte("k1"), []byte("v1"))
+		})
+		Expect(err).To(BeNil())
+		stats, err := db.StatsDetailed(ctx)
+		Expect(err).To(BeNil())
+		Expect(stats.Backend).To(Equal(boltkv.MemoryBackend))
+		Expect(stats.SizeB).To(Equal(int64(4)))
+		Expect(stats.Buckets).To(HaveLen(1))
+		Expect(stats.Buckets[0].KeyCount).To(Equal(int64(1)))
+		Expect(bytes.Equal(stats.Buckets[0].Name, []byte("a"))).To(BeTrue())
+	})
+	It("fails after close", func() {
+		Expect(db.Close()).To(Succeed())
+		err := db.View(ctx, func(ctx context.Context, tx libkv.Tx) error { return nil })
+		Expect(errors.Is(err, bolt.ErrDatabaseNotOpen)).To(BeTrue())
+	})
+})
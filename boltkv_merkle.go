@@ -0,0 +1,398 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"sort"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+)
+
+const (
+	// DefaultMerkleFanout is the number of subranges a differing range is split into.
+	DefaultMerkleFanout = 16
+	// DefaultMerkleLeafKeys is the key count up to which a differing range is transferred.
+	DefaultMerkleLeafKeys = 64
+)
+
+// KeyRange contains all keys with Start <= key < End, a nil End is unbounded.
+type KeyRange struct {
+	Start []byte `json:"start,omitempty"`
+	End   []byte `json:"end,omitempty"`
+}
+
+// Contains reports whether key is inside the range.
+func (r KeyRange) Contains(key []byte) bool {
+	return bytes.Compare(key, r.Start) >= 0 && (r.End == nil || bytes.Compare(key, r.End) < 0)
+}
+
+// RangeDigest is the key count and SHA-256 over all pairs of a KeyRange in key order.
+// Two ranges have equal content if their Keys and Hash are equal.
+type RangeDigest struct {
+	KeyRange
+	Keys int64  `json:"keys"`
+	Hash []byte `json:"hash"`
+}
+
+// Equal reports whether both digests cover the same content.
+func (d RangeDigest) Equal(other RangeDigest) bool {
+	return d.Keys == other.Keys && bytes.Equal(d.Hash, other.Hash)
+}
+
+// Digester computes digests of key ranges of a bucket. The ranges split from
+// the whole key space form a Merkle tree that two sides compare top-down,
+// descending only into differing ranges. NewDigester works on a local DB,
+// other implementations may serve it remotely.
+// Missing buckets are treated as empty.
+//
+//counterfeiter:generate -o mocks/boltkv-digester.go --fake-name BoltkvDigester . Digester
+type Digester interface {
+	// Split splits every range into up to parts subranges with about equal
+	// key counts and returns their digests, covering each range completely.
+	Split(
+		ctx context.Context,
+		bucketName libkv.BucketName,
+		ranges []KeyRange,
+		parts int,
+	) ([]RangeDigest, error)
+	// Digest returns the digest of every range.
+	Digest(
+		ctx context.Context,
+		bucketName libkv.BucketName,
+		ranges []KeyRange,
+	) ([]RangeDigest, error)
+}
+
+// NewDigester returns a Digester computing each call in a single View of db.
+func NewDigester(db libkv.DB) Digester {
+	return &digester{db: db}
+}
+
+type digester struct {
+	db libkv.DB
+}
+
+func (d *digester) Split(
+	ctx context.Context,
+	bucketName libkv.BucketName,
+	ranges []KeyRange,
+	parts int,
+) ([]RangeDigest, error) {
+	if parts < 1 {
+		return nil, errors.Errorf(ctx, "parts must be positive, got %d", parts)
+	}
+	var result []RangeDigest
+	err := d.db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		return digestBucket(ctx, tx, bucketName, func(bucket libkv.Bucket) error {
+			for _, r := range ranges {
+				var keys int64
+				err := iterateKeyRange(bucket, r, func(key []byte, value []byte) error {
+					keys++
+					return nil
+				})
+				if err != nil {
+					return errors.Wrapf(ctx, err, "count range failed")
+				}
+				digests, err := splitRange(bucket, r, (keys+int64(parts)-1)/int64(parts))
+				if err != nil {
+					return errors.Wrapf(ctx, err, "split range failed")
+				}
+				result = append(result, digests...)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "split ranges of %s failed", bucketName)
+	}
+	return result, nil
+}
+
+func (d *digester) Digest(
+	ctx context.Context,
+	bucketName libkv.BucketName,
+	ranges []KeyRange,
+) ([]RangeDigest, error) {
+	var result []RangeDigest
+	err := d.db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		return digestBucket(ctx, tx, bucketName, func(bucket libkv.Bucket) error {
+			for _, r := range ranges {
+				digests, err := splitRange(bucket, r, 0)
+				if err != nil {
+					return errors.Wrapf(ctx, err, "digest range failed")
+				}
+				result = append(result, digests...)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "digest ranges of %s failed", bucketName)
+	}
+	return result, nil
+}
+
+// digestBucket calls fn with the bucket, or with nil if it does not exist.
+func digestBucket(
+	ctx context.Context,
+	tx libkv.Tx,
+	bucketName libkv.BucketName,
+	fn func(bucket libkv.Bucket) error,
+) error {
+	bucket, err := tx.Bucket(ctx, bucketName)
+	if errors.Is(err, libkv.BucketNotFoundError) {
+		return fn(nil)
+	}
+	if err != nil {
+		return errors.Wrapf(ctx, err, "get bucket failed")
+	}
+	return fn(bucket)
+}
+
+// splitRange returns digests of consecutive subranges of r with up to
+// partKeys keys each, a single digest of r if partKeys is zero.
+func splitRange(bucket libkv.Bucket, r KeyRange, partKeys int64) ([]RangeDigest, error) {
+	var result []RangeDigest
+	current := RangeDigest{KeyRange: KeyRange{Start: r.Start}}
+	h := sha256.New()
+	err := iterateKeyRange(bucket, r, func(key []byte, value []byte) error {
+		if partKeys > 0 && current.Keys == partKeys {
+			current.End = bytes.Clone(key)
+			current.Hash = h.Sum(nil)
+			result = append(result, current)
+			current = RangeDigest{KeyRange: KeyRange{Start: bytes.Clone(key)}}
+			h.Reset()
+		}
+		writePair(h, key, value)
+		current.Keys++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	current.End = r.End
+	current.Hash = h.Sum(nil)
+	return append(result, current), nil
+}
+
+// iterateKeyRange calls fn for every pair of the range in key order, a nil bucket is empty.
+// Nested buckets are skipped, they are neither digested nor transferred.
+func iterateKeyRange(
+	bucket libkv.Bucket,
+	r KeyRange,
+	fn func(key []byte, value []byte) error,
+) error {
+	if bucket == nil {
+		return nil
+	}
+	it := bucket.Iterator()
+	defer it.Close()
+	for it.Seek(r.Start); it.Valid(); it.Next() {
+		item := it.Item()
+		if !r.Contains(item.Key()) {
+			return nil
+		}
+		if err := item.Value(func(value []byte) error {
+			if value == nil {
+				return nil
+			}
+			return fn(item.Key(), value)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MerkleOptions configures DiffRanges and Reconcile.
+type MerkleOptions struct {
+	// Fanout is the number of subranges per round, defaults to DefaultMerkleFanout.
+	Fanout int
+	// LeafKeys is the source key count up to which a differing range is
+	// reported instead of split further, defaults to DefaultMerkleLeafKeys.
+	LeafKeys int64
+}
+
+func (o MerkleOptions) withDefaults() MerkleOptions {
+	if o.Fanout < 2 {
+		o.Fanout = DefaultMerkleFanout
+	}
+	if o.LeafKeys < 1 {
+		o.LeafKeys = DefaultMerkleLeafKeys
+	}
+	return o
+}
+
+// RangeDiff is the result of DiffRanges.
+type RangeDiff struct {
+	// Ranges differ between source and target, sorted by key.
+	Ranges []KeyRange
+	// Rounds is the number of Split and Digest round trips.
+	Rounds int
+}
+
+// DiffRanges finds the ranges of the bucket whose content differs between
+// source and target. Each round splits all differing ranges with source and
+// compares their digests with target, so it needs about log(keys)/log(Fanout)
+// rounds to narrow differences down to ranges of at most LeafKeys source keys.
+func DiffRanges(
+	ctx context.Context,
+	source Digester,
+	target Digester,
+	bucketName libkv.BucketName,
+	opts MerkleOptions,
+) (RangeDiff, error) {
+	opts = opts.withDefaults()
+	var result RangeDiff
+	pending := []KeyRange{{}}
+	for len(pending) > 0 {
+		select {
+		case <-ctx.Done():
+			return RangeDiff{}, errors.Wrapf(ctx, ctx.Err(), "diff ranges canceled")
+		default:
+		}
+		result.Rounds++
+		sourceDigests, err := source.Split(ctx, bucketName, pending, opts.Fanout)
+		if err != nil {
+			return RangeDiff{}, errors.Wrapf(ctx, err, "split source failed")
+		}
+		ranges := make([]KeyRange, len(sourceDigests))
+		for i, digest := range sourceDigests {
+			ranges[i] = digest.KeyRange
+		}
+		targetDigests, err := target.Digest(ctx, bucketName, ranges)
+		if err != nil {
+			return RangeDiff{}, errors.Wrapf(ctx, err, "digest target failed")
+		}
+		if len(targetDigests) != len(sourceDigests) {
+			return RangeDiff{}, errors.Errorf(
+				ctx,
+				"target returned %d digests for %d ranges",
+				len(targetDigests),
+				len(sourceDigests),
+			)
+		}
+		pending = pending[:0:0]
+		for i, sourceDigest := range sourceDigests {
+			if sourceDigest.Equal(targetDigests[i]) {
+				continue
+			}
+			if sourceDigest.Keys <= opts.LeafKeys {
+				result.Ranges = append(result.Ranges, sourceDigest.KeyRange)
+				continue
+			}
+			pending = append(pending, sourceDigest.KeyRange)
+		}
+	}
+	sort.Slice(result.Ranges, func(i, j int) bool {
+		return bytes.Compare(result.Ranges[i].Start, result.Ranges[j].Start) < 0
+	})
+	return result, nil
+}
+
+// ReconcileResult is the result of Reconcile.
+type ReconcileResult struct {
+	RangeDiff
+	// Put is the number of keys written to target.
+	Put int64
+	// Deleted is the number of keys removed from target.
+	Deleted int64
+}
+
+// Reconcile makes the bucket of target equal to the bucket of source,
+// transferring only the keys of the ranges found by DiffRanges.
+func Reconcile(
+	ctx context.Context,
+	source libkv.DB,
+	target libkv.DB,
+	bucketName libkv.BucketName,
+	opts MerkleOptions,
+) (ReconcileResult, error) {
+	diff, err := DiffRanges(ctx, NewDigester(source), NewDigester(target), bucketName, opts)
+	if err != nil {
+		return ReconcileResult{}, errors.Wrapf(ctx, err, "diff ranges failed")
+	}
+	result := ReconcileResult{RangeDiff: diff}
+	for _, r := range diff.Ranges {
+		put, deleted, err := TransferRange(ctx, source, target, bucketName, r)
+		if err != nil {
+			return result, errors.Wrapf(ctx, err, "transfer range failed")
+		}
+		result.Put += put
+		result.Deleted += deleted
+	}
+	return result, nil
+}
+
+// TransferRange replaces the range of the bucket in target with the pairs of
+// source in one Update, writing only changed keys. It returns the number of
+// keys written and deleted.
+func TransferRange(
+	ctx context.Context,
+	source libkv.DB,
+	target libkv.DB,
+	bucketName libkv.BucketName,
+	r KeyRange,
+) (int64, int64, error) {
+	sourcePairs := map[string][]byte{}
+	err := source.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		return digestBucket(ctx, tx, bucketName, func(bucket libkv.Bucket) error {
+			return iterateKeyRange(bucket, r, func(key []byte, value []byte) error {
+				sourcePairs[string(key)] = bytes.Clone(value)
+				return nil
+			})
+		})
+	})
+	if err != nil {
+		return 0, 0, errors.Wrapf(ctx, err, "read source range failed")
+	}
+	var put, deleted int64
+	err = target.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		put, deleted = 0, 0
+		bucket, err := tx.CreateBucketIfNotExists(ctx, bucketName)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "create bucket failed")
+		}
+		var remove [][]byte
+		unchanged := map[string]bool{}
+		err = iterateKeyRange(bucket, r, func(key []byte, value []byte) error {
+			sourceValue, ok := sourcePairs[string(key)]
+			switch {
+			case !ok:
+				remove = append(remove, bytes.Clone(key))
+			case bytes.Equal(sourceValue, value):
+				unchanged[string(key)] = true
+			}
+			return nil
+		})
+		if err != nil {
+			return errors.Wrapf(ctx, err, "read target range failed")
+		}
+		for _, key := range remove {
+			if err := bucket.Delete(ctx, key); err != nil {
+				return errors.Wrapf(ctx, err, "delete key failed")
+			}
+			deleted++
+		}
+		for key, value := range sourcePairs {
+			if unchanged[key] {
+				continue
+			}
+			if err := bucket.Put(ctx, []byte(key), value); err != nil {
+				return errors.Wrapf(ctx, err, "put key failed")
+			}
+			put++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, errors.Wrapf(ctx, err, "write target range failed")
+	}
+	return put, deleted, nil
+}
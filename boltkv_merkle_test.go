@@ -0,0 +1,182 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv_test
+
+import (
+	"context"
+	"fmt"
+
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bborbe/boltkv"
+)
+
+var _ = Describe("Merkle", func() {
+	var ctx context.Context
+	var source boltkv.DB
+	var target boltkv.DB
+	var bucketName libkv.BucketName
+	key := func(i int) []byte {
+		return []byte(fmt.Sprintf("key-%05d", i))
+	}
+	update := func(db libkv.DB, fn func(bucket libkv.Bucket)) {
+		Expect(db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.CreateBucketIfNotExists(ctx, bucketName)
+			Expect(err).To(BeNil())
+			fn(bucket)
+			return nil
+		})).To(Succeed())
+	}
+	fingerprint := func(db boltkv.DB) boltkv.Fingerprint {
+		fingerprint, err := boltkv.BucketFingerprint(ctx, db, bucketName)
+		Expect(err).To(BeNil())
+		return fingerprint
+	}
+	BeforeEach(func() {
+		ctx = context.Background()
+		bucketName = libkv.NewBucketName("a")
+		var err error
+		source, err = boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		target, err = boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		for _, db := range []libkv.DB{source, target} {
+			update(db, func(bucket libkv.Bucket) {
+				for i := 0; i < 2000; i++ {
+					Expect(bucket.Put(ctx, key(i), key(i))).To(Succeed())
+				}
+			})
+		}
+	})
+	AfterEach(func() {
+		for _, db := range []boltkv.DB{source, target} {
+			_ = db.Close()
+			_ = db.Remove()
+		}
+	})
+	It("finds no ranges for equal buckets", func() {
+		diff, err := boltkv.DiffRanges(
+			ctx,
+			boltkv.NewDigester(source),
+			boltkv.NewDigester(target),
+			bucketName,
+			boltkv.MerkleOptions{},
+		)
+		Expect(err).To(BeNil())
+		Expect(diff.Ranges).To(BeEmpty())
+		Expect(diff.Rounds).To(Equal(1))
+	})
+	It("narrows a single change down to a leaf range", func() {
+		update(target, func(bucket libkv.Bucket) {
+			Expect(bucket.Put(ctx, key(1234), []byte("changed"))).To(Succeed())
+		})
+		diff, err := boltkv.DiffRanges(
+			ctx,
+			boltkv.NewDigester(source),
+			boltkv.NewDigester(target),
+			bucketName,
+			boltkv.MerkleOptions{Fanout: 4, LeafKeys: 10},
+		)
+		Expect(err).To(BeNil())
+		Expect(diff.Ranges).To(HaveLen(1))
+		Expect(diff.Ranges[0].Contains(key(1234))).To(BeTrue())
+		Expect(diff.Rounds).To(BeNumerically("<=", 6))
+
+		digests, err := boltkv.NewDigester(source).
+			Digest(ctx, bucketName, diff.Ranges)
+		Expect(err).To(BeNil())
+		Expect(digests[0].Keys).To(BeNumerically("<=", 10))
+	})
+	It("splits ranges covering them completely", func() {
+		digests, err := boltkv.NewDigester(source).Split(
+			ctx,
+			bucketName,
+			[]boltkv.KeyRange{{Start: key(100), End: key(200)}},
+			3,
+		)
+		Expect(err).To(BeNil())
+		Expect(digests).To(HaveLen(3))
+		Expect(digests[0].Start).To(Equal(key(100)))
+		Expect(digests[0].End).To(Equal(digests[1].Start))
+		Expect(digests[2].End).To(Equal(key(200)))
+		Expect(digests[0].Keys + digests[1].Keys + digests[2].Keys).To(Equal(int64(100)))
+	})
+	It("treats a missing bucket as empty", func() {
+		digests, err := boltkv.NewDigester(source).
+			Digest(ctx, libkv.NewBucketName("missing"), []boltkv.KeyRange{{}})
+		Expect(err).To(BeNil())
+		Expect(digests).To(HaveLen(1))
+		Expect(digests[0].Keys).To(BeZero())
+	})
+	It("reconciles target by transferring only differing ranges", func() {
+		update(target, func(bucket libkv.Bucket) {
+			Expect(bucket.Put(ctx, key(5), []byte("changed"))).To(Succeed())
+			Expect(bucket.Delete(ctx, key(700))).To(Succeed())
+			Expect(bucket.Put(ctx, []byte("zzz"), []byte("extra"))).To(Succeed())
+		})
+		update(source, func(bucket libkv.Bucket) {
+			Expect(bucket.Put(ctx, key(1500), []byte("new"))).To(Succeed())
+		})
+		result, err := boltkv.Reconcile(
+			ctx,
+			source,
+			target,
+			bucketName,
+			boltkv.MerkleOptions{Fanout: 8, LeafKeys: 16},
+		)
+		Expect(err).To(BeNil())
+		Expect(result.Put).To(Equal(int64(3)))
+		Expect(result.Deleted).To(Equal(int64(1)))
+		Expect(fingerprint(target)).To(Equal(fingerprint(source)))
+
+		result, err = boltkv.Reconcile(ctx, source, target, bucketName, boltkv.MerkleOptions{})
+		Expect(err).To(BeNil())
+		Expect(result.Ranges).To(BeEmpty())
+	})
+	It("skips nested buckets on either side", func() {
+		nested := func(db boltkv.DB, name string) {
+			Expect(db.DB().Update(func(tx *bolt.Tx) error {
+				_, err := tx.Bucket(bucketName).CreateBucket([]byte(name))
+				return err
+			})).To(Succeed())
+		}
+		nested(source, "key-00010-source")
+		nested(target, "key-00020-target")
+		update(target, func(bucket libkv.Bucket) {
+			Expect(bucket.Put(ctx, key(5), []byte("changed"))).To(Succeed())
+		})
+		result, err := boltkv.Reconcile(
+			ctx,
+			source,
+			target,
+			bucketName,
+			boltkv.MerkleOptions{Fanout: 8, LeafKeys: 100},
+		)
+		Expect(err).To(BeNil())
+		Expect(result.Put).To(Equal(int64(1)))
+		Expect(result.Deleted).To(BeZero())
+		Expect(fingerprint(target)).To(Equal(fingerprint(source)))
+		Expect(target.DB().View(func(tx *bolt.Tx) error {
+			Expect(tx.Bucket(bucketName).Get([]byte("key-00010-source"))).To(BeNil())
+			Expect(tx.Bucket(bucketName).Bucket([]byte("key-00020-target"))).NotTo(BeNil())
+			return nil
+		})).To(Succeed())
+	})
+	It("reconciles into an empty target", func() {
+		empty, err := boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		defer func() {
+			_ = empty.Close()
+			_ = empty.Remove()
+		}()
+		result, err := boltkv.Reconcile(ctx, source, empty, bucketName, boltkv.MerkleOptions{})
+		Expect(err).To(BeNil())
+		Expect(result.Put).To(Equal(int64(2000)))
+		Expect(fingerprint(empty)).To(Equal(fingerprint(source)))
+	})
+})
@@ -0,0 +1,61 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+
+	"github.com/bborbe/boltkv/mocks"
+)
+
+// saveUser is the code under test of the fake examples.
+func saveUser(ctx context.Context, db libkv.DB, id string, name string) error {
+	return db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(ctx, libkv.NewBucketName("users"))
+		if err != nil {
+			return errors.Wrapf(ctx, err, "create bucket failed")
+		}
+		return bucket.Put(ctx, []byte(id), []byte(name))
+	})
+}
+
+func Example_fakeDBUpdateReturns() {
+	ctx := context.Background()
+	db := &mocks.BoltkvDB{}
+	db.UpdateReturns(errors.New(ctx, "disk full"))
+
+	err := saveUser(ctx, db, "1", "alice")
+
+	fmt.Println(err)
+	fmt.Println(db.UpdateCallCount())
+	// Output:
+	// disk full
+	// 1
+}
+
+func Example_fakeDBUpdateCalls() {
+	ctx := context.Background()
+	bucket := &mocks.BoltkvBucket{}
+	bucket.PutReturns(errors.New(ctx, "put failed"))
+	tx := &mocks.BoltkvTx{}
+	tx.CreateBucketIfNotExistsReturns(bucket, nil)
+	db := &mocks.BoltkvDB{}
+	db.UpdateCalls(func(ctx context.Context, fn func(ctx context.Context, tx libkv.Tx) error) error {
+		return fn(ctx, tx)
+	})
+
+	err := saveUser(ctx, db, "1", "alice")
+
+	fmt.Println(err)
+	_, key, value := bucket.PutArgsForCall(0)
+	fmt.Println(string(key), string(value))
+	// Output:
+	// put failed
+	// 1 alice
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+)
+
+// ScanRange selects keys of a bucket for IterateRange.
+// All fields are optional, Start is inclusive and End exclusive.
+type ScanRange struct {
+	Prefix  []byte
+	Start   []byte
+	End     []byte
+	Limit   int
+	Reverse bool
+}
+
+// KeyRange returns the bounds of the keys selected by Prefix, Start and End.
+func (r ScanRange) KeyRange() KeyRange {
+	return KeyRange{Start: r.lower(), End: r.upper()}
+}
+
+// lower returns the first key of the range, nil for the first key of the bucket.
+func (r ScanRange) lower() []byte {
+	if bytes.Compare(r.Start, r.Prefix) > 0 {
+		return r.Start
+	}
+	return r.Prefix
+}
+
+// upper returns the exclusive end of the range, nil for after the last key of the bucket.
+func (r ScanRange) upper() []byte {
+	prefixEnd := prefixEnd(r.Prefix)
+	switch {
+	case r.End == nil:
+		return prefixEnd
+	case prefixEnd == nil:
+		return r.End
+	case bytes.Compare(r.End, prefixEnd) < 0:
+		return r.End
+	default:
+		return prefixEnd
+	}
+}
+
+// prefixEnd returns the first key after all keys starting with prefix, nil if there is none.
+func prefixEnd(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] != 0xff {
+			result := append([]byte{}, prefix[:i+1]...)
+			result[i]++
+			return result
+		}
+	}
+	return nil
+}
+
+// Iterator returns an iterator of bucket positioned at the first key of the
+// range, or at the last key if Reverse is set. Limit is not applied, the caller
+// stops once a key is outside KeyRange.
+func (r ScanRange) Iterator(bucket libkv.Bucket) libkv.Iterator {
+	if !r.Reverse {
+		it := bucket.Iterator()
+		if lower := r.lower(); lower != nil {
+			it.Seek(lower)
+		} else {
+			it.Rewind()
+		}
+		return it
+	}
+	it := bucket.IteratorReverse()
+	upper := r.upper()
+	if upper == nil {
+		it.Rewind()
+		return it
+	}
+	// reverse seek stops at upper itself if it exists, but upper is exclusive
+	it.Seek(upper)
+	if it.Valid() && bytes.Compare(it.Item().Key(), upper) >= 0 {
+		it.Next()
+	}
+	return it
+}
+
+// IterateRange calls fn for every key in the range in key order, or reverse order if set.
+// Keys and values are only valid until fn returns.
+func IterateRange(
+	ctx context.Context,
+	db libkv.DB,
+	bucketName libkv.BucketName,
+	r ScanRange,
+	fn func(key []byte, value []byte) error,
+) error {
+	return db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		bucket, err := tx.Bucket(ctx, bucketName)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "get bucket failed")
+		}
+		keyRange := r.KeyRange()
+		it := r.Iterator(bucket)
+		defer it.Close()
+		for count := 0; it.Valid(); it.Next() {
+			item := it.Item()
+			if !keyRange.Contains(item.Key()) {
+				return nil
+			}
+			if r.Limit > 0 && count == r.Limit {
+				return nil
+			}
+			count++
+			err := item.Value(func(value []byte) error {
+				return fn(item.Key(), value)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListValues calls fn for every key of the bucket in key order.
+func ListValues(
+	ctx context.Context,
+	db libkv.DB,
+	bucketName libkv.BucketName,
+	fn func(key []byte, value []byte) error,
+) error {
+	return ScanValues(ctx, db, bucketName, nil, fn)
+}
+
+// ScanValues calls fn for every key of the bucket starting with prefix in key order.
+func ScanValues(
+	ctx context.Context,
+	db libkv.DB,
+	bucketName libkv.BucketName,
+	prefix []byte,
+	fn func(key []byte, value []byte) error,
+) error {
+	return IterateRange(ctx, db, bucketName, ScanRange{Prefix: prefix}, fn)
+}
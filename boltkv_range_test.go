@@ -0,0 +1,80 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv_test
+
+import (
+	"context"
+
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+)
+
+var _ = Describe("ScanRange", func() {
+	var ctx context.Context
+	var db boltkv.DB
+	bucketName := libkv.NewBucketName("a")
+	keys := func(r boltkv.ScanRange) []string {
+		var result []string
+		err := boltkv.IterateRange(ctx, db, bucketName, r, func(key []byte, value []byte) error {
+			result = append(result, string(key))
+			return nil
+		})
+		Expect(err).To(BeNil())
+		return result
+	}
+	BeforeEach(func() {
+		ctx = context.Background()
+		var err error
+		db, err = boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		for _, key := range []string{"a1", "a2", "a3", "b1", "b2", "c1", "\xff\xff"} {
+			Expect(boltkv.SetValue(ctx, db, bucketName, []byte(key), []byte("v"))).To(Succeed())
+		}
+	})
+	AfterEach(func() {
+		_ = db.Close()
+		_ = db.Remove()
+	})
+	DescribeTable("IterateRange",
+		func(r boltkv.ScanRange, expected []string) {
+			Expect(keys(r)).To(Equal(expected))
+		},
+		Entry("all", boltkv.ScanRange{}, []string{"a1", "a2", "a3", "b1", "b2", "c1", "\xff\xff"}),
+		Entry("prefix", boltkv.ScanRange{Prefix: []byte("b")}, []string{"b1", "b2"}),
+		Entry("start", boltkv.ScanRange{Start: []byte("b2")}, []string{"b2", "c1", "\xff\xff"}),
+		Entry("end", boltkv.ScanRange{End: []byte("a3")}, []string{"a1", "a2"}),
+		Entry("start and end", boltkv.ScanRange{Start: []byte("a2"), End: []byte("b2")},
+			[]string{"a2", "a3", "b1"}),
+		Entry("prefix and start", boltkv.ScanRange{Prefix: []byte("a"), Start: []byte("a2")},
+			[]string{"a2", "a3"}),
+		Entry("limit", boltkv.ScanRange{Limit: 2}, []string{"a1", "a2"}),
+		Entry("prefix 0xff", boltkv.ScanRange{Prefix: []byte("\xff")}, []string{"\xff\xff"}),
+		Entry("reverse", boltkv.ScanRange{Reverse: true, Limit: 3}, []string{"\xff\xff", "c1", "b2"}),
+		Entry("reverse prefix", boltkv.ScanRange{Prefix: []byte("a"), Reverse: true},
+			[]string{"a3", "a2", "a1"}),
+		Entry("reverse end", boltkv.ScanRange{End: []byte("b2"), Reverse: true, Limit: 2},
+			[]string{"b1", "a3"}),
+		Entry("reverse end missing", boltkv.ScanRange{End: []byte("b3"), Reverse: true, Limit: 2},
+			[]string{"b2", "b1"}),
+		Entry(
+			"reverse start and end",
+			boltkv.ScanRange{Start: []byte("a3"), End: []byte("c"), Reverse: true},
+			[]string{"b2", "b1", "a3"},
+		),
+		Entry("reverse before first", boltkv.ScanRange{End: []byte("a"), Reverse: true}, nil),
+		Entry("empty", boltkv.ScanRange{Prefix: []byte("d")}, nil),
+	)
+	It("fails for missing buckets", func() {
+		err := boltkv.IterateRange(ctx, db, libkv.NewBucketName("missing"), boltkv.ScanRange{}, nil)
+		Expect(err).NotTo(BeNil())
+	})
+	It("returns the bounds as KeyRange", func() {
+		keyRange := boltkv.ScanRange{Prefix: []byte("a"), Start: []byte("a2")}.KeyRange()
+		Expect(keyRange).To(Equal(boltkv.KeyRange{Start: []byte("a2"), End: []byte("b")}))
+	})
+})
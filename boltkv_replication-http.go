@@ -0,0 +1,147 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bborbe/errors"
+	"github.com/golang/glog"
+)
+
+// ReplicationSequenceHeader is the HTTP trailer carrying the sequence of a snapshot.
+const ReplicationSequenceHeader = "X-Boltkv-Sequence"
+
+// NewReplicationHandler serves transport over HTTP for NewHTTPReplicationTransport:
+// GET entries?after=N&limit=M returns ReplicationEntries as JSON, answering
+// 410 Gone for truncated entries, GET snapshot returns the database file.
+func NewReplicationHandler(transport ReplicationTransport) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /entries", func(resp http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		after, err := parseUintParam(ctx, req, "after")
+		if err != nil {
+			http.Error(resp, err.Error(), http.StatusBadRequest)
+			return
+		}
+		limit, err := parseUintParam(ctx, req, "limit")
+		if err != nil {
+			http.Error(resp, err.Error(), http.StatusBadRequest)
+			return
+		}
+		entries, err := transport.Entries(ctx, after, int(limit))
+		if errors.Is(err, ReplicationLogTruncatedError) {
+			http.Error(resp, err.Error(), http.StatusGone)
+			return
+		}
+		if err != nil {
+			glog.Warningf("read replication entries failed: %v", err)
+			http.Error(resp, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		resp.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(resp).Encode(entries); err != nil {
+			glog.Warningf("write replication entries failed: %v", err)
+		}
+	})
+	mux.HandleFunc("GET /snapshot", func(resp http.ResponseWriter, req *http.Request) {
+		// the sequence is known after streaming the snapshot, so it is sent as trailer
+		resp.Header().Set("Trailer", ReplicationSequenceHeader)
+		resp.Header().Set("Content-Type", "application/octet-stream")
+		sequence, err := transport.Snapshot(req.Context(), resp)
+		if err != nil {
+			// the status is sent already, the missing trailer fails the client
+			glog.Warningf("snapshot failed: %v", err)
+			return
+		}
+		resp.Header().Set(ReplicationSequenceHeader, strconv.FormatUint(sequence, 10))
+	})
+	return mux
+}
+
+func parseUintParam(ctx context.Context, req *http.Request, name string) (uint64, error) {
+	value := req.URL.Query().Get(name)
+	if value == "" {
+		return 0, nil
+	}
+	result, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(ctx, err, "parse %s failed", name)
+	}
+	return result, nil
+}
+
+// NewHTTPReplicationTransport returns a ReplicationTransport talking to a
+// NewReplicationHandler served at url.
+func NewHTTPReplicationTransport(client *http.Client, url string) ReplicationTransport {
+	return &httpReplicationTransport{
+		client: client,
+		url:    strings.TrimSuffix(url, "/"),
+	}
+}
+
+type httpReplicationTransport struct {
+	client *http.Client
+	url    string
+}
+
+func (h *httpReplicationTransport) Entries(
+	ctx context.Context,
+	after uint64,
+	limit int,
+) (ReplicationEntries, error) {
+	resp, err := h.get(ctx, fmt.Sprintf("%s/entries?after=%d&limit=%d", h.url, after, limit))
+	if err != nil {
+		return ReplicationEntries{}, err
+	}
+	defer resp.Body.Close()
+	var result ReplicationEntries
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return ReplicationEntries{}, errors.Wrapf(ctx, err, "decode entries failed")
+	}
+	return result, nil
+}
+
+func (h *httpReplicationTransport) Snapshot(ctx context.Context, w io.Writer) (uint64, error) {
+	resp, err := h.get(ctx, h.url+"/snapshot")
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return 0, errors.Wrapf(ctx, err, "read snapshot failed")
+	}
+	sequence, err := strconv.ParseUint(resp.Trailer.Get(ReplicationSequenceHeader), 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(ctx, err, "parse %s failed", ReplicationSequenceHeader)
+	}
+	return sequence, nil
+}
+
+func (h *httpReplicationTransport) get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "create request failed")
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "get %s failed", url)
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode == http.StatusGone {
+		return nil, errors.Wrapf(ctx, ReplicationLogTruncatedError, "get %s: %s", url, body)
+	}
+	return nil, errors.Errorf(ctx, "get %s failed with status %d: %s", url, resp.StatusCode, body)
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv_test
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+)
+
+var _ = Describe("HTTPReplicationTransport", func() {
+	var ctx context.Context
+	var primary boltkv.DB
+	var server *httptest.Server
+	var transport boltkv.ReplicationTransport
+	BeforeEach(func() {
+		ctx = context.Background()
+		db, err := boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		primary = boltkv.NewDB(db.DB(), boltkv.WithReplicationLog())
+		for i := 0; i < 3; i++ {
+			Expect(primary.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+				bucket, err := tx.CreateBucketIfNotExists(ctx, libkv.NewBucketName("a"))
+				Expect(err).To(BeNil())
+				return bucket.Put(ctx, []byte{byte(i)}, []byte{0xff, byte(i)})
+			})).To(Succeed())
+		}
+		server = httptest.NewServer(
+			boltkv.NewReplicationHandler(boltkv.NewMemoryReplicationTransport(primary)),
+		)
+		transport = boltkv.NewHTTPReplicationTransport(server.Client(), server.URL)
+	})
+	AfterEach(func() {
+		server.Close()
+		_ = primary.Close()
+		_ = primary.Remove()
+	})
+	It("returns entries", func() {
+		entries, err := transport.Entries(ctx, 1, 1)
+		Expect(err).To(BeNil())
+		Expect(entries.Sequence).To(Equal(uint64(3)))
+		Expect(entries.Entries).To(HaveLen(1))
+		Expect(entries.Entries[0].Sequence).To(Equal(uint64(2)))
+		Expect(entries.Entries[0].Mutations[0].Value).To(Equal([]byte{0xff, 1}))
+	})
+	It("maps truncated entries to ReplicationLogTruncatedError", func() {
+		Expect(boltkv.TruncateReplicationLog(ctx, primary, 3)).To(Equal(int64(2)))
+		_, err := transport.Entries(ctx, 0, 0)
+		Expect(errors.Is(err, boltkv.ReplicationLogTruncatedError)).To(BeTrue())
+	})
+	It("streams a snapshot with its sequence", func() {
+		buf := &bytes.Buffer{}
+		sequence, err := transport.Snapshot(ctx, buf)
+		Expect(err).To(BeNil())
+		Expect(sequence).To(Equal(uint64(3)))
+		Expect(buf.Len()).To(BeNumerically(">", 0))
+	})
+	It("replicates over loopback", func() {
+		replicaDB, err := boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		defer func() {
+			_ = replicaDB.Close()
+			_ = replicaDB.Remove()
+		}()
+		replica := boltkv.NewReplica(replicaDB, transport, boltkv.ReplicaOptions{})
+		applied, err := replica.Sync(ctx)
+		Expect(err).To(BeNil())
+		Expect(applied).To(Equal(3))
+		primaryFingerprint, err := boltkv.BucketFingerprint(ctx, primary, libkv.NewBucketName("a"))
+		Expect(err).To(BeNil())
+		Expect(boltkv.BucketFingerprint(ctx, replicaDB, libkv.NewBucketName("a"))).
+			To(Equal(primaryFingerprint))
+	})
+})
@@ -0,0 +1,527 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	"github.com/golang/glog"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// DefaultReplicaBatchSize is the number of log entries a replica fetches and applies at once.
+	DefaultReplicaBatchSize = 100
+	// DefaultReplicaPollInterval is the pause of Replica.Run after catching up.
+	DefaultReplicaPollInterval = time.Second
+)
+
+var (
+	// replicationLogBucket holds the log entries of a primary keyed by big-endian sequence.
+	replicationLogBucket = libkv.NewBucketName(InternalBucketPrefix + "replication_log")
+	// replicaStateBucket holds the applied sequence of a replica.
+	replicaStateBucket = libkv.NewBucketName(InternalBucketPrefix + "replica")
+
+	// ReplicationLogTruncatedError is returned if the entries following the
+	// requested sequence were truncated, the replica needs a new snapshot.
+	ReplicationLogTruncatedError = errors.New(
+		context.Background(),
+		"replication log truncated",
+	)
+	// ReplicationGapError is returned if a replica receives a non-consecutive entry.
+	ReplicationGapError = errors.New(context.Background(), "replication gap")
+)
+
+var (
+	replicaSequenceKey = []byte("sequence")
+	replicaTimeKey     = []byte("time")
+)
+
+// ReplicationMutationType is the kind of a ReplicationMutation.
+type ReplicationMutationType string
+
+const (
+	ReplicationMutationPut          ReplicationMutationType = "put"
+	ReplicationMutationDelete       ReplicationMutationType = "delete"
+	ReplicationMutationCreateBucket ReplicationMutationType = "create_bucket"
+	ReplicationMutationDeleteBucket ReplicationMutationType = "delete_bucket"
+)
+
+// ReplicationMutation is a single write of an Update.
+type ReplicationMutation struct {
+	Type   ReplicationMutationType `json:"type"`
+	Bucket []byte                  `json:"bucket"`
+	Key    []byte                  `json:"key,omitempty"`
+	Value  []byte                  `json:"value,omitempty"`
+}
+
+// ReplicationEntry holds all mutations of one committed Update.
+type ReplicationEntry struct {
+	Sequence  uint64                `json:"sequence"`
+	Time      time.Time             `json:"time"`
+	Mutations []ReplicationMutation `json:"mutations"`
+}
+
+// ReplicationEntries is a page of the replication log.
+type ReplicationEntries struct {
+	Entries []ReplicationEntry `json:"entries"`
+	// Sequence is the last sequence of the primary.
+	Sequence uint64 `json:"sequence"`
+}
+
+// WithReplicationLog records the mutations of every Update through the libkv
+// interfaces in the internal bucket _boltkv_replication_log as one
+// ReplicationEntry, committed in the same transaction. Only top-level buckets
+// are supported, writes through the raw bolt.Tx or bolt.Bucket are not recorded.
+func WithReplicationLog() ChangeDBOptions {
+	return func(opts *DBOptions) {
+		opts.ReplicationLog = true
+	}
+}
+
+// replicationRecorder collects the mutations of one Update, a nil recorder ignores them.
+type replicationRecorder struct {
+	mutations []ReplicationMutation
+}
+
+func (r *replicationRecorder) record(
+	mutationType ReplicationMutationType,
+	bucketName libkv.BucketName,
+	key []byte,
+	value []byte,
+) {
+	if r == nil {
+		return
+	}
+	r.mutations = append(r.mutations, ReplicationMutation{
+		Type:   mutationType,
+		Bucket: bytes.Clone(bucketName),
+		Key:    bytes.Clone(key),
+		Value:  bytes.Clone(value),
+	})
+}
+
+// append writes the recorded mutations as next entry of the log, nothing if there are none.
+func (r *replicationRecorder) append(tx *bolt.Tx) error {
+	if r == nil || len(r.mutations) == 0 {
+		return nil
+	}
+	bucket, err := tx.CreateBucketIfNotExists(replicationLogBucket)
+	if err != nil {
+		return err
+	}
+	sequence, err := bucket.NextSequence()
+	if err != nil {
+		return err
+	}
+	value, err := json.Marshal(ReplicationEntry{
+		Sequence:  sequence,
+		Time:      time.Now(),
+		Mutations: r.mutations,
+	})
+	if err != nil {
+		return err
+	}
+	return bucket.Put(binary.BigEndian.AppendUint64(nil, sequence), value)
+}
+
+// ReadReplicationLog returns up to limit entries following the sequence after.
+func ReadReplicationLog(
+	ctx context.Context,
+	db DB,
+	after uint64,
+	limit int,
+) (ReplicationEntries, error) {
+	var result ReplicationEntries
+	err := db.DB().View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(replicationLogBucket)
+		if bucket == nil {
+			return nil
+		}
+		result.Sequence = bucket.Sequence()
+		cursor := bucket.Cursor()
+		key, value := cursor.Seek(binary.BigEndian.AppendUint64(nil, after+1))
+		if key != nil && binary.BigEndian.Uint64(key) != after+1 {
+			return errors.Wrapf(
+				ctx,
+				ReplicationLogTruncatedError,
+				"entry %d not found, log starts at %d",
+				after+1,
+				binary.BigEndian.Uint64(key),
+			)
+		}
+		if key == nil && after < result.Sequence {
+			return errors.Wrapf(ctx, ReplicationLogTruncatedError, "entry %d not found", after+1)
+		}
+		for ; key != nil && (limit <= 0 || len(result.Entries) < limit); key, value = cursor.Next() {
+			var entry ReplicationEntry
+			if err := json.Unmarshal(value, &entry); err != nil {
+				return errors.Wrapf(ctx, err, "unmarshal entry failed")
+			}
+			result.Entries = append(result.Entries, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return ReplicationEntries{}, errors.Wrapf(ctx, err, "read replication log failed")
+	}
+	return result, nil
+}
+
+// TruncateReplicationLog removes all entries with a sequence lower than before.
+// Replicas behind it need a new snapshot.
+func TruncateReplicationLog(ctx context.Context, db DB, before uint64) (int64, error) {
+	var deleted int64
+	err := db.DB().Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(replicationLogBucket)
+		if bucket == nil {
+			return nil
+		}
+		cursor := bucket.Cursor()
+		for key, _ := cursor.First(); key != nil; key, _ = cursor.First() {
+			if binary.BigEndian.Uint64(key) >= before {
+				return nil
+			}
+			if err := cursor.Delete(); err != nil {
+				return err
+			}
+			deleted++
+		}
+		return nil
+	})
+	if err != nil {
+		return deleted, errors.Wrapf(ctx, err, "truncate replication log failed")
+	}
+	return deleted, nil
+}
+
+// ReplicationTransport connects a replica with its primary.
+//
+//counterfeiter:generate -o mocks/boltkv-replication-transport.go --fake-name BoltkvReplicationTransport . ReplicationTransport
+type ReplicationTransport interface {
+	// Entries returns up to limit log entries following the sequence after.
+	Entries(ctx context.Context, after uint64, limit int) (ReplicationEntries, error)
+	// Snapshot writes a backup of the primary to w and returns the sequence it contains.
+	Snapshot(ctx context.Context, w io.Writer) (uint64, error)
+}
+
+// NewMemoryReplicationTransport returns a ReplicationTransport reading the
+// primary directly, for replicas in the same process.
+func NewMemoryReplicationTransport(primary DB) ReplicationTransport {
+	return &memoryReplicationTransport{primary: primary}
+}
+
+type memoryReplicationTransport struct {
+	primary DB
+}
+
+func (m *memoryReplicationTransport) Entries(
+	ctx context.Context,
+	after uint64,
+	limit int,
+) (ReplicationEntries, error) {
+	return ReadReplicationLog(ctx, m.primary, after, limit)
+}
+
+func (m *memoryReplicationTransport) Snapshot(ctx context.Context, w io.Writer) (uint64, error) {
+	return Snapshot(ctx, m.primary, w)
+}
+
+// Snapshot writes a consistent backup like Backup and returns the last
+// sequence of the replication log contained in it.
+func Snapshot(ctx context.Context, db DB, w io.Writer) (uint64, error) {
+	var sequence uint64
+	err := db.DB().View(func(tx *bolt.Tx) error {
+		if bucket := tx.Bucket(replicationLogBucket); bucket != nil {
+			sequence = bucket.Sequence()
+		}
+		_, err := tx.WriteTo(w)
+		return err
+	})
+	if err != nil {
+		return 0, errors.Wrapf(ctx, err, "snapshot failed")
+	}
+	return sequence, nil
+}
+
+// RestoreSnapshot bootstraps a replica database at path from a snapshot of
+// the primary. The file is written next to path and renamed on success; the
+// replication log of the primary is removed from it and the snapshot sequence
+// stored as applied. path must not be open.
+func RestoreSnapshot(
+	ctx context.Context,
+	transport ReplicationTransport,
+	path string,
+) (uint64, error) {
+	tmpPath := path + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, errors.Wrapf(ctx, err, "create %s failed", tmpPath)
+	}
+	sequence, err := transport.Snapshot(ctx, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = initReplica(tmpPath, sequence)
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return 0, errors.Wrapf(ctx, err, "restore snapshot failed")
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return 0, errors.Wrapf(ctx, err, "rename %s to %s failed", tmpPath, path)
+	}
+	return sequence, nil
+}
+
+func initReplica(path string, sequence uint64) error {
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket(replicationLogBucket) != nil {
+			if err := tx.DeleteBucket(replicationLogBucket); err != nil {
+				return err
+			}
+		}
+		return storeReplicaState(tx, sequence, time.Time{})
+	})
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func storeReplicaState(tx *bolt.Tx, sequence uint64, applied time.Time) error {
+	bucket, err := tx.CreateBucketIfNotExists(replicaStateBucket)
+	if err != nil {
+		return err
+	}
+	err = bucket.Put(replicaSequenceKey, binary.BigEndian.AppendUint64(nil, sequence))
+	if err != nil {
+		return err
+	}
+	value, err := applied.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return bucket.Put(replicaTimeKey, value)
+}
+
+func loadReplicaState(tx *bolt.Tx) (uint64, time.Time, error) {
+	bucket := tx.Bucket(replicaStateBucket)
+	if bucket == nil {
+		return 0, time.Time{}, nil
+	}
+	var sequence uint64
+	if value := bucket.Get(replicaSequenceKey); len(value) == 8 {
+		sequence = binary.BigEndian.Uint64(value)
+	}
+	var applied time.Time
+	if value := bucket.Get(replicaTimeKey); value != nil {
+		if err := applied.UnmarshalBinary(value); err != nil {
+			return 0, time.Time{}, err
+		}
+	}
+	return sequence, applied, nil
+}
+
+// ReplicationLag describes how far a replica is behind its primary.
+type ReplicationLag struct {
+	// Applied is the last sequence applied by the replica.
+	Applied uint64
+	// Primary is the last sequence of the primary seen by the replica.
+	Primary uint64
+	// AppliedTime is the commit time on the primary of the last applied entry.
+	AppliedTime time.Time
+	// Synced is the time of the last successful Sync.
+	Synced time.Time
+}
+
+// Entries returns the number of entries not applied yet.
+func (l ReplicationLag) Entries() uint64 {
+	if l.Primary < l.Applied {
+		return 0
+	}
+	return l.Primary - l.Applied
+}
+
+// ReplicaOptions configures NewReplica.
+type ReplicaOptions struct {
+	// BatchSize is the number of entries per fetch and Update, defaults to DefaultReplicaBatchSize.
+	BatchSize int
+	// PollInterval is the pause of Run after catching up, defaults to DefaultReplicaPollInterval.
+	PollInterval time.Duration
+}
+
+// Replica applies the replication log of a primary in order.
+//
+//counterfeiter:generate -o mocks/boltkv-replica.go --fake-name BoltkvReplica . Replica
+type Replica interface {
+	// Sync applies all available entries and returns how many were applied.
+	Sync(ctx context.Context) (int, error)
+	// Run calls Sync until ctx is canceled. Failed syncs are logged and
+	// retried, except ReplicationLogTruncatedError and ReplicationGapError.
+	Run(ctx context.Context) error
+	// Lag returns the state observed by the last Sync.
+	Lag() ReplicationLag
+}
+
+// NewReplica returns a Replica applying the entries from transport to db.
+// The applied sequence is stored in the internal bucket _boltkv_replica in the
+// same transaction as the entries, see RestoreSnapshot to bootstrap db.
+func NewReplica(db DB, transport ReplicationTransport, opts ReplicaOptions) Replica {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultReplicaBatchSize
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultReplicaPollInterval
+	}
+	return &replica{
+		db:        db,
+		transport: transport,
+		opts:      opts,
+	}
+}
+
+type replica struct {
+	db        DB
+	transport ReplicationTransport
+	opts      ReplicaOptions
+
+	mux sync.Mutex
+	lag ReplicationLag
+}
+
+func (r *replica) Sync(ctx context.Context) (int, error) {
+	var applied int
+	for {
+		var state ReplicationLag
+		err := r.db.DB().View(func(tx *bolt.Tx) error {
+			var err error
+			state.Applied, state.AppliedTime, err = loadReplicaState(tx)
+			return err
+		})
+		if err != nil {
+			return applied, errors.Wrapf(ctx, err, "load replica state failed")
+		}
+		entries, err := r.transport.Entries(ctx, state.Applied, r.opts.BatchSize)
+		if err != nil {
+			return applied, errors.Wrapf(ctx, err, "fetch entries after %d failed", state.Applied)
+		}
+		if len(entries.Entries) > 0 {
+			if err := r.apply(ctx, state.Applied, entries.Entries); err != nil {
+				return applied, errors.Wrapf(ctx, err, "apply entries failed")
+			}
+			last := entries.Entries[len(entries.Entries)-1]
+			state.Applied, state.AppliedTime = last.Sequence, last.Time
+			applied += len(entries.Entries)
+		}
+		state.Primary = entries.Sequence
+		state.Synced = time.Now()
+		r.mux.Lock()
+		r.lag = state
+		r.mux.Unlock()
+		if len(entries.Entries) < r.opts.BatchSize {
+			glog.V(3).Infof("replica applied %d entries, lag %d", applied, state.Entries())
+			return applied, nil
+		}
+	}
+}
+
+func (r *replica) apply(ctx context.Context, after uint64, entries []ReplicationEntry) error {
+	return r.db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		for _, entry := range entries {
+			if entry.Sequence != after+1 {
+				return errors.Wrapf(
+					ctx,
+					ReplicationGapError,
+					"expected entry %d but got %d",
+					after+1,
+					entry.Sequence,
+				)
+			}
+			for _, mutation := range entry.Mutations {
+				if err := applyMutation(ctx, tx, mutation); err != nil {
+					return errors.Wrapf(ctx, err, "apply entry %d failed", entry.Sequence)
+				}
+			}
+			after = entry.Sequence
+		}
+		boltkvTx, ok := tx.(Tx)
+		if !ok || boltkvTx.Tx() == nil {
+			return errors.Errorf(ctx, "tx is not a bolt transaction")
+		}
+		last := entries[len(entries)-1]
+		return storeReplicaState(boltkvTx.Tx(), last.Sequence, last.Time)
+	})
+}
+
+func applyMutation(ctx context.Context, tx libkv.Tx, mutation ReplicationMutation) error {
+	bucketName := libkv.BucketName(mutation.Bucket)
+	switch mutation.Type {
+	case ReplicationMutationCreateBucket:
+		_, err := tx.CreateBucketIfNotExists(ctx, bucketName)
+		return err
+	case ReplicationMutationDeleteBucket:
+		err := tx.DeleteBucket(ctx, bucketName)
+		if errors.Is(err, libkv.BucketNotFoundError) {
+			return nil
+		}
+		return err
+	case ReplicationMutationPut:
+		bucket, err := tx.CreateBucketIfNotExists(ctx, bucketName)
+		if err != nil {
+			return err
+		}
+		return bucket.Put(ctx, mutation.Key, mutation.Value)
+	case ReplicationMutationDelete:
+		bucket, err := tx.Bucket(ctx, bucketName)
+		if errors.Is(err, libkv.BucketNotFoundError) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return bucket.Delete(ctx, mutation.Key)
+	default:
+		return errors.Errorf(ctx, "unknown mutation type '%s'", mutation.Type)
+	}
+}
+
+func (r *replica) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := r.Sync(ctx); err != nil {
+			if errors.Is(err, ReplicationLogTruncatedError) || errors.Is(err, ReplicationGapError) {
+				return errors.Wrapf(ctx, err, "sync failed")
+			}
+			glog.Warningf("replica sync failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *replica) Lag() ReplicationLag {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return r.lag
+}
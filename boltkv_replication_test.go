@@ -0,0 +1,211 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+)
+
+var _ = Describe("Replication", func() {
+	var ctx context.Context
+	var primary boltkv.DB
+	var replicaDB boltkv.DB
+	var replica boltkv.Replica
+	var bucketName libkv.BucketName
+	put := func(pairs ...string) {
+		Expect(primary.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.CreateBucketIfNotExists(ctx, bucketName)
+			Expect(err).To(BeNil())
+			for i := 0; i < len(pairs); i += 2 {
+				Expect(bucket.Put(ctx, []byte(pairs[i]), []byte(pairs[i+1]))).To(Succeed())
+			}
+			return nil
+		})).To(Succeed())
+	}
+	get := func(db libkv.DB, key string) string {
+		var result string
+		Expect(db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			item, err := bucket.Get(ctx, []byte(key))
+			Expect(err).To(BeNil())
+			return item.Value(func(value []byte) error {
+				result = string(value)
+				return nil
+			})
+		})).To(Succeed())
+		return result
+	}
+	BeforeEach(func() {
+		ctx = context.Background()
+		bucketName = libkv.NewBucketName("a")
+		db, err := boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		primary = boltkv.NewDB(db.DB(), boltkv.WithReplicationLog())
+		replicaDB, err = boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		replica = boltkv.NewReplica(
+			replicaDB,
+			boltkv.NewMemoryReplicationTransport(primary),
+			boltkv.ReplicaOptions{BatchSize: 2},
+		)
+	})
+	AfterEach(func() {
+		for _, db := range []boltkv.DB{primary, replicaDB} {
+			_ = db.Close()
+			_ = db.Remove()
+		}
+	})
+	It("records one entry per Update with mutations", func() {
+		put("k1", "v1", "k2", "v2")
+		put()
+		Expect(primary.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			Expect(err).To(BeNil())
+			return bucket.Delete(ctx, []byte("k1"))
+		})).To(Succeed())
+		entries, err := boltkv.ReadReplicationLog(ctx, primary, 0, 0)
+		Expect(err).To(BeNil())
+		Expect(entries.Sequence).To(Equal(uint64(2)))
+		Expect(entries.Entries).To(HaveLen(2))
+		Expect(entries.Entries[0].Mutations).To(HaveLen(3))
+		Expect(entries.Entries[0].Mutations[0].Type).
+			To(Equal(boltkv.ReplicationMutationCreateBucket))
+		Expect(entries.Entries[1].Mutations).To(Equal([]boltkv.ReplicationMutation{{
+			Type:   boltkv.ReplicationMutationDelete,
+			Bucket: []byte("a"),
+			Key:    []byte("k1"),
+		}}))
+	})
+	It("does not record failed Updates", func() {
+		Expect(primary.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			_, err := tx.CreateBucket(ctx, bucketName)
+			Expect(err).To(BeNil())
+			return errors.New(ctx, "banana")
+		})).NotTo(Succeed())
+		entries, err := boltkv.ReadReplicationLog(ctx, primary, 0, 0)
+		Expect(err).To(BeNil())
+		Expect(entries.Entries).To(BeEmpty())
+	})
+	It("applies entries in order and reports lag", func() {
+		put("k1", "v1")
+		put("k2", "v2")
+		put("k1", "changed")
+		Expect(primary.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			return tx.DeleteBucket(ctx, libkv.NewBucketName("missing"))
+		})).NotTo(Succeed())
+
+		applied, err := replica.Sync(ctx)
+		Expect(err).To(BeNil())
+		Expect(applied).To(Equal(3))
+		Expect(get(replicaDB, "k1")).To(Equal("changed"))
+		Expect(get(replicaDB, "k2")).To(Equal("v2"))
+		lag := replica.Lag()
+		Expect(lag.Applied).To(Equal(uint64(3)))
+		Expect(lag.Primary).To(Equal(uint64(3)))
+		Expect(lag.Entries()).To(BeZero())
+		Expect(lag.AppliedTime).NotTo(BeZero())
+
+		put("k3", "v3")
+		applied, err = replica.Sync(ctx)
+		Expect(err).To(BeNil())
+		Expect(applied).To(Equal(1))
+		Expect(get(replicaDB, "k3")).To(Equal("v3"))
+	})
+	It("replicates bucket deletion", func() {
+		put("k1", "v1")
+		Expect(primary.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			return tx.DeleteBucket(ctx, bucketName)
+		})).To(Succeed())
+		_, err := replica.Sync(ctx)
+		Expect(err).To(BeNil())
+		Expect(replicaDB.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			_, err := tx.Bucket(ctx, bucketName)
+			return err
+		})).To(MatchError(ContainSubstring("not found")))
+	})
+	It("returns ReplicationLogTruncatedError behind a truncated log", func() {
+		put("k1", "v1")
+		put("k2", "v2")
+		deleted, err := boltkv.TruncateReplicationLog(ctx, primary, 2)
+		Expect(err).To(BeNil())
+		Expect(deleted).To(Equal(int64(1)))
+		_, err = replica.Sync(ctx)
+		Expect(errors.Is(err, boltkv.ReplicationLogTruncatedError)).To(BeTrue())
+		Expect(replica.Run(ctx)).NotTo(Succeed())
+	})
+	It("bootstraps a replica from a snapshot", func() {
+		put("k1", "v1")
+		Expect(boltkv.TruncateReplicationLog(ctx, primary, 2)).To(Equal(int64(1)))
+		dir, err := os.MkdirTemp("", "")
+		Expect(err).To(BeNil())
+		defer func() {
+			_ = os.RemoveAll(dir)
+		}()
+		path := filepath.Join(dir, "replica.db")
+		transport := boltkv.NewMemoryReplicationTransport(primary)
+		sequence, err := boltkv.RestoreSnapshot(ctx, transport, path)
+		Expect(err).To(BeNil())
+		Expect(sequence).To(Equal(uint64(1)))
+
+		restored, err := boltkv.OpenFile(ctx, path)
+		Expect(err).To(BeNil())
+		defer func() {
+			_ = restored.Close()
+		}()
+		put("k2", "v2")
+		replica := boltkv.NewReplica(restored, transport, boltkv.ReplicaOptions{})
+		applied, err := replica.Sync(ctx)
+		Expect(err).To(BeNil())
+		Expect(applied).To(Equal(1))
+		Expect(get(restored, "k1")).To(Equal("v1"))
+		Expect(get(restored, "k2")).To(Equal("v2"))
+	})
+	It("fails instead of panicking if the DB hides the bolt transaction", func() {
+		put("k1", "v1")
+		replica := boltkv.NewReplica(
+			plainTxDB{boltkvDB: replicaDB},
+			boltkv.NewMemoryReplicationTransport(primary),
+			boltkv.ReplicaOptions{},
+		)
+		_, err := replica.Sync(ctx)
+		Expect(err).To(MatchError(ContainSubstring("tx is not a bolt transaction")))
+	})
+	It("stops Run on context cancel", func() {
+		put("k1", "v1")
+		cancelCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		Expect(replica.Run(cancelCtx)).To(Succeed())
+		Expect(get(replicaDB, "k1")).To(Equal("v1"))
+	})
+})
+
+// boltkvDB is embedded under another name, a field named DB hides the DB method.
+type boltkvDB = boltkv.DB
+
+// plainTxDB passes transactions without the boltkv.Tx methods to Update.
+type plainTxDB struct {
+	boltkvDB
+}
+
+func (d plainTxDB) Update(
+	ctx context.Context,
+	fn func(ctx context.Context, tx libkv.Tx) error,
+) error {
+	return d.boltkvDB.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		return fn(ctx, struct{ libkv.Tx }{tx})
+	})
+}
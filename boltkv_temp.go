@@ -0,0 +1,99 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv
+
+import (
+	"context"
+	"os"
+
+	"github.com/bborbe/errors"
+)
+
+// DefaultTempPattern is the file name pattern of temporary databases.
+const DefaultTempPattern = "boltkv-*.db"
+
+// memoryTempDirs are checked in order by MemoryTempDir.
+var memoryTempDirs = []string{"/dev/shm", "/run/shm"}
+
+// TempOptions configures OpenTempWithOptions.
+type TempOptions struct {
+	// Dir is the directory of the file, defaults to os.TempDir or MemoryTempDir if Memory is set.
+	Dir string
+	// Pattern is the file name pattern of os.CreateTemp, defaults to DefaultTempPattern.
+	Pattern string
+	// RemoveOnClose deletes the file on Close, see WithRemoveOnClose.
+	RemoveOnClose bool
+	// Memory places the file in MemoryTempDir if Dir is empty.
+	Memory bool
+	// DBOptions configure the boltkv layer, e.g. WithHook or WithFingerprint.
+	DBOptions []ChangeDBOptions
+}
+
+// OpenTemp opens a new database in os.TempDir. The caller removes it with Remove.
+func OpenTemp(ctx context.Context, fn ...ChangeOptions) (DB, error) {
+	return OpenTempWithOptions(ctx, TempOptions{}, fn...)
+}
+
+// OpenTempWithOptions opens a new database in a file created with os.CreateTemp.
+func OpenTempWithOptions(ctx context.Context, opts TempOptions, fn ...ChangeOptions) (DB, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Wrapf(ctx, err, "open temp failed")
+	}
+	dir := opts.Dir
+	if dir == "" && opts.Memory {
+		dir = MemoryTempDir()
+	}
+	pattern := opts.Pattern
+	if pattern == "" {
+		pattern = DefaultTempPattern
+	}
+	file, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "create temp file in '%s' failed", dir)
+	}
+	path := file.Name()
+	if err := file.Close(); err != nil {
+		_ = os.Remove(path)
+		return nil, errors.Wrapf(ctx, err, "close temp file %s failed", path)
+	}
+	dbOptions := append([]ChangeDBOptions{}, opts.DBOptions...)
+	if opts.RemoveOnClose {
+		dbOptions = append(dbOptions, WithRemoveOnClose())
+	}
+	db, err := OpenFileWithOptions(ctx, path, OpenOptions{
+		BoltOptions: fn,
+		DBOptions:   dbOptions,
+	})
+	if err != nil {
+		_ = os.Remove(path)
+		return nil, err
+	}
+	return db, nil
+}
+
+// MemoryTempDir returns a memory-backed directory like /dev/shm if one is
+// writable, os.TempDir otherwise.
+func MemoryTempDir() string {
+	for _, dir := range memoryTempDirs {
+		if writableDir(dir) {
+			return dir
+		}
+	}
+	return os.TempDir()
+}
+
+func writableDir(dir string) bool {
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return false
+	}
+	file, err := os.CreateTemp(dir, ".boltkv-probe-*")
+	if err != nil {
+		return false
+	}
+	_ = file.Close()
+	_ = os.Remove(file.Name())
+	return true
+}
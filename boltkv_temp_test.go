@@ -0,0 +1,128 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+)
+
+var _ = Describe("Temp", func() {
+	var ctx context.Context
+	var dir string
+	BeforeEach(func() {
+		ctx = context.Background()
+		dir = GinkgoT().TempDir()
+	})
+	files := func(dir string) []string {
+		entries, err := os.ReadDir(dir)
+		Expect(err).To(BeNil())
+		var result []string
+		for _, entry := range entries {
+			result = append(result, entry.Name())
+		}
+		return result
+	}
+	put := func(db libkv.DB) error {
+		return db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.CreateBucket(ctx, libkv.NewBucketName("a"))
+			if err != nil {
+				return err
+			}
+			return bucket.Put(ctx, []byte("k"), []byte("v"))
+		})
+	}
+	It("honors dir and pattern", func() {
+		db, err := boltkv.OpenTempWithOptions(ctx, boltkv.TempOptions{
+			Dir:     dir,
+			Pattern: "test-*.bolt",
+		})
+		Expect(err).To(BeNil())
+		defer db.Close()
+		Expect(filepath.Dir(db.DB().Path())).To(Equal(dir))
+		name := filepath.Base(db.DB().Path())
+		Expect(strings.HasPrefix(name, "test-")).To(BeTrue())
+		Expect(strings.HasSuffix(name, ".bolt")).To(BeTrue())
+	})
+	It("removes on close", func() {
+		db, err := boltkv.OpenTempWithOptions(ctx, boltkv.TempOptions{
+			Dir:           dir,
+			RemoveOnClose: true,
+		})
+		Expect(err).To(BeNil())
+		Expect(put(db)).To(Succeed())
+		Expect(files(dir)).To(HaveLen(1))
+		Expect(db.Close()).To(Succeed())
+		Expect(files(dir)).To(BeEmpty())
+		Expect(db.Remove()).To(Succeed())
+	})
+	It("applies DBOptions", func() {
+		var puts int
+		db, err := boltkv.OpenTempWithOptions(ctx, boltkv.TempOptions{
+			Dir:           dir,
+			RemoveOnClose: true,
+			DBOptions: []boltkv.ChangeDBOptions{boltkv.WithHook(boltkv.HookFuncs{
+				EndFunc: func(ctx context.Context, op boltkv.Operation, name libkv.BucketName, err error) {
+					if op == boltkv.OperationPut {
+						puts++
+					}
+				},
+			})},
+		})
+		Expect(err).To(BeNil())
+		Expect(put(db)).To(Succeed())
+		Expect(puts).To(Equal(1))
+		Expect(db.Close()).To(Succeed())
+		Expect(files(dir)).To(BeEmpty())
+	})
+	It("keeps the file without RemoveOnClose", func() {
+		db, err := boltkv.OpenTempWithOptions(ctx, boltkv.TempOptions{Dir: dir})
+		Expect(err).To(BeNil())
+		Expect(db.Close()).To(Succeed())
+		Expect(files(dir)).To(HaveLen(1))
+		Expect(db.Remove()).To(Succeed())
+		Expect(files(dir)).To(BeEmpty())
+	})
+	It("does not leak file descriptors", func() {
+		if _, err := os.Stat("/proc/self/fd"); err != nil {
+			Skip("/proc/self/fd not available")
+		}
+		before := len(files("/proc/self/fd"))
+		for i := 0; i < 10; i++ {
+			db, err := boltkv.OpenTempWithOptions(ctx, boltkv.TempOptions{
+				Dir:           dir,
+				RemoveOnClose: true,
+			})
+			Expect(err).To(BeNil())
+			Expect(db.Close()).To(Succeed())
+		}
+		Expect(len(files("/proc/self/fd"))).To(BeNumerically("<=", before))
+	})
+	It("fails for canceled context", func() {
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := boltkv.OpenTempWithOptions(canceled, boltkv.TempOptions{Dir: dir})
+		Expect(err).NotTo(BeNil())
+		Expect(files(dir)).To(BeEmpty())
+	})
+	It("places memory databases in MemoryTempDir", func() {
+		db, err := boltkv.OpenTempWithOptions(ctx, boltkv.TempOptions{
+			Memory:        true,
+			RemoveOnClose: true,
+		})
+		Expect(err).To(BeNil())
+		defer db.Close()
+		Expect(filepath.Dir(db.DB().Path())).To(Equal(filepath.Clean(boltkv.MemoryTempDir())))
+		Expect(put(db)).To(Succeed())
+	})
+})
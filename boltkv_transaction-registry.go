@@ -0,0 +1,129 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv
+
+import (
+	"context"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// TransactionInfo describes a transaction that is or was open.
+type TransactionInfo struct {
+	ID        uint64
+	Operation Operation
+	Started   time.Time
+	// Stack is the caller stack captured at transaction start.
+	// Only set if a slow transaction threshold is configured.
+	Stack string
+}
+
+// Age returns how long the transaction is open.
+func (t TransactionInfo) Age() time.Duration {
+	return time.Since(t.Started)
+}
+
+// SlowTransactionHook can be implemented by a Hook to get notified about
+// Update and View calls exceeding DBOptions.SlowTransactionThreshold.
+//
+//counterfeiter:generate -o mocks/boltkv-slow-transaction-hook.go --fake-name BoltkvSlowTransactionHook . SlowTransactionHook
+type SlowTransactionHook interface {
+	SlowTransaction(ctx context.Context, info TransactionInfo, duration time.Duration)
+}
+
+// transactionLister is implemented by the DBs returned by NewDB.
+type transactionLister interface {
+	openTransactions() []TransactionInfo
+}
+
+// OpenTransactions returns all currently open Update and View transactions of
+// db, oldest first, nil if db was not created by NewDB or the open functions.
+func OpenTransactions(db DB) []TransactionInfo {
+	lister, ok := db.(transactionLister)
+	if !ok {
+		return nil
+	}
+	return lister.openTransactions()
+}
+
+func newTransactionRegistry(slowThreshold time.Duration, hook Hook) *transactionRegistry {
+	return &transactionRegistry{
+		slowThreshold: slowThreshold,
+		hook:          hook,
+		open:          make(map[uint64]TransactionInfo),
+	}
+}
+
+// transactionRegistry tracks all currently open transactions of a DB.
+type transactionRegistry struct {
+	slowThreshold time.Duration
+	hook          Hook
+
+	mux    sync.Mutex
+	nextID uint64
+	open   map[uint64]TransactionInfo
+}
+
+func (r *transactionRegistry) Start(op Operation) TransactionInfo {
+	info := TransactionInfo{
+		Operation: op,
+		Started:   time.Now(),
+	}
+	if r.slowThreshold > 0 {
+		info.Stack = string(debug.Stack())
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.nextID++
+	info.ID = r.nextID
+	r.open[info.ID] = info
+	return info
+}
+
+func (r *transactionRegistry) End(ctx context.Context, info TransactionInfo) {
+	r.mux.Lock()
+	delete(r.open, info.ID)
+	r.mux.Unlock()
+
+	if r.slowThreshold <= 0 {
+		return
+	}
+	duration := info.Age()
+	if duration < r.slowThreshold {
+		return
+	}
+	glog.Warningf(
+		"slow %s transaction %d took %v (threshold %v), started at:\n%s",
+		info.Operation,
+		info.ID,
+		duration,
+		r.slowThreshold,
+		info.Stack,
+	)
+	if slowTransactionHook, ok := r.hook.(SlowTransactionHook); ok {
+		slowTransactionHook.SlowTransaction(ctx, info, duration)
+	}
+}
+
+// List returns all open transactions, oldest first.
+func (r *transactionRegistry) List() []TransactionInfo {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	result := make([]TransactionInfo, 0, len(r.open))
+	for _, info := range r.open {
+		result = append(result, info)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
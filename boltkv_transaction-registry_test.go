@@ -0,0 +1,99 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv_test
+
+import (
+	"context"
+	"time"
+
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+	"github.com/bborbe/boltkv/mocks"
+)
+
+var _ = Describe("Transaction registry", func() {
+	var ctx context.Context
+	var tempDB boltkv.DB
+	BeforeEach(func() {
+		ctx = context.Background()
+		var err error
+		tempDB, err = boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+	})
+	AfterEach(func() {
+		_ = tempDB.Close()
+		_ = tempDB.Remove()
+	})
+	Context("OpenTransactions", func() {
+		It("returns empty list without open transactions", func() {
+			Expect(boltkv.OpenTransactions(tempDB)).To(BeEmpty())
+		})
+		It("returns nil for DBs not created by NewDB", func() {
+			Expect(boltkv.OpenTransactions(&mocks.BoltkvDB{})).To(BeNil())
+		})
+		It("lists the open transactions", func() {
+			err := tempDB.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+				transactions := boltkv.OpenTransactions(tempDB)
+				Expect(transactions).To(HaveLen(1))
+				Expect(transactions[0].Operation).To(Equal(boltkv.OperationView))
+				Expect(transactions[0].Age()).To(BeNumerically(">=", 0))
+				Expect(transactions[0].Stack).To(BeEmpty())
+				return nil
+			})
+			Expect(err).To(BeNil())
+			Expect(boltkv.OpenTransactions(tempDB)).To(BeEmpty())
+		})
+	})
+	Context("SlowTransactionThreshold", func() {
+		var db boltkv.DB
+		var slow []boltkv.TransactionInfo
+		BeforeEach(func() {
+			slow = nil
+			db = boltkv.NewDB(
+				tempDB.DB(),
+				boltkv.WithSlowTransactionThreshold(10*time.Millisecond),
+				boltkv.WithHook(boltkv.HookFuncs{
+					SlowTransactionFunc: func(
+						ctx context.Context,
+						info boltkv.TransactionInfo,
+						duration time.Duration,
+					) {
+						Expect(duration).To(BeNumerically(">=", 10*time.Millisecond))
+						slow = append(slow, info)
+					},
+				}),
+			)
+		})
+		It("captures the stack at start", func() {
+			err := db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+				transactions := boltkv.OpenTransactions(db)
+				Expect(transactions).To(HaveLen(1))
+				Expect(transactions[0].Stack).To(ContainSubstring("boltkv_transaction-registry_test.go"))
+				return nil
+			})
+			Expect(err).To(BeNil())
+		})
+		It("does not report fast transactions", func() {
+			err := db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+				return nil
+			})
+			Expect(err).To(BeNil())
+			Expect(slow).To(BeEmpty())
+		})
+		It("reports slow transactions to the hook", func() {
+			err := db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+				time.Sleep(20 * time.Millisecond)
+				return nil
+			})
+			Expect(err).To(BeNil())
+			Expect(slow).To(HaveLen(1))
+			Expect(slow[0].Operation).To(Equal(boltkv.OperationUpdate))
+			Expect(slow[0].Stack).NotTo(BeEmpty())
+		})
+	})
+})
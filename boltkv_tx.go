@@ -5,6 +5,7 @@
 package boltkv
 
 import (
+	"bytes"
 	"context"
 	"sync"
 
@@ -13,20 +14,48 @@ import (
 	bolt "go.etcd.io/bbolt"
 )
 
+// InternalBucketPrefix starts the names of the buckets boltkv keeps its own
+// metadata in, e.g. _boltkv_fingerprint. ListBucketNames skips them, so Copy,
+// Export, Diff and the CLI only see user data.
+const InternalBucketPrefix = "_boltkv_"
+
+// IsInternalBucket reports whether name starts with InternalBucketPrefix.
+func IsInternalBucket(name libkv.BucketName) bool {
+	return bytes.HasPrefix(name, []byte(InternalBucketPrefix))
+}
+
+//counterfeiter:generate -o mocks/boltkv-tx.go --fake-name BoltkvTx . Tx
 type Tx interface {
 	libkv.Tx
 	Tx() *bolt.Tx
 }
 
 func NewTx(boltTx *bolt.Tx) Tx {
+	return newTx(boltTx, noopHook{}, nil, nil)
+}
+
+func newTx(
+	boltTx *bolt.Tx,
+	hook Hook,
+	fingerprints map[string]bool,
+	recorder *replicationRecorder,
+) Tx {
 	return &tx{
-		boltTx: boltTx,
-		cache:  make(map[string]libkv.Bucket),
+		boltTx:       boltTx,
+		hook:         hook,
+		fingerprints: fingerprints,
+		recorder:     recorder,
+		cache:        make(map[string]libkv.Bucket),
 	}
 }
 
 type tx struct {
 	boltTx *bolt.Tx
+	hook   Hook
+	// fingerprints contains the buckets with a stored fingerprint, see WithFingerprint
+	fingerprints map[string]bool
+	// recorder collects the mutations for the replication log, nil if disabled
+	recorder *replicationRecorder
 
 	mux   sync.Mutex
 	cache map[string]libkv.Bucket
@@ -35,7 +64,9 @@ type tx struct {
 func (t *tx) ListBucketNames(ctx context.Context) (libkv.BucketNames, error) {
 	result := libkv.BucketNames{}
 	err := t.boltTx.ForEach(func(name []byte, buckets *bolt.Bucket) error {
-		result = append(result, name)
+		if !IsInternalBucket(name) {
+			result = append(result, name)
+		}
 		return nil
 	})
 	if err != nil {
@@ -48,7 +79,12 @@ func (t *tx) Tx() *bolt.Tx {
 	return t.boltTx
 }
 
-func (t *tx) Bucket(ctx context.Context, name libkv.BucketName) (libkv.Bucket, error) {
+func (t *tx) Bucket(ctx context.Context, name libkv.BucketName) (_ libkv.Bucket, err error) {
+	ctx = t.hook.Start(ctx, OperationBucket, name)
+	defer func() {
+		t.hook.End(ctx, OperationBucket, name, err)
+	}()
+
 	t.mux.Lock()
 	defer t.mux.Unlock()
 
@@ -60,7 +96,7 @@ func (t *tx) Bucket(ctx context.Context, name libkv.BucketName) (libkv.Bucket, e
 	if boltBucket == nil {
 		return nil, errors.Wrapf(ctx, libkv.BucketNotFoundError, "bucket %s not found", name)
 	}
-	bucket = NewBucket(boltBucket)
+	bucket = t.newBucket(ctx, boltBucket, name)
 	t.cache[name.String()] = bucket
 	return bucket, nil
 }
@@ -81,7 +117,8 @@ func (t *tx) CreateBucket(ctx context.Context, name libkv.BucketName) (libkv.Buc
 		}
 		return nil, errors.Wrapf(ctx, err, "create bucket failed")
 	}
-	bucket := NewBucket(boltBucket)
+	t.recorder.record(ReplicationMutationCreateBucket, name, nil, nil)
+	bucket := t.newBucket(ctx, boltBucket, name)
 	t.cache[name.String()] = bucket
 	return bucket, nil
 }
@@ -98,11 +135,15 @@ func (t *tx) CreateBucketIfNotExists(
 		return bucket, nil
 	}
 
+	existed := t.boltTx.Bucket(name) != nil
 	boltBucket, err := t.boltTx.CreateBucketIfNotExists(name)
 	if err != nil {
 		return nil, errors.Wrapf(ctx, err, "create bucket if not exists failed")
 	}
-	bucket = NewBucket(boltBucket)
+	if !existed {
+		t.recorder.record(ReplicationMutationCreateBucket, name, nil, nil)
+	}
+	bucket = t.newBucket(ctx, boltBucket, name)
 	t.cache[name.String()] = bucket
 	return bucket, nil
 }
@@ -118,5 +159,28 @@ func (t *tx) DeleteBucket(ctx context.Context, name libkv.BucketName) error {
 		return errors.Wrapf(ctx, err, "delete bucket failed")
 	}
 	delete(t.cache, name.String())
+	t.recorder.record(ReplicationMutationDeleteBucket, name, nil, nil)
+	if tracker := t.fingerprintTracker(name); tracker != nil {
+		if err := tracker.clear(); err != nil {
+			return errors.Wrapf(ctx, err, "clear fingerprint failed")
+		}
+	}
 	return nil
 }
+
+func (t *tx) newBucket(ctx context.Context, boltBucket *bolt.Bucket, name libkv.BucketName) Bucket {
+	return newBucket(ctx, boltBucket, name, t.hook, t.fingerprintTracker(name), t.recorder)
+}
+
+// fingerprintTracker returns nil for read-only transactions and buckets
+// neither configured with WithFingerprint nor having a stored fingerprint.
+func (t *tx) fingerprintTracker(name libkv.BucketName) *fingerprintTracker {
+	if !t.boltTx.Writable() {
+		return nil
+	}
+	tracker := &fingerprintTracker{tx: t.boltTx, name: name}
+	if !t.fingerprints[string(name)] && !tracker.stored() {
+		return nil
+	}
+	return tracker
+}
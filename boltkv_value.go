@@ -0,0 +1,125 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkv
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+)
+
+// ListBuckets calls fn for every top-level bucket.
+func ListBuckets(
+	ctx context.Context,
+	db libkv.DB,
+	fn func(bucketName libkv.BucketName) error,
+) error {
+	return db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		bucketNames, err := tx.ListBucketNames(ctx)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "list bucketNames failed")
+		}
+		for _, bucketName := range bucketNames {
+			if err := fn(bucketName); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CreateBucket creates the bucket, it fails if the bucket already exists.
+func CreateBucket(ctx context.Context, db libkv.DB, bucketName libkv.BucketName) error {
+	return db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		_, err := tx.CreateBucket(ctx, bucketName)
+		return err
+	})
+}
+
+// DeleteBucket deletes the bucket and all its keys.
+func DeleteBucket(ctx context.Context, db libkv.DB, bucketName libkv.BucketName) error {
+	return db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		return tx.DeleteBucket(ctx, bucketName)
+	})
+}
+
+// BucketStats returns key count and size of all top-level buckets,
+// without the internal buckets of boltkv.
+func BucketStats(ctx context.Context, db libkv.DB) ([]libkv.BucketStats, error) {
+	stats, err := db.StatsDetailed(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "stats failed")
+	}
+	result := make([]libkv.BucketStats, 0, len(stats.Buckets))
+	for _, bucketStats := range stats.Buckets {
+		if !IsInternalBucket(bucketStats.Name) {
+			result = append(result, bucketStats)
+		}
+	}
+	return result, nil
+}
+
+// GetValue returns the value of key, nil if the key does not exist.
+func GetValue(
+	ctx context.Context,
+	db libkv.DB,
+	bucketName libkv.BucketName,
+	key []byte,
+) ([]byte, error) {
+	var result []byte
+	err := db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		bucket, err := tx.Bucket(ctx, bucketName)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "get bucket failed")
+		}
+		item, err := bucket.Get(ctx, key)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "get key failed")
+		}
+		return item.Value(func(value []byte) error {
+			result = bytes.Clone(value)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetValue writes the value of key, the bucket is created if it does not exist.
+func SetValue(
+	ctx context.Context,
+	db libkv.DB,
+	bucketName libkv.BucketName,
+	key []byte,
+	value []byte,
+) error {
+	return db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(ctx, bucketName)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "get bucket failed")
+		}
+		return bucket.Put(ctx, key, value)
+	})
+}
+
+// DeleteValue deletes key, a missing bucket is reported as libkv.BucketNotFoundError.
+func DeleteValue(
+	ctx context.Context,
+	db libkv.DB,
+	bucketName libkv.BucketName,
+	key []byte,
+) error {
+	return db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		bucket, err := tx.Bucket(ctx, bucketName)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "get bucket failed")
+		}
+		return bucket.Delete(ctx, key)
+	})
+}
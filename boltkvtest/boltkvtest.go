@@ -0,0 +1,34 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package boltkvtest provides helpers for tests using boltkv.
+package boltkvtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bborbe/boltkv"
+)
+
+// OpenDB opens a temporary database removed on Close and closes it in
+// tb.Cleanup. It fails the test if the database cannot be opened.
+func OpenDB(tb testing.TB, opts boltkv.TempOptions, fn ...boltkv.ChangeOptions) boltkv.DB {
+	tb.Helper()
+	if opts.Dir == "" && !opts.Memory {
+		opts.Dir = tb.TempDir()
+	}
+	opts.RemoveOnClose = true
+	db, err := boltkv.OpenTempWithOptions(context.Background(), opts, fn...)
+	if err != nil {
+		tb.Fatalf("open test db failed: %v", err)
+		return nil
+	}
+	tb.Cleanup(func() {
+		if err := db.Close(); err != nil {
+			tb.Errorf("close test db failed: %v", err)
+		}
+	})
+	return db
+}
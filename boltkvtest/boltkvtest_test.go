@@ -0,0 +1,93 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package boltkvtest_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+	"github.com/bborbe/boltkv/boltkvtest"
+)
+
+// fakeTB records cleanups and failures, all other methods panic.
+type fakeTB struct {
+	testing.TB
+	dir      string
+	cleanups []func()
+	failures []string
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) TempDir() string { return f.dir }
+
+func (f *fakeTB) Cleanup(fn func()) { f.cleanups = append(f.cleanups, fn) }
+
+func (f *fakeTB) Fatalf(format string, args ...interface{}) {
+	f.failures = append(f.failures, format)
+}
+
+func (f *fakeTB) Errorf(format string, args ...interface{}) {
+	f.failures = append(f.failures, format)
+}
+
+func (f *fakeTB) runCleanups() {
+	for i := len(f.cleanups) - 1; i >= 0; i-- {
+		f.cleanups[i]()
+	}
+}
+
+var _ = Describe("OpenDB", func() {
+	var ctx context.Context
+	var dir string
+	BeforeEach(func() {
+		ctx = context.Background()
+		dir = GinkgoT().TempDir()
+	})
+	files := func(dir string) []string {
+		entries, err := os.ReadDir(dir)
+		Expect(err).To(BeNil())
+		var result []string
+		for _, entry := range entries {
+			result = append(result, entry.Name())
+		}
+		return result
+	}
+	put := func(db libkv.DB) error {
+		return db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.CreateBucket(ctx, libkv.NewBucketName("a"))
+			if err != nil {
+				return err
+			}
+			return bucket.Put(ctx, []byte("k"), []byte("v"))
+		})
+	}
+	It("closes and removes test databases in cleanup", func() {
+		tb := &fakeTB{dir: dir}
+		db := boltkvtest.OpenDB(tb, boltkv.TempOptions{})
+		Expect(put(db)).To(Succeed())
+		Expect(files(dir)).To(HaveLen(1))
+		Expect(tb.cleanups).To(HaveLen(1))
+		tb.runCleanups()
+		Expect(tb.failures).To(BeEmpty())
+		Expect(files(dir)).To(BeEmpty())
+	})
+	It("fails the test if the database cannot be opened", func() {
+		tb := &fakeTB{dir: filepath.Join(dir, "missing")}
+		Expect(boltkvtest.OpenDB(tb, boltkv.TempOptions{})).To(BeNil())
+		Expect(tb.failures).To(HaveLen(1))
+	})
+	It("works with GinkgoTB", func() {
+		db := boltkvtest.OpenDB(GinkgoTB(), boltkv.TempOptions{Memory: true})
+		Expect(put(db)).To(Succeed())
+	})
+})
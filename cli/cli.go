@@ -0,0 +1,192 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package cli implements the boltkv command line tool.
+// The operations are exported for reuse by the single-purpose bolt-* commands.
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bborbe/errors"
+
+	"github.com/bborbe/boltkv"
+)
+
+// arguments holds all flags, each command registers the ones it uses.
+type arguments struct {
+	DataDir        string
+	Format         string
+	KeyEncoding    string
+	Verbosity      int
+	Bucket         string
+	Key            string
+	Value          string
+	ValueFile      string
+	Stdin          bool
+	ValueEncoding  string
+	NoCreateBucket bool
+	Bulk           string
+	BatchSize      int
+	Prefix         string
+	Start          string
+	End            string
+	Limit          int
+	Reverse        bool
+	KeysOnly       bool
+	Count          bool
+	Regex          string
+	OlderThan      string
+	TimeFormat     string
+	DryRun         bool
+	Output         string
+	Input          string
+	ReadOnly       bool
+}
+
+type environment struct {
+	args        *arguments
+	db          boltkv.DB
+	writer      Writer
+	keyEncoding boltkv.Encoding
+	stdin       io.Reader
+	stdout      io.Writer
+}
+
+// key returns the -key flag decoded with the key encoding.
+func (e *environment) key(ctx context.Context) ([]byte, error) {
+	return e.keyEncoding.Decode(ctx, e.args.Key)
+}
+
+type command struct {
+	name     string
+	usage    string
+	readOnly bool
+	// flags registers the command specific flags
+	flags func(fs *flag.FlagSet, args *arguments)
+	// required lists the flags that must not be empty
+	required []string
+	run      func(ctx context.Context, env *environment) error
+}
+
+// Main runs the command given by args (without the program name) and returns the exit code.
+func Main(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	cmd, rest := findCommand(args)
+	if cmd == nil {
+		printUsage(stderr)
+		return 2
+	}
+	arguments := &arguments{}
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: boltkv %s [flags]\n\n%s\n\n", cmd.name, cmd.usage)
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&arguments.DataDir, "datadir", os.Getenv("DATADIR"), "data directory")
+	fs.StringVar(
+		&arguments.Format,
+		"format",
+		string(FormatText),
+		fmt.Sprintf("output format %v", Formats),
+	)
+	fs.StringVar(
+		&arguments.KeyEncoding,
+		"key-encoding",
+		string(boltkv.EncodingUTF8),
+		fmt.Sprintf("encoding of keys in flags and output %v", boltkv.KeyEncodings),
+	)
+	fs.IntVar(&arguments.Verbosity, "v", 0, "log level for V logs")
+	if cmd.flags != nil {
+		cmd.flags(fs, arguments)
+	}
+	if err := fs.Parse(rest); err != nil {
+		return 2
+	}
+	for _, name := range append([]string{"datadir"}, cmd.required...) {
+		if fs.Lookup(name).Value.String() == "" {
+			fmt.Fprintf(stderr, "flag -%s is required\n", name)
+			fs.Usage()
+			return 2
+		}
+	}
+	if arguments.Verbosity > 0 {
+		_ = flag.Set("v", strconv.Itoa(arguments.Verbosity))
+	}
+	if err := run(ctx, cmd, arguments, stdout); err != nil {
+		fmt.Fprintf(stderr, "boltkv %s failed: %v\n", cmd.name, err)
+		return 1
+	}
+	return 0
+}
+
+func run(ctx context.Context, cmd *command, args *arguments, stdout io.Writer) error {
+	format, err := ParseFormat(ctx, args.Format)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "parse format failed")
+	}
+	keyEncoding, err := boltkv.ParseKeyEncoding(ctx, args.KeyEncoding)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "parse key encoding failed")
+	}
+	writer, err := NewWriter(ctx, stdout, format, keyEncoding)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "create writer failed")
+	}
+	db, err := OpenDB(ctx, args.DataDir, cmd.readOnly || args.ReadOnly)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "open db failed")
+	}
+	defer db.Close()
+	env := &environment{
+		args:        args,
+		db:          db,
+		writer:      writer,
+		keyEncoding: keyEncoding,
+		stdin:       os.Stdin,
+		stdout:      stdout,
+	}
+	if err := cmd.run(ctx, env); err != nil {
+		_ = writer.Close()
+		return err
+	}
+	return writer.Close()
+}
+
+func findCommand(args []string) (*command, []string) {
+	for _, words := range []int{2, 1} {
+		if len(args) < words {
+			continue
+		}
+		name := strings.Join(args[:words], " ")
+		for _, cmd := range commands {
+			if cmd.name == name {
+				return cmd, args[words:]
+			}
+		}
+	}
+	return nil, nil
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "usage: boltkv <command> [flags]\n\ncommands:\n")
+	names := make([]string, 0, len(commands))
+	usages := make(map[string]string, len(commands))
+	for _, cmd := range commands {
+		names = append(names, cmd.name)
+		usages[cmd.name] = cmd.usage
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-16s %s\n", name, usages[name])
+	}
+	fmt.Fprintf(w, "\nrun 'boltkv <command> -h' for the flags of a command\n")
+}
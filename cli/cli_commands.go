@@ -0,0 +1,364 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	"github.com/golang/glog"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bborbe/boltkv"
+)
+
+func bucketFlag(fs *flag.FlagSet, args *arguments) {
+	fs.StringVar(&args.Bucket, "bucket", "", "bucket name")
+}
+
+func keyFlags(fs *flag.FlagSet, args *arguments) {
+	bucketFlag(fs, args)
+	fs.StringVar(&args.Key, "key", "", "key")
+}
+
+func outputFlag(fs *flag.FlagSet, args *arguments) {
+	fs.StringVar(&args.Output, "output", "", "output file")
+}
+
+var commands = []*command{
+	{
+		name:     "bucket list",
+		usage:    "list all buckets",
+		readOnly: true,
+		run: func(ctx context.Context, env *environment) error {
+			return boltkv.ListBuckets(ctx, env.db, env.writer.Bucket)
+		},
+	},
+	{
+		name:     "bucket create",
+		usage:    "create a bucket",
+		flags:    bucketFlag,
+		required: []string{"bucket"},
+		run: func(ctx context.Context, env *environment) error {
+			return boltkv.CreateBucket(ctx, env.db, libkv.NewBucketName(env.args.Bucket))
+		},
+	},
+	{
+		name:     "bucket delete",
+		usage:    "delete a bucket with all its keys",
+		flags:    bucketFlag,
+		required: []string{"bucket"},
+		run: func(ctx context.Context, env *environment) error {
+			return boltkv.DeleteBucket(ctx, env.db, libkv.NewBucketName(env.args.Bucket))
+		},
+	},
+	{
+		name:     "bucket stats",
+		usage:    "print key count and size of all buckets",
+		readOnly: true,
+		run: func(ctx context.Context, env *environment) error {
+			stats, err := boltkv.BucketStats(ctx, env.db)
+			if err != nil {
+				return err
+			}
+			for _, s := range stats {
+				if err := env.writer.BucketStats(s); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		name:     "bucket fingerprint",
+		usage:    "print key count and content hash of one or all buckets",
+		readOnly: true,
+		flags:    bucketFlag,
+		run: func(ctx context.Context, env *environment) error {
+			fingerprints, err := BucketFingerprints(
+				ctx,
+				env.db,
+				libkv.NewBucketName(env.args.Bucket),
+			)
+			if err != nil {
+				return err
+			}
+			for _, fingerprint := range fingerprints {
+				if err := env.writer.Fingerprint(fingerprint); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		name:     "value get",
+		usage:    "print the value of a key",
+		readOnly: true,
+		flags:    keyFlags,
+		required: []string{"bucket", "key"},
+		run: func(ctx context.Context, env *environment) error {
+			key, err := env.key(ctx)
+			if err != nil {
+				return err
+			}
+			value, err := boltkv.GetValue(ctx, env.db, libkv.NewBucketName(env.args.Bucket), key)
+			if err != nil {
+				return err
+			}
+			if value == nil {
+				return errors.Wrapf(ctx, libkv.KeyNotFoundError, "key %q not found", key)
+			}
+			return env.writer.Value(key, value)
+		},
+	},
+	{
+		name:  "value set",
+		usage: "write the value of a key or pairs from stdin, creates the bucket if missing",
+		flags: func(fs *flag.FlagSet, args *arguments) {
+			keyFlags(fs, args)
+			fs.StringVar(&args.Value, "value", "", "value")
+			fs.StringVar(&args.ValueFile, "value-file", "", "read the value from file")
+			fs.BoolVar(&args.Stdin, "stdin", false, "read the value from stdin")
+			fs.StringVar(
+				&args.ValueEncoding,
+				"value-encoding",
+				string(boltkv.EncodingUTF8),
+				fmt.Sprintf("encoding of the value input %v", ValueEncodings),
+			)
+			fs.BoolVar(&args.NoCreateBucket, "no-create-bucket", false, "fail if the bucket is missing")
+			fs.StringVar(
+				&args.Bulk,
+				"bulk",
+				"",
+				fmt.Sprintf("read key value pairs from stdin in format %v", BulkFormats),
+			)
+			fs.IntVar(&args.BatchSize, "batch-size", DefaultBulkBatchSize, "pairs per transaction")
+		},
+		required: []string{"bucket"},
+		run: func(ctx context.Context, env *environment) error {
+			req, err := ParseSetRequest(
+				ctx,
+				env.args.Key,
+				env.keyEncoding,
+				env.args.ValueEncoding,
+				env.args.Bulk,
+			)
+			if err != nil {
+				return err
+			}
+			req.Input.Value = env.args.Value
+			req.Input.File = env.args.ValueFile
+			req.Input.Stdin = env.args.Stdin
+			req.BatchSize = env.args.BatchSize
+			req.NoCreateBucket = env.args.NoCreateBucket
+			written, err := Set(ctx, env.db, libkv.NewBucketName(env.args.Bucket), req, env.stdin)
+			if err != nil {
+				return err
+			}
+			glog.V(2).Infof("%d keys written", written)
+			return nil
+		},
+	},
+	{
+		name:  "value delete",
+		usage: "delete a key or all keys selected by prefix, range, regex or age",
+		flags: func(fs *flag.FlagSet, args *arguments) {
+			keyFlags(fs, args)
+			fs.StringVar(&args.Prefix, "prefix", "", "delete keys starting with prefix")
+			fs.StringVar(&args.Start, "start", "", "first key to delete (inclusive)")
+			fs.StringVar(&args.End, "end", "", "last key to delete (exclusive)")
+			fs.StringVar(&args.Regex, "regex", "", "delete keys matching the regular expression")
+			fs.StringVar(
+				&args.OlderThan,
+				"older-than",
+				"",
+				"delete keys with a time prefix older than a duration or RFC3339 time",
+			)
+			fs.StringVar(
+				&args.TimeFormat,
+				"time-format",
+				string(KeyTimeFormatRFC3339),
+				fmt.Sprintf("time prefix of keys for older-than %v", KeyTimeFormats),
+			)
+			fs.IntVar(&args.Limit, "limit", 0, "maximum number of keys to delete, 0 for all")
+			fs.IntVar(&args.BatchSize, "batch-size", DefaultDeleteBatchSize, "keys per transaction")
+			fs.BoolVar(&args.DryRun, "dry-run", false, "print the selected keys without deleting")
+		},
+		required: []string{"bucket"},
+		run: func(ctx context.Context, env *environment) error {
+			timeFormat, err := ParseKeyTimeFormat(ctx, env.args.TimeFormat)
+			if err != nil {
+				return err
+			}
+			return Delete(ctx, env.db, libkv.NewBucketName(env.args.Bucket), DeleteRequest{
+				Key:         env.args.Key,
+				KeyEncoding: env.keyEncoding,
+				Prefix:      env.args.Prefix,
+				Start:       env.args.Start,
+				End:         env.args.End,
+				Regex:       env.args.Regex,
+				OlderThan:   env.args.OlderThan,
+				TimeFormat:  timeFormat,
+				Limit:       env.args.Limit,
+				BatchSize:   env.args.BatchSize,
+				DryRun:      env.args.DryRun,
+			}, env.writer)
+		},
+	},
+	{
+		name:     "value list",
+		usage:    "print the keys and values of a bucket, optionally limited to a range",
+		readOnly: true,
+		flags: func(fs *flag.FlagSet, args *arguments) {
+			bucketFlag(fs, args)
+			fs.StringVar(&args.Prefix, "prefix", "", "only keys starting with prefix")
+			fs.StringVar(&args.Start, "start", "", "first key (inclusive)")
+			fs.StringVar(&args.End, "end", "", "last key (exclusive)")
+			fs.IntVar(&args.Limit, "limit", 0, "maximum number of keys, 0 for all")
+			fs.BoolVar(&args.Reverse, "reverse", false, "list in reverse key order")
+			fs.BoolVar(&args.KeysOnly, "keys-only", false, "print keys without values")
+			fs.BoolVar(&args.Count, "count", false, "print the number of keys only")
+		},
+		required: []string{"bucket"},
+		run: func(ctx context.Context, env *environment) error {
+			opts, err := ParseListOptions(
+				ctx,
+				env.keyEncoding,
+				env.args.Prefix,
+				env.args.Start,
+				env.args.End,
+			)
+			if err != nil {
+				return err
+			}
+			opts.Limit = env.args.Limit
+			opts.Reverse = env.args.Reverse
+			opts.KeysOnly = env.args.KeysOnly
+			opts.Count = env.args.Count
+			return List(ctx, env.db, libkv.NewBucketName(env.args.Bucket), opts, env.writer)
+		},
+	},
+	{
+		name:     "value scan",
+		usage:    "print all keys and values of a bucket starting with a prefix",
+		readOnly: true,
+		flags: func(fs *flag.FlagSet, args *arguments) {
+			bucketFlag(fs, args)
+			fs.StringVar(&args.Prefix, "prefix", "", "key prefix")
+		},
+		required: []string{"bucket"},
+		run: func(ctx context.Context, env *environment) error {
+			prefix, err := env.keyEncoding.Decode(ctx, env.args.Prefix)
+			if err != nil {
+				return err
+			}
+			return boltkv.ScanValues(
+				ctx,
+				env.db,
+				libkv.NewBucketName(env.args.Bucket),
+				prefix,
+				env.writer.KeyValue,
+			)
+		},
+	},
+	{
+		name:  "diff apply",
+		usage: "apply a diff written by bolt-diff, so the database matches the new database",
+		flags: func(fs *flag.FlagSet, args *arguments) {
+			fs.StringVar(&args.Input, "input", "", "diff file, stdin if empty")
+			fs.IntVar(
+				&args.BatchSize,
+				"batch-size",
+				boltkv.DefaultApplyDiffBatchSize,
+				"records per transaction",
+			)
+		},
+		run: func(ctx context.Context, env *environment) error {
+			var r io.Reader = env.stdin
+			if env.args.Input != "" {
+				file, err := os.Open(env.args.Input)
+				if err != nil {
+					return errors.Wrapf(ctx, err, "open input failed")
+				}
+				defer file.Close()
+				r = file
+			}
+			applied, err := boltkv.ApplyDiff(ctx, env.db, r, env.args.BatchSize)
+			if err != nil {
+				return err
+			}
+			glog.V(2).Infof("%d diff records applied", applied)
+			return nil
+		},
+	},
+	{
+		name:     "backup",
+		usage:    "write a consistent copy of the database to a file",
+		readOnly: true,
+		flags:    outputFlag,
+		required: []string{"output"},
+		run: func(ctx context.Context, env *environment) error {
+			if err := boltkv.BackupFile(ctx, env.db, env.args.Output); err != nil {
+				return err
+			}
+			glog.V(2).Infof("backup to %s completed", env.args.Output)
+			return nil
+		},
+	},
+	{
+		name:     "compact",
+		usage:    "write a compacted copy of the database to a file",
+		readOnly: true,
+		flags:    outputFlag,
+		required: []string{"output"},
+		run: func(ctx context.Context, env *environment) error {
+			// close syncs NoSync databases, so the copy is durable once compact returns
+			err := boltkv.Compact(ctx, env.db, env.args.Output, func(opts *bolt.Options) {
+				opts.NoSync = true
+			})
+			if err != nil {
+				return err
+			}
+			glog.V(2).Infof("compact to %s completed", env.args.Output)
+			return nil
+		},
+	},
+	{
+		name:     "check",
+		usage:    "check the integrity of the database, fails on corruption",
+		readOnly: true,
+		run: func(ctx context.Context, env *environment) error {
+			findings, err := boltkv.Check(ctx, env.db)
+			if err != nil {
+				return err
+			}
+			for _, finding := range findings {
+				if err := env.writer.CheckFinding(finding); err != nil {
+					return err
+				}
+			}
+			if findings.Corrupted() {
+				return errors.Errorf(ctx, "database corrupted: %d findings", len(findings))
+			}
+			return nil
+		},
+	},
+	{
+		name:  "shell",
+		usage: "interactive shell, run help inside the shell for its commands",
+		flags: func(fs *flag.FlagSet, args *arguments) {
+			fs.BoolVar(&args.ReadOnly, "readonly", false, "open the database read-only")
+		},
+		run: func(ctx context.Context, env *environment) error {
+			return NewShell(env.db, env.stdout).Run(ctx, env.stdin)
+		},
+	},
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"context"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bborbe/boltkv"
+)
+
+// OpenDB opens the bolt.db in dataDir.
+// Read-only databases share the file lock with other readers and fail
+// instead of creating the file if it does not exist.
+func OpenDB(ctx context.Context, dataDir string, readOnly bool) (boltkv.DB, error) {
+	db, err := boltkv.OpenDir(ctx, dataDir, func(opts *bolt.Options) {
+		opts.ReadOnly = readOnly
+	})
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "open %s failed", dataDir)
+	}
+	return db, nil
+}
+
+// BucketFingerprints returns the fingerprint of the bucket, of all top-level
+// buckets if bucketName is empty.
+func BucketFingerprints(
+	ctx context.Context,
+	db boltkv.DB,
+	bucketName libkv.BucketName,
+) ([]boltkv.Fingerprint, error) {
+	bucketNames := libkv.BucketNames{bucketName}
+	if len(bucketName) == 0 {
+		bucketNames = nil
+		err := boltkv.ListBuckets(ctx, db, func(bucketName libkv.BucketName) error {
+			bucketNames = append(bucketNames, bucketName)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	result := make([]boltkv.Fingerprint, 0, len(bucketNames))
+	for _, bucketName := range bucketNames {
+		fingerprint, err := boltkv.BucketFingerprint(ctx, db, bucketName)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "fingerprint failed")
+		}
+		result = append(result, fingerprint)
+	}
+	return result, nil
+}
@@ -0,0 +1,328 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"regexp"
+	"time"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+
+	"github.com/bborbe/boltkv"
+)
+
+// DefaultDeleteBatchSize is the number of keys DeleteValues removes per Update.
+const DefaultDeleteBatchSize = 1000
+
+// KeyTimeFormat is the encoding of a time prefix in keys.
+// All formats sort in time order, so keys older than a time form a range.
+type KeyTimeFormat string
+
+const (
+	// KeyTimeFormatRFC3339 is a UTC time.RFC3339 text prefix, e.g. 2026-01-02T15:04:05Z.
+	KeyTimeFormatRFC3339 KeyTimeFormat = "rfc3339"
+	// KeyTimeFormatUnix is an 8 byte big-endian prefix of unix seconds.
+	KeyTimeFormatUnix KeyTimeFormat = "unix"
+	// KeyTimeFormatUnixMilli is an 8 byte big-endian prefix of unix milliseconds.
+	KeyTimeFormatUnixMilli KeyTimeFormat = "unixmilli"
+	// KeyTimeFormatUnixNano is an 8 byte big-endian prefix of unix nanoseconds.
+	KeyTimeFormatUnixNano KeyTimeFormat = "unixnano"
+)
+
+// KeyTimeFormats contains all supported key time formats.
+var KeyTimeFormats = []KeyTimeFormat{
+	KeyTimeFormatRFC3339,
+	KeyTimeFormatUnix,
+	KeyTimeFormatUnixMilli,
+	KeyTimeFormatUnixNano,
+}
+
+// ParseKeyTimeFormat returns the KeyTimeFormat for the given name.
+func ParseKeyTimeFormat(ctx context.Context, name string) (KeyTimeFormat, error) {
+	for _, format := range KeyTimeFormats {
+		if string(format) == name {
+			return format, nil
+		}
+	}
+	return "", errors.Errorf(
+		ctx,
+		"unknown key time format '%s', expected one of %v",
+		name,
+		KeyTimeFormats,
+	)
+}
+
+// Encode returns the key prefix of t.
+func (f KeyTimeFormat) Encode(ctx context.Context, t time.Time) ([]byte, error) {
+	switch f {
+	case KeyTimeFormatRFC3339:
+		return []byte(t.UTC().Format(time.RFC3339)), nil
+	case KeyTimeFormatUnix:
+		return binary.BigEndian.AppendUint64(nil, uint64(t.Unix())), nil
+	case KeyTimeFormatUnixMilli:
+		return binary.BigEndian.AppendUint64(nil, uint64(t.UnixMilli())), nil
+	case KeyTimeFormatUnixNano:
+		return binary.BigEndian.AppendUint64(nil, uint64(t.UnixNano())), nil
+	default:
+		return nil, errors.Errorf(ctx, "unknown key time format '%s'", f)
+	}
+}
+
+// ParseOlderThan returns the time given as duration before now or as RFC3339 timestamp.
+func ParseOlderThan(ctx context.Context, value string, now time.Time) (time.Time, error) {
+	if duration, err := time.ParseDuration(value); err == nil {
+		return now.Add(-duration), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.Errorf(
+			ctx,
+			"older-than '%s' is neither a duration nor a RFC3339 time",
+			value,
+		)
+	}
+	return t, nil
+}
+
+// DeleteOptions selects the keys DeleteValues removes.
+// ScanRange.Limit limits the total number of deleted keys, ScanRange.Reverse is ignored.
+type DeleteOptions struct {
+	boltkv.ScanRange
+	// Pattern only selects keys matching the regular expression
+	Pattern *regexp.Regexp
+	// DryRun selects the keys without deleting them
+	DryRun bool
+	// BatchSize defaults to DefaultDeleteBatchSize
+	BatchSize int
+}
+
+func (o DeleteOptions) match(key []byte) bool {
+	return o.Pattern == nil || o.Pattern.Match(key)
+}
+
+// DeleteValues deletes all selected keys in Updates of BatchSize keys and calls fn for each.
+// It returns the number of deleted keys, batches deleted before an error stay deleted.
+// A missing bucket is reported as libkv.BucketNotFoundError.
+func DeleteValues(
+	ctx context.Context,
+	db libkv.DB,
+	bucketName libkv.BucketName,
+	opts DeleteOptions,
+	fn func(key []byte) error,
+) (int64, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultDeleteBatchSize
+	}
+	if fn == nil {
+		fn = func(key []byte) error { return nil }
+	}
+	opts.Reverse = false
+	if opts.DryRun {
+		return selectValues(ctx, db, bucketName, opts, fn)
+	}
+	var deleted int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return deleted, errors.Wrapf(ctx, err, "context done")
+		}
+		batchSize := opts.BatchSize
+		if opts.Limit > 0 && int64(opts.Limit)-deleted < int64(batchSize) {
+			batchSize = opts.Limit - int(deleted)
+		}
+		if batchSize == 0 {
+			return deleted, nil
+		}
+		keys, err := deleteBatch(ctx, db, bucketName, opts, batchSize)
+		if err != nil {
+			return deleted, errors.Wrapf(ctx, err, "delete batch failed")
+		}
+		for _, key := range keys {
+			if err := fn(key); err != nil {
+				return deleted, err
+			}
+		}
+		deleted += int64(len(keys))
+		if len(keys) < batchSize {
+			return deleted, nil
+		}
+		// the last deleted key is gone, so seeking to it continues with the next key
+		opts.Start = keys[len(keys)-1]
+	}
+}
+
+// errLimitReached stops the iteration of selectValues.
+var errLimitReached = errors.New(context.Background(), "limit reached")
+
+// selectValues calls fn for all keys DeleteValues would delete.
+func selectValues(
+	ctx context.Context,
+	db libkv.DB,
+	bucketName libkv.BucketName,
+	opts DeleteOptions,
+	fn func(key []byte) error,
+) (int64, error) {
+	var count int64
+	err := boltkv.IterateRange(
+		ctx,
+		db,
+		bucketName,
+		opts.withoutLimit(),
+		func(key []byte, value []byte) error {
+			if !opts.match(key) {
+				return nil
+			}
+			if opts.Limit > 0 && count == int64(opts.Limit) {
+				return errLimitReached
+			}
+			count++
+			return fn(key)
+		},
+	)
+	if err != nil && !errors.Is(err, errLimitReached) {
+		return count, err
+	}
+	return count, nil
+}
+
+func (o DeleteOptions) withoutLimit() boltkv.ScanRange {
+	r := o.ScanRange
+	r.Limit = 0
+	return r
+}
+
+// deleteBatch deletes up to batchSize selected keys in one Update and returns them.
+func deleteBatch(
+	ctx context.Context,
+	db libkv.DB,
+	bucketName libkv.BucketName,
+	opts DeleteOptions,
+	batchSize int,
+) ([][]byte, error) {
+	var keys [][]byte
+	err := db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		keys = nil
+		bucket, err := tx.Bucket(ctx, bucketName)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "get bucket failed")
+		}
+		keyRange := opts.KeyRange()
+		it := opts.withoutLimit().Iterator(bucket)
+		for ; it.Valid() && len(keys) < batchSize; it.Next() {
+			key := it.Item().Key()
+			if !keyRange.Contains(key) {
+				break
+			}
+			if opts.match(key) {
+				keys = append(keys, bytes.Clone(key))
+			}
+		}
+		// deleting while iterating moves the cursor, so delete after iterating
+		it.Close()
+		for _, key := range keys {
+			if err := bucket.Delete(ctx, key); err != nil {
+				return errors.Wrapf(ctx, err, "delete key failed")
+			}
+		}
+		return nil
+	})
+	return keys, err
+}
+
+// DeleteRequest describes a value delete command,
+// a single Key or the keys selected by the other fields.
+type DeleteRequest struct {
+	Key         string
+	KeyEncoding boltkv.Encoding
+	Prefix      string
+	Start       string
+	End         string
+	Regex       string
+	// OlderThan is a duration before now or RFC3339 time, keys start with TimeFormat
+	OlderThan  string
+	TimeFormat KeyTimeFormat
+	Limit      int
+	BatchSize  int
+	DryRun     bool
+}
+
+func (r DeleteRequest) bulk() bool {
+	return r.Prefix != "" || r.Start != "" || r.End != "" || r.Regex != "" || r.OlderThan != ""
+}
+
+// Delete executes req. Bulk deletes write the number of deleted keys to writer,
+// dry runs the selected keys followed by their number.
+func Delete(
+	ctx context.Context,
+	db libkv.DB,
+	bucketName libkv.BucketName,
+	req DeleteRequest,
+	writer Writer,
+) error {
+	if !req.bulk() {
+		if req.Key == "" {
+			return errors.Errorf(ctx, "key or one of prefix, start, end, regex, older-than required")
+		}
+		key, err := req.KeyEncoding.Decode(ctx, req.Key)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "decode key failed")
+		}
+		if req.DryRun {
+			return writer.Key(key)
+		}
+		return boltkv.DeleteValue(ctx, db, bucketName, key)
+	}
+	if req.Key != "" {
+		return errors.Errorf(ctx, "key is not allowed with prefix, start, end, regex or older-than")
+	}
+	opts, err := req.options(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	var fn func(key []byte) error
+	if req.DryRun {
+		fn = writer.Key
+	}
+	count, err := DeleteValues(ctx, db, bucketName, opts, fn)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "delete values failed after %d keys", count)
+	}
+	return writer.Count(count)
+}
+
+func (r DeleteRequest) options(ctx context.Context, now time.Time) (DeleteOptions, error) {
+	listOptions, err := ParseListOptions(ctx, r.KeyEncoding, r.Prefix, r.Start, r.End)
+	if err != nil {
+		return DeleteOptions{}, err
+	}
+	opts := DeleteOptions{
+		ScanRange: listOptions.ScanRange,
+		DryRun:    r.DryRun,
+		BatchSize: r.BatchSize,
+	}
+	opts.Limit = r.Limit
+	if r.Regex != "" {
+		if opts.Pattern, err = regexp.Compile(r.Regex); err != nil {
+			return DeleteOptions{}, errors.Wrapf(ctx, err, "compile regex failed")
+		}
+	}
+	if r.OlderThan != "" {
+		olderThan, err := ParseOlderThan(ctx, r.OlderThan, now)
+		if err != nil {
+			return DeleteOptions{}, err
+		}
+		end, err := r.TimeFormat.Encode(ctx, olderThan)
+		if err != nil {
+			return DeleteOptions{}, err
+		}
+		if opts.End == nil || bytes.Compare(end, opts.End) < 0 {
+			opts.End = end
+		}
+	}
+	return opts, nil
+}
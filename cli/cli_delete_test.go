@@ -0,0 +1,185 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+	"github.com/bborbe/boltkv/cli"
+)
+
+var _ = Describe("Delete", func() {
+	var ctx context.Context
+	var dataDir string
+	var db boltkv.DB
+	bucketName := libkv.NewBucketName("a")
+	remaining := func() []string {
+		var result []string
+		err := boltkv.ListValues(ctx, db, bucketName, func(key []byte, value []byte) error {
+			result = append(result, string(key))
+			return nil
+		})
+		Expect(err).To(BeNil())
+		return result
+	}
+	BeforeEach(func() {
+		ctx = context.Background()
+		var err error
+		dataDir, err = os.MkdirTemp("", "")
+		Expect(err).To(BeNil())
+		db, err = cli.OpenDB(ctx, dataDir, false)
+		Expect(err).To(BeNil())
+		for _, key := range []string{
+			"2026-01-01T00:00:00Z/a",
+			"2026-02-01T00:00:00Z/b",
+			"2026-03-01T00:00:00Z/c",
+			"user:1",
+			"user:2",
+			"user:3",
+			"user:x",
+		} {
+			Expect(boltkv.SetValue(ctx, db, bucketName, []byte(key), []byte("v"))).To(Succeed())
+		}
+	})
+	AfterEach(func() {
+		_ = db.Close()
+		_ = os.RemoveAll(dataDir)
+	})
+	Context("DeleteValues", func() {
+		var deleted []string
+		deleteValues := func(opts cli.DeleteOptions) int64 {
+			deleted = nil
+			count, err := cli.DeleteValues(ctx, db, bucketName, opts, func(key []byte) error {
+				deleted = append(deleted, string(key))
+				return nil
+			})
+			Expect(err).To(BeNil())
+			return count
+		}
+		It("deletes a prefix in batches", func() {
+			opts := cli.DeleteOptions{ScanRange: boltkv.ScanRange{Prefix: []byte("user:")}, BatchSize: 3}
+			Expect(deleteValues(opts)).To(Equal(int64(4)))
+			Expect(deleted).To(Equal([]string{"user:1", "user:2", "user:3", "user:x"}))
+			Expect(remaining()).To(HaveLen(3))
+		})
+		It("deletes matching keys with a limit", func() {
+			opts := cli.DeleteOptions{
+				Pattern:   regexp.MustCompile(`^user:\d$`),
+				BatchSize: 1,
+			}
+			opts.Limit = 2
+			Expect(deleteValues(opts)).To(Equal(int64(2)))
+			Expect(deleted).To(Equal([]string{"user:1", "user:2"}))
+			Expect(remaining()).To(ContainElements("user:3", "user:x"))
+		})
+		It("deletes a range", func() {
+			opts := cli.DeleteOptions{
+				ScanRange: boltkv.ScanRange{Start: []byte("user:2"), End: []byte("user:x")},
+			}
+			Expect(deleteValues(opts)).To(Equal(int64(2)))
+			Expect(remaining()).To(ContainElements("user:1", "user:x"))
+		})
+		It("only selects keys in dry run", func() {
+			opts := cli.DeleteOptions{
+				ScanRange: boltkv.ScanRange{Prefix: []byte("user:"), Limit: 3},
+				DryRun:    true,
+			}
+			Expect(deleteValues(opts)).To(Equal(int64(3)))
+			Expect(deleted).To(Equal([]string{"user:1", "user:2", "user:3"}))
+			Expect(remaining()).To(HaveLen(7))
+		})
+		It("fails for missing buckets", func() {
+			_, err := cli.DeleteValues(ctx, db, libkv.NewBucketName("missing"), cli.DeleteOptions{}, nil)
+			Expect(errors.Is(err, libkv.BucketNotFoundError)).To(BeTrue())
+		})
+	})
+	Context("Delete", func() {
+		var buf *bytes.Buffer
+		var writer cli.Writer
+		BeforeEach(func() {
+			buf = &bytes.Buffer{}
+			var err error
+			writer, err = cli.NewWriter(ctx, buf, cli.FormatText, boltkv.EncodingUTF8)
+			Expect(err).To(BeNil())
+		})
+		It("deletes keys older than a time", func() {
+			err := cli.Delete(ctx, db, bucketName, cli.DeleteRequest{
+				OlderThan:  "2026-02-15T00:00:00Z",
+				TimeFormat: cli.KeyTimeFormatRFC3339,
+			}, writer)
+			Expect(err).To(BeNil())
+			Expect(writer.Close()).To(Succeed())
+			Expect(buf.String()).To(Equal("2\n"))
+			Expect(remaining()[0]).To(Equal("2026-03-01T00:00:00Z/c"))
+		})
+		It("previews a dry run", func() {
+			err := cli.Delete(ctx, db, bucketName, cli.DeleteRequest{
+				Regex:  "x$",
+				DryRun: true,
+			}, writer)
+			Expect(err).To(BeNil())
+			Expect(writer.Close()).To(Succeed())
+			Expect(buf.String()).To(Equal("user:x\n1\n"))
+			Expect(remaining()).To(HaveLen(7))
+		})
+		It("deletes a single key", func() {
+			Expect(cli.Delete(ctx, db, bucketName, cli.DeleteRequest{Key: "user:1"}, writer)).
+				To(Succeed())
+			Expect(remaining()).To(HaveLen(6))
+		})
+		It("rejects a key with selectors", func() {
+			err := cli.Delete(ctx, db, bucketName, cli.DeleteRequest{Key: "k", Prefix: "u"}, writer)
+			Expect(err).NotTo(BeNil())
+		})
+		It("requires a key or selector", func() {
+			Expect(cli.Delete(ctx, db, bucketName, cli.DeleteRequest{}, writer)).NotTo(Succeed())
+		})
+		It("reports missing buckets instead of creating them", func() {
+			missing := libkv.NewBucketName("missing")
+			err := cli.Delete(ctx, db, missing, cli.DeleteRequest{Key: "k"}, writer)
+			Expect(errors.Is(err, libkv.BucketNotFoundError)).To(BeTrue())
+			var names []string
+			Expect(boltkv.ListBuckets(ctx, db, func(name libkv.BucketName) error {
+				names = append(names, name.String())
+				return nil
+			})).To(Succeed())
+			Expect(names).To(Equal([]string{"a"}))
+		})
+	})
+	DescribeTable("KeyTimeFormat",
+		func(format cli.KeyTimeFormat, expected []byte) {
+			t := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+			result, err := format.Encode(ctx, t)
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal(expected))
+		},
+		Entry("rfc3339", cli.KeyTimeFormatRFC3339, []byte("2026-01-02T03:04:05Z")),
+		Entry("unix", cli.KeyTimeFormatUnix, binary.BigEndian.AppendUint64(nil, 1767323045)),
+		Entry("unixmilli", cli.KeyTimeFormatUnixMilli,
+			binary.BigEndian.AppendUint64(nil, 1767323045000)),
+	)
+	It("parses older-than durations and times", func() {
+		now := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+		t, err := cli.ParseOlderThan(ctx, "24h", now)
+		Expect(err).To(BeNil())
+		Expect(t).To(Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)))
+		t, err = cli.ParseOlderThan(ctx, "2025-12-31T00:00:00Z", now)
+		Expect(err).To(BeNil())
+		Expect(t.Equal(time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC))).To(BeTrue())
+		_, err = cli.ParseOlderThan(ctx, "yesterday", now)
+		Expect(err).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,88 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"context"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+
+	"github.com/bborbe/boltkv"
+)
+
+// ListOptions selects the keys and output of List.
+type ListOptions struct {
+	boltkv.ScanRange
+	// KeysOnly writes keys without values
+	KeysOnly bool
+	// Count writes only the number of keys in the range
+	Count bool
+}
+
+// ParseListOptions returns ListOptions with the given bounds decoded by keyEncoding.
+// Empty bounds are unset.
+func ParseListOptions(
+	ctx context.Context,
+	keyEncoding boltkv.Encoding,
+	prefix string,
+	start string,
+	end string,
+) (ListOptions, error) {
+	var opts ListOptions
+	for _, bound := range []struct {
+		value  string
+		target *[]byte
+	}{
+		{value: prefix, target: &opts.Prefix},
+		{value: start, target: &opts.Start},
+		{value: end, target: &opts.End},
+	} {
+		if bound.value == "" {
+			continue
+		}
+		decoded, err := keyEncoding.Decode(ctx, bound.value)
+		if err != nil {
+			return ListOptions{}, errors.Wrapf(ctx, err, "decode '%s' failed", bound.value)
+		}
+		*bound.target = decoded
+	}
+	return opts, nil
+}
+
+// List streams all keys of the range to writer.
+func List(
+	ctx context.Context,
+	db libkv.DB,
+	bucketName libkv.BucketName,
+	opts ListOptions,
+	writer Writer,
+) error {
+	var count int64
+	err := boltkv.IterateRange(
+		ctx,
+		db,
+		bucketName,
+		opts.ScanRange,
+		func(key []byte, value []byte) error {
+			count++
+			switch {
+			case opts.Count:
+				return nil
+			case opts.KeysOnly:
+				return writer.Key(key)
+			default:
+				return writer.KeyValue(key, value)
+			}
+		},
+	)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "list %s failed", bucketName)
+	}
+	if opts.Count {
+		return writer.Count(count)
+	}
+	return nil
+}
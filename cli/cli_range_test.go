@@ -0,0 +1,73 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+	"github.com/bborbe/boltkv/cli"
+)
+
+var _ = Describe("Range", func() {
+	var ctx context.Context
+	var dataDir string
+	var db boltkv.DB
+	bucketName := libkv.NewBucketName("a")
+	BeforeEach(func() {
+		ctx = context.Background()
+		var err error
+		dataDir, err = os.MkdirTemp("", "")
+		Expect(err).To(BeNil())
+		db, err = cli.OpenDB(ctx, dataDir, false)
+		Expect(err).To(BeNil())
+		for _, key := range []string{"a1", "a2", "a3", "b1", "b2", "c1", "\xff\xff"} {
+			Expect(boltkv.SetValue(ctx, db, bucketName, []byte(key), []byte("v"))).To(Succeed())
+		}
+	})
+	AfterEach(func() {
+		_ = db.Close()
+		_ = os.RemoveAll(dataDir)
+	})
+	Context("List", func() {
+		var buf *bytes.Buffer
+		list := func(opts cli.ListOptions) string {
+			buf.Reset()
+			writer, err := cli.NewWriter(ctx, buf, cli.FormatText, boltkv.EncodingUTF8)
+			Expect(err).To(BeNil())
+			Expect(cli.List(ctx, db, bucketName, opts, writer)).To(Succeed())
+			Expect(writer.Close()).To(Succeed())
+			return buf.String()
+		}
+		BeforeEach(func() {
+			buf = &bytes.Buffer{}
+		})
+		It("writes keys and values", func() {
+			Expect(list(cli.ListOptions{ScanRange: boltkv.ScanRange{Prefix: []byte("b")}})).
+				To(Equal("b1 = v\nb2 = v\n"))
+		})
+		It("writes keys only", func() {
+			Expect(list(cli.ListOptions{ScanRange: boltkv.ScanRange{Prefix: []byte("b")}, KeysOnly: true})).
+				To(Equal("b1\nb2\n"))
+		})
+		It("writes the count", func() {
+			Expect(list(cli.ListOptions{ScanRange: boltkv.ScanRange{Prefix: []byte("a")}, Count: true})).
+				To(Equal("3\n"))
+		})
+		It("parses bounds with the key encoding", func() {
+			opts, err := cli.ParseListOptions(ctx, boltkv.EncodingHex, "61", "", "6232")
+			Expect(err).To(BeNil())
+			Expect(opts.Prefix).To(Equal([]byte("a")))
+			Expect(opts.Start).To(BeNil())
+			Expect(opts.End).To(Equal([]byte("b2")))
+		})
+	})
+})
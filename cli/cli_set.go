@@ -0,0 +1,351 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+
+	"github.com/bborbe/boltkv"
+)
+
+// DefaultBulkBatchSize is the number of pairs SetValues writes per Update.
+const DefaultBulkBatchSize = 1000
+
+// ValueEncodings contains all encodings supported for values.
+var ValueEncodings = []boltkv.Encoding{
+	boltkv.EncodingUTF8,
+	boltkv.EncodingHex,
+	boltkv.EncodingBase64,
+}
+
+// ParseValueEncoding returns the value Encoding for the given name.
+func ParseValueEncoding(ctx context.Context, name string) (boltkv.Encoding, error) {
+	for _, encoding := range ValueEncodings {
+		if string(encoding) == name {
+			return encoding, nil
+		}
+	}
+	return "", errors.Errorf(
+		ctx,
+		"unknown value encoding '%s', expected one of %v",
+		name,
+		ValueEncodings,
+	)
+}
+
+// ValueInput selects the source of a single value.
+// At most one of Value, File and Stdin may be set.
+type ValueInput struct {
+	Value    string
+	File     string
+	Stdin    bool
+	Encoding boltkv.Encoding
+}
+
+// Read returns the decoded value. Surrounding whitespace is ignored for hex and base64.
+func (v ValueInput) Read(ctx context.Context, stdin io.Reader) ([]byte, error) {
+	sources := 0
+	for _, set := range []bool{v.Value != "", v.File != "", v.Stdin} {
+		if set {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return nil, errors.Errorf(ctx, "only one of value, value-file and stdin allowed")
+	}
+	var content []byte
+	var err error
+	switch {
+	case v.File != "":
+		content, err = os.ReadFile(v.File)
+	case v.Stdin:
+		content, err = io.ReadAll(stdin)
+	default:
+		content = []byte(v.Value)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "read value failed")
+	}
+	if v.Encoding == boltkv.EncodingUTF8 || v.Encoding == "" {
+		return content, nil
+	}
+	return v.Encoding.Decode(ctx, string(bytes.TrimSpace(content)))
+}
+
+// PutValue writes the value of key into an existing bucket.
+func PutValue(
+	ctx context.Context,
+	db libkv.DB,
+	bucketName libkv.BucketName,
+	key []byte,
+	value []byte,
+) error {
+	return db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		bucket, err := tx.Bucket(ctx, bucketName)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "get bucket failed")
+		}
+		return bucket.Put(ctx, key, value)
+	})
+}
+
+// BulkFormat is the input format of SetValues.
+type BulkFormat string
+
+const (
+	// BulkFormatTSV reads one key<TAB>value pair per line.
+	BulkFormatTSV BulkFormat = "tsv"
+	// BulkFormatJSONL reads one object per line as written by the jsonl output format.
+	BulkFormatJSONL BulkFormat = "jsonl"
+)
+
+// BulkFormats contains all supported bulk formats.
+var BulkFormats = []BulkFormat{BulkFormatTSV, BulkFormatJSONL}
+
+// ParseBulkFormat returns the BulkFormat for the given name.
+func ParseBulkFormat(ctx context.Context, name string) (BulkFormat, error) {
+	for _, format := range BulkFormats {
+		if string(format) == name {
+			return format, nil
+		}
+	}
+	return "", errors.Errorf(ctx, "unknown bulk format '%s', expected one of %v", name, BulkFormats)
+}
+
+// BulkOptions configures SetValues.
+type BulkOptions struct {
+	Format BulkFormat
+	// KeyEncoding and ValueEncoding decode tsv input,
+	// jsonl records carry their encodings and default to utf8.
+	KeyEncoding   boltkv.Encoding
+	ValueEncoding boltkv.Encoding
+	// BatchSize defaults to DefaultBulkBatchSize.
+	BatchSize int
+	// NoCreateBucket fails if the bucket does not exist instead of creating it.
+	NoCreateBucket bool
+}
+
+type bulkPair struct {
+	key   []byte
+	value []byte
+}
+
+// SetValues reads key value pairs from r and writes them in Updates of BatchSize pairs.
+// It returns the number of written pairs, batches written before an error stay written.
+func SetValues(
+	ctx context.Context,
+	db libkv.DB,
+	bucketName libkv.BucketName,
+	r io.Reader,
+	opts BulkOptions,
+) (int64, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBulkBatchSize
+	}
+	var decode func(line []byte) (bulkPair, error)
+	switch opts.Format {
+	case BulkFormatTSV:
+		decode = func(line []byte) (bulkPair, error) {
+			return decodeTSV(ctx, line, opts.KeyEncoding, opts.ValueEncoding)
+		}
+	case BulkFormatJSONL:
+		decode = func(line []byte) (bulkPair, error) {
+			return decodeJSONL(ctx, line)
+		}
+	default:
+		return 0, errors.Errorf(ctx, "unknown bulk format '%s'", opts.Format)
+	}
+	var written int64
+	batch := make([]bulkPair, 0, opts.BatchSize)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := bytes.TrimSuffix(scanner.Bytes(), []byte("\r"))
+		if len(line) == 0 {
+			continue
+		}
+		pair, err := decode(line)
+		if err != nil {
+			return written, errors.Wrapf(ctx, err, "decode line %d failed", lineNumber)
+		}
+		batch = append(batch, pair)
+		if len(batch) < opts.BatchSize {
+			continue
+		}
+		if err := putBatch(ctx, db, bucketName, batch, opts.NoCreateBucket); err != nil {
+			return written, err
+		}
+		written += int64(len(batch))
+		batch = batch[:0]
+	}
+	if err := scanner.Err(); err != nil {
+		return written, errors.Wrapf(ctx, err, "read input failed")
+	}
+	if err := putBatch(ctx, db, bucketName, batch, opts.NoCreateBucket); err != nil {
+		return written, err
+	}
+	return written + int64(len(batch)), nil
+}
+
+func decodeTSV(
+	ctx context.Context,
+	line []byte,
+	keyEncoding boltkv.Encoding,
+	valueEncoding boltkv.Encoding,
+) (bulkPair, error) {
+	key, value, found := strings.Cut(string(line), "\t")
+	if !found {
+		return bulkPair{}, errors.Errorf(ctx, "missing tab between key and value")
+	}
+	return decodePair(ctx, key, keyEncoding, value, valueEncoding)
+}
+
+func decodeJSONL(ctx context.Context, line []byte) (bulkPair, error) {
+	var kv jsonKeyValue
+	if err := json.Unmarshal(line, &kv); err != nil {
+		return bulkPair{}, errors.Wrapf(ctx, err, "unmarshal failed")
+	}
+	return decodePair(ctx, kv.Key, kv.KeyEncoding, kv.Value, kv.ValueEncoding)
+}
+
+func decodePair(
+	ctx context.Context,
+	key string,
+	keyEncoding boltkv.Encoding,
+	value string,
+	valueEncoding boltkv.Encoding,
+) (bulkPair, error) {
+	decodedKey, err := keyEncoding.Decode(ctx, key)
+	if err != nil {
+		return bulkPair{}, errors.Wrapf(ctx, err, "decode key failed")
+	}
+	decodedValue, err := valueEncoding.Decode(ctx, value)
+	if err != nil {
+		return bulkPair{}, errors.Wrapf(ctx, err, "decode value failed")
+	}
+	return bulkPair{key: decodedKey, value: decodedValue}, nil
+}
+
+func putBatch(
+	ctx context.Context,
+	db libkv.DB,
+	bucketName libkv.BucketName,
+	batch []bulkPair,
+	noCreateBucket bool,
+) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	err := db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		var bucket libkv.Bucket
+		var err error
+		if noCreateBucket {
+			bucket, err = tx.Bucket(ctx, bucketName)
+		} else {
+			bucket, err = tx.CreateBucketIfNotExists(ctx, bucketName)
+		}
+		if err != nil {
+			return errors.Wrapf(ctx, err, "get bucket failed")
+		}
+		for _, pair := range batch {
+			if err := bucket.Put(ctx, pair.key, pair.value); err != nil {
+				return errors.Wrapf(ctx, err, "put key failed")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(ctx, err, "write batch of %d pairs failed", len(batch))
+	}
+	return nil
+}
+
+// SetRequest describes a value set command,
+// a single key with its value or pairs read from stdin if Bulk is set.
+type SetRequest struct {
+	Key            string
+	KeyEncoding    boltkv.Encoding
+	Input          ValueInput
+	Bulk           BulkFormat
+	BatchSize      int
+	NoCreateBucket bool
+}
+
+// Set executes req and returns the number of written keys.
+func Set(
+	ctx context.Context,
+	db libkv.DB,
+	bucketName libkv.BucketName,
+	req SetRequest,
+	stdin io.Reader,
+) (int64, error) {
+	if req.Bulk != "" {
+		if req.Key != "" || req.Input.Value != "" || req.Input.File != "" {
+			return 0, errors.Errorf(ctx, "key, value and value-file are not allowed in bulk mode")
+		}
+		return SetValues(ctx, db, bucketName, stdin, BulkOptions{
+			Format:         req.Bulk,
+			KeyEncoding:    req.KeyEncoding,
+			ValueEncoding:  req.Input.Encoding,
+			BatchSize:      req.BatchSize,
+			NoCreateBucket: req.NoCreateBucket,
+		})
+	}
+	if req.Key == "" {
+		return 0, errors.Errorf(ctx, "key is required without bulk mode")
+	}
+	key, err := req.KeyEncoding.Decode(ctx, req.Key)
+	if err != nil {
+		return 0, errors.Wrapf(ctx, err, "decode key failed")
+	}
+	value, err := req.Input.Read(ctx, stdin)
+	if err != nil {
+		return 0, err
+	}
+	if req.NoCreateBucket {
+		err = PutValue(ctx, db, bucketName, key, value)
+	} else {
+		err = boltkv.SetValue(ctx, db, bucketName, key, value)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// ParseSetRequest returns a SetRequest with the encodings and bulk format parsed.
+// An empty bulk name selects single key mode.
+func ParseSetRequest(
+	ctx context.Context,
+	key string,
+	keyEncoding boltkv.Encoding,
+	valueEncodingName string,
+	bulkName string,
+) (SetRequest, error) {
+	valueEncoding, err := ParseValueEncoding(ctx, valueEncodingName)
+	if err != nil {
+		return SetRequest{}, err
+	}
+	req := SetRequest{
+		Key:         key,
+		KeyEncoding: keyEncoding,
+		Input:       ValueInput{Encoding: valueEncoding},
+	}
+	if bulkName != "" {
+		if req.Bulk, err = ParseBulkFormat(ctx, bulkName); err != nil {
+			return SetRequest{}, err
+		}
+	}
+	return req, nil
+}
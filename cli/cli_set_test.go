@@ -0,0 +1,148 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+	"github.com/bborbe/boltkv/cli"
+)
+
+var _ = Describe("Set", func() {
+	var ctx context.Context
+	var dataDir string
+	var db boltkv.DB
+	bucketName := libkv.NewBucketName("a")
+	get := func(key string) []byte {
+		value, err := boltkv.GetValue(ctx, db, bucketName, []byte(key))
+		Expect(err).To(BeNil())
+		return value
+	}
+	BeforeEach(func() {
+		ctx = context.Background()
+		var err error
+		dataDir, err = os.MkdirTemp("", "")
+		Expect(err).To(BeNil())
+		db, err = cli.OpenDB(ctx, dataDir, false)
+		Expect(err).To(BeNil())
+	})
+	AfterEach(func() {
+		_ = db.Close()
+		_ = os.RemoveAll(dataDir)
+	})
+	Context("ValueInput", func() {
+		It("reads the value", func() {
+			value, err := cli.ValueInput{Value: "v"}.Read(ctx, nil)
+			Expect(err).To(BeNil())
+			Expect(value).To(Equal([]byte("v")))
+		})
+		It("reads a file unchanged", func() {
+			path := filepath.Join(dataDir, "value")
+			Expect(os.WriteFile(path, []byte{0, 1, '\n'}, 0600)).To(Succeed())
+			value, err := cli.ValueInput{File: path}.Read(ctx, nil)
+			Expect(err).To(BeNil())
+			Expect(value).To(Equal([]byte{0, 1, '\n'}))
+		})
+		It("decodes hex from stdin", func() {
+			input := cli.ValueInput{Stdin: true, Encoding: boltkv.EncodingHex}
+			value, err := input.Read(ctx, strings.NewReader("ff00\n"))
+			Expect(err).To(BeNil())
+			Expect(value).To(Equal([]byte{0xff, 0}))
+		})
+		It("rejects multiple sources", func() {
+			_, err := cli.ValueInput{Value: "v", Stdin: true}.Read(ctx, strings.NewReader(""))
+			Expect(err).NotTo(BeNil())
+		})
+	})
+	Context("SetValues", func() {
+		It("writes tsv pairs in batches", func() {
+			input := "k1\tv1\nk2\tv\t2\n\nk3\tv3\r\n"
+			written, err := cli.SetValues(ctx, db, bucketName, strings.NewReader(input), cli.BulkOptions{
+				Format:    cli.BulkFormatTSV,
+				BatchSize: 2,
+			})
+			Expect(err).To(BeNil())
+			Expect(written).To(Equal(int64(3)))
+			Expect(get("k1")).To(Equal([]byte("v1")))
+			Expect(get("k2")).To(Equal([]byte("v\t2")))
+			Expect(get("k3")).To(Equal([]byte("v3")))
+		})
+		It("decodes tsv with encodings", func() {
+			_, err := cli.SetValues(ctx, db, bucketName, strings.NewReader("6b\t/w==\n"), cli.BulkOptions{
+				Format:        cli.BulkFormatTSV,
+				KeyEncoding:   boltkv.EncodingHex,
+				ValueEncoding: boltkv.EncodingBase64,
+			})
+			Expect(err).To(BeNil())
+			Expect(get("k")).To(Equal([]byte{0xff}))
+		})
+		It("writes jsonl as printed by list", func() {
+			input := `{"key":"k1","value":"v1"}` + "\n" +
+				`{"key":"256","value":"/w==","key_encoding":"uint64","value_encoding":"base64"}` + "\n"
+			written, err := cli.SetValues(ctx, db, bucketName, strings.NewReader(input), cli.BulkOptions{
+				Format: cli.BulkFormatJSONL,
+			})
+			Expect(err).To(BeNil())
+			Expect(written).To(Equal(int64(2)))
+			Expect(get("k1")).To(Equal([]byte("v1")))
+			Expect(get("\x00\x00\x00\x00\x00\x00\x01\x00")).To(Equal([]byte{0xff}))
+		})
+		It("keeps written batches on errors", func() {
+			input := "k1\tv1\nbroken\n"
+			written, err := cli.SetValues(ctx, db, bucketName, strings.NewReader(input), cli.BulkOptions{
+				Format:    cli.BulkFormatTSV,
+				BatchSize: 1,
+			})
+			Expect(err).To(MatchError(ContainSubstring("decode line 2 failed")))
+			Expect(written).To(Equal(int64(1)))
+			Expect(get("k1")).To(Equal([]byte("v1")))
+		})
+		It("fails for missing buckets with NoCreateBucket", func() {
+			_, err := cli.SetValues(ctx, db, bucketName, strings.NewReader("k\tv\n"), cli.BulkOptions{
+				Format:         cli.BulkFormatTSV,
+				NoCreateBucket: true,
+			})
+			Expect(errors.Is(err, libkv.BucketNotFoundError)).To(BeTrue())
+		})
+	})
+	Context("Set", func() {
+		It("writes a single key", func() {
+			req, err := cli.ParseSetRequest(ctx, "256", boltkv.EncodingUint64, "hex", "")
+			Expect(err).To(BeNil())
+			req.Input.Value = "ff"
+			written, err := cli.Set(ctx, db, bucketName, req, nil)
+			Expect(err).To(BeNil())
+			Expect(written).To(Equal(int64(1)))
+			Expect(get("\x00\x00\x00\x00\x00\x00\x01\x00")).To(Equal([]byte{0xff}))
+		})
+		It("fails for missing buckets with NoCreateBucket", func() {
+			_, err := cli.Set(ctx, db, bucketName, cli.SetRequest{
+				Key:            "k",
+				NoCreateBucket: true,
+			}, nil)
+			Expect(errors.Is(err, libkv.BucketNotFoundError)).To(BeTrue())
+		})
+		It("rejects a key in bulk mode", func() {
+			_, err := cli.Set(ctx, db, bucketName, cli.SetRequest{
+				Key:  "k",
+				Bulk: cli.BulkFormatTSV,
+			}, strings.NewReader(""))
+			Expect(err).NotTo(BeNil())
+		})
+		It("requires a key without bulk mode", func() {
+			_, err := cli.Set(ctx, db, bucketName, cli.SetRequest{}, nil)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+})
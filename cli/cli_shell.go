@@ -0,0 +1,791 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/term"
+
+	"github.com/bborbe/boltkv"
+)
+
+// DefaultShellLimit is the number of entries ls and seek print if no limit is given.
+const DefaultShellLimit = 100
+
+// maxCompletions limits the keys read for tab completion.
+const maxCompletions = 100
+
+// ShellExitError is returned by Shell.Execute for exit and quit.
+var ShellExitError = errors.New(context.Background(), "exit")
+
+var shellHelp = `commands:
+  cd <bucket>[/<bucket>...]  change into a (nested) bucket, .. goes up, / to the root
+  pwd                        print the current bucket
+  ls [prefix] [limit]        list buckets and keys
+  get <key>                  print the value of a key
+  put <key> <value>          write a key
+  del <key>                  delete a key
+  seek <key> [limit]         list keys starting at key
+  count [prefix]             count keys
+  stats                      print bucket statistics
+  begin                      start batching writes
+  commit                     write all batched writes in one transaction
+  rollback                   discard all batched writes
+  help                       print this help
+  exit                       leave the shell
+keys and values containing spaces can be quoted with "", Go escapes are supported
+`
+
+var shellCommands = []string{
+	"begin", "cd", "commit", "count", "del", "exit", "get", "help",
+	"ls", "put", "pwd", "quit", "rollback", "seek", "stats",
+}
+
+type shellWrite struct {
+	bucketPath [][]byte
+	key        []byte
+	value      []byte
+	delete     bool
+}
+
+// Shell is an interactive session on a database.
+// Reads run in their own View, writes in their own Update unless batched
+// between begin and commit. Batched writes are only visible to get before commit.
+type Shell struct {
+	db         boltkv.DB
+	out        io.Writer
+	bucketPath [][]byte
+	batch      []shellWrite
+	batching   bool
+}
+
+// NewShell returns a Shell writing its output to out.
+func NewShell(db boltkv.DB, out io.Writer) *Shell {
+	return &Shell{
+		db:  db,
+		out: out,
+	}
+}
+
+// Prompt returns the prompt for the current bucket.
+func (s *Shell) Prompt() string {
+	marker := "> "
+	if s.batching {
+		marker = fmt.Sprintf("[%d]> ", len(s.batch))
+	}
+	return s.pwd() + " " + marker
+}
+
+func (s *Shell) pwd() string {
+	parts := make([]string, 0, len(s.bucketPath))
+	for _, name := range s.bucketPath {
+		parts = append(parts, display(name))
+	}
+	return "/" + strings.Join(parts, "/")
+}
+
+// Run reads commands from in until EOF or exit.
+// If in is a terminal, line editing, history and tab completion are enabled.
+func (s *Shell) Run(ctx context.Context, in io.Reader) error {
+	if file, ok := in.(*os.File); ok && term.IsTerminal(int(file.Fd())) {
+		return s.runTerminal(ctx, file)
+	}
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		if err := s.executeAndReport(ctx, scanner.Text()); err != nil {
+			return s.exit(err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Wrapf(ctx, err, "read input failed")
+	}
+	return s.exit(nil)
+}
+
+func (s *Shell) runTerminal(ctx context.Context, file *os.File) error {
+	state, err := term.MakeRaw(int(file.Fd()))
+	if err != nil {
+		return errors.Wrapf(ctx, err, "make terminal raw failed")
+	}
+	defer func() {
+		_ = term.Restore(int(file.Fd()), state)
+	}()
+	terminal := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{file, s.out}, s.Prompt())
+	s.out = terminal
+	terminal.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+		newLine, candidates := s.Complete(ctx, line[:pos])
+		if len(candidates) > 1 {
+			fmt.Fprintln(terminal, strings.Join(candidates, "  "))
+		}
+		return newLine + line[pos:], len(newLine), true
+	}
+	for {
+		line, err := terminal.ReadLine()
+		if errors.Is(err, io.EOF) {
+			return s.exit(nil)
+		}
+		if err != nil {
+			return errors.Wrapf(ctx, err, "read line failed")
+		}
+		if err := s.executeAndReport(ctx, line); err != nil {
+			return s.exit(err)
+		}
+		terminal.SetPrompt(s.Prompt())
+	}
+}
+
+// executeAndReport prints command errors and only returns ShellExitError.
+func (s *Shell) executeAndReport(ctx context.Context, line string) error {
+	err := s.Execute(ctx, line)
+	if errors.Is(err, ShellExitError) {
+		return err
+	}
+	if err != nil {
+		fmt.Fprintf(s.out, "error: %v\n", err)
+	}
+	return nil
+}
+
+func (s *Shell) exit(err error) error {
+	if s.batching && len(s.batch) > 0 {
+		fmt.Fprintf(s.out, "discarded %d uncommitted writes\n", len(s.batch))
+	}
+	if err == nil || errors.Is(err, ShellExitError) {
+		return nil
+	}
+	return err
+}
+
+// Execute runs a single command line.
+func (s *Shell) Execute(ctx context.Context, line string) error {
+	args, err := splitArgs(ctx, line)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "parse line failed")
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	switch string(args[0]) {
+	case "help":
+		_, err := io.WriteString(s.out, shellHelp)
+		return err
+	case "exit", "quit":
+		return ShellExitError
+	case "pwd":
+		_, err := fmt.Fprintln(s.out, s.pwd())
+		return err
+	case "cd":
+		return s.cd(ctx, args[1:])
+	case "ls":
+		return s.ls(ctx, args[1:])
+	case "get":
+		return s.get(ctx, args[1:])
+	case "put":
+		return s.put(ctx, args[1:])
+	case "del":
+		return s.del(ctx, args[1:])
+	case "seek":
+		return s.seek(ctx, args[1:])
+	case "count":
+		return s.count(ctx, args[1:])
+	case "stats":
+		return s.stats(ctx)
+	case "begin":
+		return s.begin(ctx)
+	case "commit":
+		return s.commit(ctx)
+	case "rollback":
+		return s.rollback(ctx)
+	default:
+		return errors.Errorf(ctx, "unknown command '%s', try help", args[0])
+	}
+}
+
+func (s *Shell) cd(ctx context.Context, args [][]byte) error {
+	if len(args) != 1 {
+		return errors.Errorf(ctx, "usage: cd <bucket>")
+	}
+	target := string(args[0])
+	bucketPath := append([][]byte{}, s.bucketPath...)
+	if strings.HasPrefix(target, "/") {
+		bucketPath = nil
+	}
+	for _, part := range strings.Split(target, "/") {
+		switch part {
+		case "", ".":
+		case "..":
+			if len(bucketPath) > 0 {
+				bucketPath = bucketPath[:len(bucketPath)-1]
+			}
+		default:
+			bucketPath = append(bucketPath, []byte(part))
+		}
+	}
+	err := s.view(ctx, func(tx *bolt.Tx) error {
+		if len(bucketPath) == 0 {
+			return nil
+		}
+		_, err := lookupBucket(ctx, tx, bucketPath)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	s.bucketPath = bucketPath
+	return nil
+}
+
+func (s *Shell) ls(ctx context.Context, args [][]byte) error {
+	var prefix []byte
+	if len(args) > 0 {
+		prefix = args[0]
+	}
+	limit, err := parseLimit(ctx, args, 1)
+	if err != nil {
+		return err
+	}
+	return s.list(ctx, prefix, limit, func(key []byte) bool {
+		return bytes.HasPrefix(key, prefix)
+	})
+}
+
+func (s *Shell) seek(ctx context.Context, args [][]byte) error {
+	if len(args) == 0 {
+		return errors.Errorf(ctx, "usage: seek <key> [limit]")
+	}
+	limit, err := parseLimit(ctx, args, 1)
+	if err != nil {
+		return err
+	}
+	return s.list(ctx, args[0], limit, func(key []byte) bool {
+		return true
+	})
+}
+
+// list prints up to limit keys starting at start while match returns true.
+func (s *Shell) list(
+	ctx context.Context,
+	start []byte,
+	limit int,
+	match func(key []byte) bool,
+) error {
+	return s.view(ctx, func(tx *bolt.Tx) error {
+		cursor, err := s.cursor(ctx, tx)
+		if err != nil {
+			return err
+		}
+		printed := 0
+		for key, value := seek(cursor, start); key != nil && match(key); key, value = cursor.Next() {
+			if isInternal(s.bucketPath, key) {
+				continue
+			}
+			if printed == limit {
+				_, err := fmt.Fprintf(s.out, "... (limit %d reached)\n", limit)
+				return err
+			}
+			printed++
+			if value == nil {
+				fmt.Fprintf(s.out, "%s/\n", display(key))
+				continue
+			}
+			fmt.Fprintf(s.out, "%s = %s\n", display(key), display(value))
+		}
+		return nil
+	})
+}
+
+func (s *Shell) count(ctx context.Context, args [][]byte) error {
+	var prefix []byte
+	if len(args) > 0 {
+		prefix = args[0]
+	}
+	return s.view(ctx, func(tx *bolt.Tx) error {
+		cursor, err := s.cursor(ctx, tx)
+		if err != nil {
+			return err
+		}
+		var count int64
+		for key, _ := seek(cursor, prefix); key != nil; key, _ = cursor.Next() {
+			if !bytes.HasPrefix(key, prefix) {
+				break
+			}
+			if isInternal(s.bucketPath, key) {
+				continue
+			}
+			count++
+		}
+		_, err = fmt.Fprintln(s.out, count)
+		return err
+	})
+}
+
+func (s *Shell) get(ctx context.Context, args [][]byte) error {
+	if len(args) != 1 {
+		return errors.Errorf(ctx, "usage: get <key>")
+	}
+	if err := s.requireBucket(ctx); err != nil {
+		return err
+	}
+	key := args[0]
+	for i := len(s.batch) - 1; i >= 0; i-- {
+		write := s.batch[i]
+		if !equalPath(write.bucketPath, s.bucketPath) || !bytes.Equal(write.key, key) {
+			continue
+		}
+		if write.delete {
+			return errors.Wrapf(ctx, libkv.KeyNotFoundError, "key %s deleted in batch", display(key))
+		}
+		_, err := fmt.Fprintf(s.out, "%s (uncommitted)\n", display(write.value))
+		return err
+	}
+	return s.view(ctx, func(tx *bolt.Tx) error {
+		bucket, err := lookupBucket(ctx, tx, s.bucketPath)
+		if err != nil {
+			return err
+		}
+		value := bucket.Get(key)
+		if value == nil {
+			return errors.Wrapf(ctx, libkv.KeyNotFoundError, "key %s not found", display(key))
+		}
+		_, err = fmt.Fprintln(s.out, display(value))
+		return err
+	})
+}
+
+func (s *Shell) put(ctx context.Context, args [][]byte) error {
+	if len(args) != 2 {
+		return errors.Errorf(ctx, "usage: put <key> <value>")
+	}
+	return s.write(ctx, shellWrite{key: args[0], value: args[1]})
+}
+
+func (s *Shell) del(ctx context.Context, args [][]byte) error {
+	if len(args) != 1 {
+		return errors.Errorf(ctx, "usage: del <key>")
+	}
+	return s.write(ctx, shellWrite{key: args[0], delete: true})
+}
+
+func (s *Shell) write(ctx context.Context, write shellWrite) error {
+	if err := s.requireBucket(ctx); err != nil {
+		return err
+	}
+	write.bucketPath = append([][]byte{}, s.bucketPath...)
+	if s.batching {
+		s.batch = append(s.batch, write)
+		return nil
+	}
+	return s.apply(ctx, []shellWrite{write})
+}
+
+func (s *Shell) begin(ctx context.Context) error {
+	if s.batching {
+		return errors.Errorf(ctx, "batch already started")
+	}
+	s.batching = true
+	return nil
+}
+
+func (s *Shell) commit(ctx context.Context) error {
+	if !s.batching {
+		return errors.Errorf(ctx, "no batch started, use begin")
+	}
+	if err := s.apply(ctx, s.batch); err != nil {
+		return errors.Wrapf(ctx, err, "commit failed, batch kept")
+	}
+	_, err := fmt.Fprintf(s.out, "committed %d writes\n", len(s.batch))
+	s.batch = nil
+	s.batching = false
+	return err
+}
+
+func (s *Shell) rollback(ctx context.Context) error {
+	if !s.batching {
+		return errors.Errorf(ctx, "no batch started, use begin")
+	}
+	_, err := fmt.Fprintf(s.out, "discarded %d writes\n", len(s.batch))
+	s.batch = nil
+	s.batching = false
+	return err
+}
+
+// apply writes all writes in one Update.
+func (s *Shell) apply(ctx context.Context, writes []shellWrite) error {
+	return s.db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		for _, write := range writes {
+			if err := applyWrite(ctx, tx, write); err != nil {
+				return errors.Wrapf(ctx, err, "write %s failed", display(write.key))
+			}
+		}
+		return nil
+	})
+}
+
+// applyWrite writes to top-level buckets through libkv, so hooks, fingerprints
+// and the replication log see it. libkv has no nested buckets, they are
+// written through the raw bolt.Tx.
+func applyWrite(ctx context.Context, tx libkv.Tx, write shellWrite) error {
+	if len(write.bucketPath) == 1 {
+		bucket, err := tx.Bucket(ctx, write.bucketPath[0])
+		if err != nil {
+			return err
+		}
+		if write.delete {
+			return bucket.Delete(ctx, write.key)
+		}
+		return bucket.Put(ctx, write.key, write.value)
+	}
+	boltTx, err := toBoltTx(ctx, tx)
+	if err != nil {
+		return err
+	}
+	bucket, err := lookupBucket(ctx, boltTx, write.bucketPath)
+	if err != nil {
+		return err
+	}
+	if write.delete {
+		return bucket.Delete(write.key)
+	}
+	return bucket.Put(write.key, write.value)
+}
+
+func (s *Shell) stats(ctx context.Context) error {
+	if len(s.bucketPath) == 0 {
+		stats, err := boltkv.BucketStats(ctx, s.db)
+		if err != nil {
+			return err
+		}
+		for _, stat := range stats {
+			fmt.Fprintf(
+				s.out,
+				"%s\tkeys=%d\tsize=%d\n",
+				display(stat.Name.Bytes()),
+				stat.KeyCount,
+				stat.SizeB,
+			)
+		}
+		return nil
+	}
+	return s.view(ctx, func(tx *bolt.Tx) error {
+		bucket, err := lookupBucket(ctx, tx, s.bucketPath)
+		if err != nil {
+			return err
+		}
+		stats := bucket.Stats()
+		_, err = fmt.Fprintf(
+			s.out,
+			"keys=%d buckets=%d depth=%d leaf-pages=%d branch-pages=%d inuse=%d\n",
+			stats.KeyN,
+			stats.BucketN,
+			stats.Depth,
+			stats.LeafPageN,
+			stats.BranchPageN,
+			stats.LeafInuse+stats.BranchInuse+stats.InlineBucketInuse,
+		)
+		return err
+	})
+}
+
+// Complete completes the last word of line.
+// It returns the completed line and all candidates for the word.
+func (s *Shell) Complete(ctx context.Context, line string) (string, []string) {
+	words := strings.Fields(line)
+	if len(words) == 0 || (len(words) == 1 && !strings.HasSuffix(line, " ")) {
+		var word string
+		if len(words) == 1 {
+			word = words[0]
+		}
+		return completeWord(line, word, filterPrefix(shellCommands, word))
+	}
+	var word string
+	if !strings.HasSuffix(line, " ") {
+		word = words[len(words)-1]
+	}
+	switch words[0] {
+	case "cd":
+		return s.completeBucket(ctx, line, word)
+	case "ls", "get", "put", "del", "seek", "count":
+		if len(words) > 2 || (len(words) == 2 && word == "") {
+			return line, nil
+		}
+		candidates := s.keys(ctx, s.bucketPath, []byte(word), false)
+		return completeWord(line, word, candidates)
+	default:
+		return line, nil
+	}
+}
+
+func (s *Shell) completeBucket(ctx context.Context, line string, word string) (string, []string) {
+	bucketPath := append([][]byte{}, s.bucketPath...)
+	dir, base := "", word
+	if i := strings.LastIndex(word, "/"); i >= 0 {
+		dir, base = word[:i+1], word[i+1:]
+		if strings.HasPrefix(word, "/") {
+			bucketPath = nil
+		}
+		for _, part := range strings.Split(strings.Trim(dir, "/"), "/") {
+			switch part {
+			case "", ".":
+			case "..":
+				if len(bucketPath) > 0 {
+					bucketPath = bucketPath[:len(bucketPath)-1]
+				}
+			default:
+				bucketPath = append(bucketPath, []byte(part))
+			}
+		}
+	}
+	candidates := s.keys(ctx, bucketPath, []byte(base), true)
+	for i, candidate := range candidates {
+		candidates[i] = dir + candidate + "/"
+	}
+	return completeWord(line, word, candidates)
+}
+
+// keys returns up to maxCompletions keys or bucket names starting with prefix.
+func (s *Shell) keys(
+	ctx context.Context,
+	bucketPath [][]byte,
+	prefix []byte,
+	bucketsOnly bool,
+) []string {
+	var result []string
+	_ = s.view(ctx, func(tx *bolt.Tx) error {
+		var cursor *bolt.Cursor
+		if len(bucketPath) == 0 {
+			cursor = tx.Cursor()
+		} else {
+			bucket, err := lookupBucket(ctx, tx, bucketPath)
+			if err != nil {
+				return err
+			}
+			cursor = bucket.Cursor()
+		}
+		for key, value := seek(cursor, prefix); key != nil; key, value = cursor.Next() {
+			if !bytes.HasPrefix(key, prefix) {
+				break
+			}
+			if bucketsOnly && value != nil {
+				continue
+			}
+			if !isPlain(key) || isInternal(bucketPath, key) {
+				continue
+			}
+			result = append(result, string(key))
+			if len(result) == maxCompletions {
+				break
+			}
+		}
+		return nil
+	})
+	return result
+}
+
+func (s *Shell) requireBucket(ctx context.Context) error {
+	if len(s.bucketPath) == 0 {
+		return errors.Errorf(ctx, "not in a bucket, use cd <bucket>")
+	}
+	return nil
+}
+
+// isInternal reports whether key is an internal bucket of boltkv at the root,
+// hidden like by ListBucketNames.
+func isInternal(bucketPath [][]byte, key []byte) bool {
+	return len(bucketPath) == 0 && boltkv.IsInternalBucket(key)
+}
+
+func (s *Shell) cursor(ctx context.Context, tx *bolt.Tx) (*bolt.Cursor, error) {
+	if len(s.bucketPath) == 0 {
+		return tx.Cursor(), nil
+	}
+	bucket, err := lookupBucket(ctx, tx, s.bucketPath)
+	if err != nil {
+		return nil, err
+	}
+	return bucket.Cursor(), nil
+}
+
+func (s *Shell) view(ctx context.Context, fn func(tx *bolt.Tx) error) error {
+	return s.db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		boltTx, err := toBoltTx(ctx, tx)
+		if err != nil {
+			return err
+		}
+		return fn(boltTx)
+	})
+}
+
+func toBoltTx(ctx context.Context, tx libkv.Tx) (*bolt.Tx, error) {
+	boltTx, ok := tx.(boltkv.Tx)
+	if !ok {
+		return nil, errors.Errorf(ctx, "tx is not a bolt transaction")
+	}
+	return boltTx.Tx(), nil
+}
+
+func lookupBucket(ctx context.Context, tx *bolt.Tx, bucketPath [][]byte) (*bolt.Bucket, error) {
+	bucket := tx.Bucket(bucketPath[0])
+	for _, name := range bucketPath[1:] {
+		if bucket == nil {
+			break
+		}
+		bucket = bucket.Bucket(name)
+	}
+	if bucket == nil {
+		return nil, errors.Wrapf(
+			ctx,
+			libkv.BucketNotFoundError,
+			"bucket %s not found",
+			displayPath(bucketPath),
+		)
+	}
+	return bucket, nil
+}
+
+func seek(cursor *bolt.Cursor, key []byte) ([]byte, []byte) {
+	if len(key) == 0 {
+		return cursor.First()
+	}
+	return cursor.Seek(key)
+}
+
+func parseLimit(ctx context.Context, args [][]byte, index int) (int, error) {
+	if len(args) <= index {
+		return DefaultShellLimit, nil
+	}
+	limit, err := strconv.Atoi(string(args[index]))
+	if err != nil || limit <= 0 {
+		return 0, errors.Errorf(ctx, "invalid limit '%s'", args[index])
+	}
+	return limit, nil
+}
+
+// splitArgs splits line at whitespace, double quoted parts are unquoted with Go escapes.
+func splitArgs(ctx context.Context, line string) ([][]byte, error) {
+	var result [][]byte
+	line = strings.TrimSpace(line)
+	for line != "" {
+		var arg string
+		if line[0] == '"' {
+			end := 1
+			for ; end < len(line); end++ {
+				if line[end] == '\\' {
+					end++
+					continue
+				}
+				if line[end] == '"' {
+					break
+				}
+			}
+			if end >= len(line) {
+				return nil, errors.Errorf(ctx, "unterminated quote")
+			}
+			unquoted, err := strconv.Unquote(line[:end+1])
+			if err != nil {
+				return nil, errors.Wrapf(ctx, err, "unquote %s failed", line[:end+1])
+			}
+			arg, line = unquoted, line[end+1:]
+		} else {
+			end := strings.IndexFunc(line, unicode.IsSpace)
+			if end < 0 {
+				end = len(line)
+			}
+			arg, line = line[:end], line[end:]
+		}
+		result = append(result, []byte(arg))
+		line = strings.TrimLeftFunc(line, unicode.IsSpace)
+	}
+	return result, nil
+}
+
+func completeWord(line string, word string, candidates []string) (string, []string) {
+	if len(candidates) == 0 {
+		return line, nil
+	}
+	common := candidates[0]
+	for _, candidate := range candidates[1:] {
+		for !strings.HasPrefix(candidate, common) {
+			common = common[:len(common)-1]
+		}
+	}
+	completed := strings.TrimSuffix(line, word) + common
+	if len(candidates) == 1 && !strings.HasSuffix(common, "/") {
+		completed += " "
+	}
+	return completed, candidates
+}
+
+func filterPrefix(values []string, prefix string) []string {
+	var result []string
+	for _, value := range values {
+		if strings.HasPrefix(value, prefix) {
+			result = append(result, value)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func equalPath(a [][]byte, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isPlain returns true if value can be printed and typed without quoting.
+func isPlain(value []byte) bool {
+	if !utf8.Valid(value) {
+		return false
+	}
+	for _, r := range string(value) {
+		if !unicode.IsPrint(r) || unicode.IsSpace(r) || r == '"' {
+			return false
+		}
+	}
+	return true
+}
+
+// display returns value as is if it is plain, quoted otherwise.
+func display(value []byte) string {
+	if len(value) > 0 && isPlain(value) {
+		return string(value)
+	}
+	return strconv.Quote(string(value))
+}
+
+func displayPath(bucketPath [][]byte) string {
+	parts := make([]string, 0, len(bucketPath))
+	for _, name := range bucketPath {
+		parts = append(parts, display(name))
+	}
+	return strings.Join(parts, "/")
+}
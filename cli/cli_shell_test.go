@@ -0,0 +1,176 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bborbe/boltkv"
+	"github.com/bborbe/boltkv/cli"
+)
+
+var _ = Describe("Shell", func() {
+	var ctx context.Context
+	var dataDir string
+	var db boltkv.DB
+	var out *bytes.Buffer
+	var shell *cli.Shell
+	execute := func(line string) (string, error) {
+		out.Reset()
+		err := shell.Execute(ctx, line)
+		return out.String(), err
+	}
+	BeforeEach(func() {
+		ctx = context.Background()
+		var err error
+		dataDir, err = os.MkdirTemp("", "")
+		Expect(err).To(BeNil())
+		db, err = cli.OpenDB(ctx, dataDir, false)
+		Expect(err).To(BeNil())
+		err = db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.(boltkv.Tx).Tx().CreateBucket([]byte("a"))
+			if err != nil {
+				return err
+			}
+			nested, err := bucket.CreateBucket([]byte("nested"))
+			if err != nil {
+				return err
+			}
+			for _, kv := range [][2]string{{"k1", "v1"}, {"k2", "v2"}, {"x1", "v3"}} {
+				if err := bucket.Put([]byte(kv[0]), []byte(kv[1])); err != nil {
+					return err
+				}
+			}
+			return nested.Put([]byte("n1"), []byte("deep"))
+		})
+		Expect(err).To(BeNil())
+		out = &bytes.Buffer{}
+		shell = cli.NewShell(db, out)
+	})
+	AfterEach(func() {
+		_ = db.Close()
+		_ = os.RemoveAll(dataDir)
+	})
+	It("lists root buckets", func() {
+		Expect(execute("ls")).To(Equal("a/\n"))
+	})
+	It("hides internal buckets at the root", func() {
+		Expect(db.DB().Update(func(tx *bolt.Tx) error {
+			_, err := tx.CreateBucket([]byte(boltkv.InternalBucketPrefix + "copy"))
+			return err
+		})).To(Succeed())
+		Expect(execute("ls")).To(Equal("a/\n"))
+		Expect(execute("count")).To(Equal("1\n"))
+		line, _ := shell.Complete(ctx, "cd ")
+		Expect(line).To(Equal("cd a/"))
+	})
+	It("changes into nested buckets", func() {
+		Expect(execute("cd a/nested")).To(Equal(""))
+		Expect(shell.Prompt()).To(Equal("/a/nested > "))
+		Expect(execute("get n1")).To(Equal("deep\n"))
+		Expect(execute("cd ..")).To(Equal(""))
+		Expect(execute("pwd")).To(Equal("/a\n"))
+		Expect(execute("cd /")).To(Equal(""))
+		Expect(execute("pwd")).To(Equal("/\n"))
+	})
+	It("fails to change into missing buckets", func() {
+		_, err := execute("cd banana")
+		Expect(err).To(MatchError(ContainSubstring("bucket banana not found")))
+		Expect(execute("pwd")).To(Equal("/\n"))
+	})
+	It("lists keys with prefix and limit", func() {
+		Expect(execute("cd a")).To(Equal(""))
+		Expect(execute("ls")).To(Equal("k1 = v1\nk2 = v2\nnested/\nx1 = v3\n"))
+		Expect(execute("ls k")).To(Equal("k1 = v1\nk2 = v2\n"))
+		Expect(execute("ls k 1")).To(Equal("k1 = v1\n... (limit 1 reached)\n"))
+	})
+	It("seeks and counts", func() {
+		Expect(execute("cd a")).To(Equal(""))
+		Expect(execute("seek k2 2")).To(Equal("k2 = v2\nnested/\n... (limit 2 reached)\n"))
+		Expect(execute("count")).To(Equal("4\n"))
+		Expect(execute("count k")).To(Equal("2\n"))
+	})
+	It("puts and deletes keys", func() {
+		Expect(execute("cd a")).To(Equal(""))
+		Expect(execute(`put "hello world" "a\tb"`)).To(Equal(""))
+		Expect(execute(`get "hello world"`)).To(Equal("\"a\\tb\"\n"))
+		Expect(execute(`del "hello world"`)).To(Equal(""))
+		_, err := execute(`get "hello world"`)
+		Expect(err).To(MatchError(ContainSubstring("not found")))
+	})
+	It("writes top-level buckets through libkv", func() {
+		var puts []string
+		shell = cli.NewShell(boltkv.NewDB(db.DB(), boltkv.WithHook(boltkv.HookFuncs{
+			EndFunc: func(ctx context.Context, op boltkv.Operation, name libkv.BucketName, err error) {
+				if op == boltkv.OperationPut || op == boltkv.OperationDelete {
+					puts = append(puts, string(op)+" "+name.String())
+				}
+			},
+		})), out)
+		Expect(execute("cd a")).To(Equal(""))
+		Expect(execute("put k3 v3")).To(Equal(""))
+		Expect(execute("del k1")).To(Equal(""))
+		Expect(execute("cd nested")).To(Equal(""))
+		Expect(execute("put n2 v")).To(Equal(""))
+		Expect(execute("get n2")).To(Equal("v\n"))
+		Expect(puts).To(Equal([]string{"Put a", "Delete a"}))
+	})
+	It("requires a bucket for writes", func() {
+		_, err := execute("put k v")
+		Expect(err).To(MatchError(ContainSubstring("not in a bucket")))
+	})
+	It("batches writes until commit", func() {
+		Expect(execute("cd a")).To(Equal(""))
+		Expect(execute("begin")).To(Equal(""))
+		Expect(execute("put k3 v3")).To(Equal(""))
+		Expect(execute("del k1")).To(Equal(""))
+		Expect(shell.Prompt()).To(Equal("/a [2]> "))
+		Expect(execute("get k3")).To(Equal("v3 (uncommitted)\n"))
+		Expect(execute("count k")).To(Equal("2\n"))
+		Expect(execute("commit")).To(Equal("committed 2 writes\n"))
+		Expect(execute("ls k")).To(Equal("k2 = v2\nk3 = v3\n"))
+	})
+	It("discards writes on rollback", func() {
+		Expect(execute("cd a")).To(Equal(""))
+		Expect(execute("begin")).To(Equal(""))
+		Expect(execute("put k3 v3")).To(Equal(""))
+		Expect(execute("rollback")).To(Equal("discarded 1 writes\n"))
+		_, err := execute("get k3")
+		Expect(err).To(MatchError(ContainSubstring("not found")))
+	})
+	It("prints bucket stats", func() {
+		Expect(execute("cd a")).To(Equal(""))
+		Expect(execute("stats")).To(HavePrefix("keys=5 buckets=2 "))
+	})
+	It("completes commands, buckets and keys", func() {
+		line, candidates := shell.Complete(ctx, "co")
+		Expect(line).To(Equal("co"))
+		Expect(candidates).To(Equal([]string{"commit", "count"}))
+		line, _ = shell.Complete(ctx, "cd ")
+		Expect(line).To(Equal("cd a/"))
+		line, _ = shell.Complete(ctx, "cd a/n")
+		Expect(line).To(Equal("cd a/nested/"))
+		Expect(execute("cd a")).To(Equal(""))
+		line, candidates = shell.Complete(ctx, "get k")
+		Expect(line).To(Equal("get k"))
+		Expect(candidates).To(Equal([]string{"k1", "k2"}))
+		line, _ = shell.Complete(ctx, "get x")
+		Expect(line).To(Equal("get x1 "))
+	})
+	It("runs commands from a reader until exit", func() {
+		input := strings.NewReader("cd a\nget k1\nget banana\nexit\nget k2\n")
+		Expect(shell.Run(ctx, input)).To(BeNil())
+		Expect(out.String()).To(HavePrefix("v1\nerror: "))
+		Expect(out.String()).NotTo(ContainSubstring("v2"))
+	})
+})
@@ -0,0 +1,150 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv/cli"
+)
+
+var _ = Describe("Main", func() {
+	var ctx context.Context
+	var dataDir string
+	var stdout *bytes.Buffer
+	var stderr *bytes.Buffer
+	run := func(args ...string) int {
+		stdout.Reset()
+		stderr.Reset()
+		return cli.Main(ctx, append(args, "-datadir", dataDir), stdout, stderr)
+	}
+	BeforeEach(func() {
+		ctx = context.Background()
+		stdout = &bytes.Buffer{}
+		stderr = &bytes.Buffer{}
+		var err error
+		dataDir, err = os.MkdirTemp("", "")
+		Expect(err).To(BeNil())
+		Expect(run("bucket", "create", "-bucket", "a")).To(Equal(0))
+		Expect(run("value", "set", "-bucket", "a", "-key", "k1", "-value", "v1")).To(Equal(0))
+		Expect(run("value", "set", "-bucket", "a", "-key", "k2", "-value", "v2")).To(Equal(0))
+		Expect(run("value", "set", "-bucket", "a", "-key", "x1", "-value", "v3")).To(Equal(0))
+	})
+	AfterEach(func() {
+		_ = os.RemoveAll(dataDir)
+	})
+	It("prints usage for unknown commands", func() {
+		Expect(cli.Main(ctx, []string{"banana"}, stdout, stderr)).To(Equal(2))
+		Expect(stderr.String()).To(ContainSubstring("bucket list"))
+	})
+	It("requires datadir", func() {
+		Expect(cli.Main(ctx, []string{"bucket", "list"}, stdout, stderr)).To(Equal(2))
+		Expect(stderr.String()).To(ContainSubstring("flag -datadir is required"))
+	})
+	It("requires command flags", func() {
+		Expect(run("value", "get", "-bucket", "a")).To(Equal(2))
+		Expect(stderr.String()).To(ContainSubstring("flag -key is required"))
+	})
+	It("lists buckets", func() {
+		Expect(run("bucket", "list")).To(Equal(0))
+		Expect(stdout.String()).To(Equal("a\n"))
+	})
+	It("lists buckets as json", func() {
+		Expect(run("bucket", "list", "-format", "json")).To(Equal(0))
+		Expect(stdout.String()).To(Equal("[\n{\"bucket\":\"a\"}\n]\n"))
+	})
+	It("prints bucket stats", func() {
+		Expect(run("bucket", "stats")).To(Equal(0))
+		Expect(stdout.String()).To(HavePrefix("a\t3\t"))
+	})
+	It("prints bucket fingerprints", func() {
+		Expect(run("bucket", "fingerprint")).To(Equal(0))
+		Expect(stdout.String()).To(MatchRegexp("^a\t3\tsha256:[0-9a-f]{64}\n$"))
+		Expect(run("bucket", "fingerprint", "-bucket", "a", "-format", "jsonl")).To(Equal(0))
+		Expect(stdout.String()).To(MatchRegexp(
+			`^{"bucket":"a","keys":3,"algorithm":"sha256","fingerprint":"[0-9a-f]{64}"}\n$`,
+		))
+	})
+	It("fails to fingerprint a missing bucket", func() {
+		Expect(run("bucket", "fingerprint", "-bucket", "missing")).To(Equal(1))
+	})
+	It("gets a value", func() {
+		Expect(run("value", "get", "-bucket", "a", "-key", "k1")).To(Equal(0))
+		Expect(stdout.String()).To(Equal("v1\n"))
+	})
+	It("sets and gets a uint64 key", func() {
+		Expect(run(
+			"value", "set", "-bucket", "seq", "-key", "256", "-value", "v", "-key-encoding", "uint64",
+		)).To(Equal(0))
+		Expect(run("value", "list", "-bucket", "seq", "-key-encoding", "hex")).To(Equal(0))
+		Expect(stdout.String()).To(Equal("0000000000000100 = v\n"))
+		Expect(run("value", "list", "-bucket", "seq", "-key-encoding", "uint64", "-format", "jsonl")).
+			To(Equal(0))
+		Expect(stdout.String()).To(Equal(`{"key":"256","value":"v","key_encoding":"uint64"}` + "\n"))
+	})
+	It("fails for an unknown key encoding", func() {
+		Expect(run("value", "list", "-bucket", "a", "-key-encoding", "latin1")).To(Equal(1))
+	})
+	It("fails for a missing key", func() {
+		Expect(run("value", "get", "-bucket", "a", "-key", "missing")).To(Equal(1))
+	})
+	It("lists values", func() {
+		Expect(run("value", "list", "-bucket", "a")).To(Equal(0))
+		Expect(stdout.String()).To(Equal("k1 = v1\nk2 = v2\nx1 = v3\n"))
+	})
+	It("lists a reverse range of keys", func() {
+		Expect(run("value", "list", "-bucket", "a", "-end", "x1", "-reverse", "-keys-only")).
+			To(Equal(0))
+		Expect(stdout.String()).To(Equal("k2\nk1\n"))
+	})
+	It("counts values", func() {
+		Expect(run("value", "list", "-bucket", "a", "-prefix", "k", "-count")).To(Equal(0))
+		Expect(stdout.String()).To(Equal("2\n"))
+	})
+	It("scans values", func() {
+		Expect(run("value", "scan", "-bucket", "a", "-prefix", "k")).To(Equal(0))
+		Expect(stdout.String()).To(Equal("k1 = v1\nk2 = v2\n"))
+	})
+	It("deletes a value", func() {
+		Expect(run("value", "delete", "-bucket", "a", "-key", "k1")).To(Equal(0))
+		Expect(run("value", "list", "-bucket", "a")).To(Equal(0))
+		Expect(stdout.String()).To(Equal("k2 = v2\nx1 = v3\n"))
+	})
+	It("deletes a bucket", func() {
+		Expect(run("bucket", "delete", "-bucket", "a")).To(Equal(0))
+		Expect(run("bucket", "list")).To(Equal(0))
+		Expect(stdout.String()).To(BeEmpty())
+	})
+	It("checks the database", func() {
+		Expect(run("check")).To(Equal(0))
+		Expect(stdout.String()).To(BeEmpty())
+	})
+	It("applies a diff", func() {
+		input := filepath.Join(dataDir, "diff.jsonl")
+		Expect(os.WriteFile(input, []byte(
+			`{"type":"removed","bucket":"a","key":"k1","encoding":"utf8"}`+"\n"+
+				`{"type":"changed","bucket":"a","key":"k2","value":"new","encoding":"utf8"}`+"\n",
+		), 0600)).To(Succeed())
+		Expect(run("diff", "apply", "-input", input)).To(Equal(0))
+		Expect(run("value", "list", "-bucket", "a")).To(Equal(0))
+		Expect(stdout.String()).To(Equal("k2 = new\nx1 = v3\n"))
+	})
+	It("writes a backup", func() {
+		output := filepath.Join(dataDir, "backup.db")
+		Expect(run("backup", "-output", output)).To(Equal(0))
+		Expect(output).To(BeAnExistingFile())
+	})
+	It("writes a compacted copy", func() {
+		output := filepath.Join(dataDir, "compact.db")
+		Expect(run("compact", "-output", output)).To(Equal(0))
+		Expect(output).To(BeAnExistingFile())
+	})
+})
@@ -0,0 +1,398 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+
+	"github.com/bborbe/boltkv"
+)
+
+// Format is the output format of the commands.
+type Format string
+
+const (
+	// FormatText prints one line per result, binary data is quoted.
+	FormatText Format = "text"
+	// FormatJSON prints all results as one JSON array.
+	FormatJSON Format = "json"
+	// FormatJSONL prints one JSON object per line.
+	FormatJSONL Format = "jsonl"
+	// FormatCSV prints results as CSV with a header line.
+	FormatCSV Format = "csv"
+	// FormatHex prints values hex encoded.
+	FormatHex Format = "hex"
+	// FormatBase64 prints values base64 encoded.
+	FormatBase64 Format = "base64"
+)
+
+// Formats contains all supported formats.
+var Formats = []Format{FormatText, FormatJSON, FormatJSONL, FormatCSV, FormatHex, FormatBase64}
+
+// ParseFormat returns the Format for the given name.
+func ParseFormat(ctx context.Context, name string) (Format, error) {
+	for _, format := range Formats {
+		if string(format) == name {
+			return format, nil
+		}
+	}
+	return "", errors.Errorf(ctx, "unknown format '%s', expected one of %v", name, Formats)
+}
+
+// Writer writes command results in a Format.
+// Close must be called to complete the output.
+type Writer interface {
+	Bucket(bucketName libkv.BucketName) error
+	BucketStats(stats libkv.BucketStats) error
+	Fingerprint(fingerprint boltkv.Fingerprint) error
+	Value(key []byte, value []byte) error
+	KeyValue(key []byte, value []byte) error
+	Key(key []byte) error
+	Count(count int64) error
+	CheckFinding(finding boltkv.CheckFinding) error
+	Close() error
+}
+
+// NewWriter returns a Writer for the format, keys are printed with keyEncoding.
+// Binary values are base64 encoded in json, jsonl and csv and quoted in text.
+func NewWriter(
+	ctx context.Context,
+	w io.Writer,
+	format Format,
+	keyEncoding boltkv.Encoding,
+) (Writer, error) {
+	bw := bufio.NewWriter(w)
+	switch format {
+	case FormatText:
+		return &textWriter{w: bw, keyEncoding: keyEncoding, separator: " = "}, nil
+	case FormatHex:
+		return &textWriter{
+			w:             bw,
+			keyEncoding:   keyEncoding,
+			valueEncoding: boltkv.EncodingHex,
+			separator:     "\t",
+		}, nil
+	case FormatBase64:
+		return &textWriter{
+			w:             bw,
+			keyEncoding:   keyEncoding,
+			valueEncoding: boltkv.EncodingBase64,
+			separator:     "\t",
+		}, nil
+	case FormatJSON:
+		return &jsonWriter{w: bw, keyEncoding: keyEncoding}, nil
+	case FormatJSONL:
+		return &jsonWriter{w: bw, keyEncoding: keyEncoding, lines: true}, nil
+	case FormatCSV:
+		return &csvWriter{w: bw, csv: csv.NewWriter(bw), keyEncoding: keyEncoding}, nil
+	default:
+		return nil, errors.Errorf(ctx, "unknown format '%s'", format)
+	}
+}
+
+// textWriter prints one line per result.
+// Values are printed as is if valueEncoding is empty.
+type textWriter struct {
+	w             *bufio.Writer
+	keyEncoding   boltkv.Encoding
+	valueEncoding boltkv.Encoding
+	separator     string
+}
+
+func (t *textWriter) Bucket(bucketName libkv.BucketName) error {
+	_, err := fmt.Fprintln(t.w, boltkv.EncodingUTF8.Text(bucketName.Bytes()))
+	return err
+}
+
+func (t *textWriter) BucketStats(stats libkv.BucketStats) error {
+	_, err := fmt.Fprintf(
+		t.w,
+		"%s\t%d\t%d\n",
+		boltkv.EncodingUTF8.Text(stats.Name.Bytes()),
+		stats.KeyCount,
+		stats.SizeB,
+	)
+	return err
+}
+
+func (t *textWriter) Fingerprint(fingerprint boltkv.Fingerprint) error {
+	_, err := fmt.Fprintf(
+		t.w,
+		"%s\t%d\t%s\n",
+		boltkv.EncodingUTF8.Text(fingerprint.Bucket.Bytes()),
+		fingerprint.Keys,
+		fingerprint,
+	)
+	return err
+}
+
+func (t *textWriter) Value(key []byte, value []byte) error {
+	_, err := fmt.Fprintln(t.w, t.value(value))
+	return err
+}
+
+func (t *textWriter) KeyValue(key []byte, value []byte) error {
+	_, err := fmt.Fprintf(t.w, "%s%s%s\n", t.keyEncoding.Text(key), t.separator, t.value(value))
+	return err
+}
+
+func (t *textWriter) Key(key []byte) error {
+	_, err := fmt.Fprintln(t.w, t.keyEncoding.Text(key))
+	return err
+}
+
+func (t *textWriter) Count(count int64) error {
+	_, err := fmt.Fprintln(t.w, count)
+	return err
+}
+
+func (t *textWriter) value(value []byte) string {
+	if t.valueEncoding == "" {
+		return boltkv.EncodingUTF8.Text(value)
+	}
+	result, _ := t.valueEncoding.Encode(value)
+	return result
+}
+
+func (t *textWriter) CheckFinding(finding boltkv.CheckFinding) error {
+	_, err := fmt.Fprintln(t.w, finding.String())
+	return err
+}
+
+func (t *textWriter) Close() error {
+	return t.w.Flush()
+}
+
+type jsonBucket struct {
+	Bucket string `json:"bucket"`
+	Keys   *int64 `json:"keys,omitempty"`
+	SizeB  *int64 `json:"size,omitempty"`
+}
+
+type jsonFingerprint struct {
+	Bucket      string `json:"bucket"`
+	Keys        int64  `json:"keys"`
+	Algorithm   string `json:"algorithm"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+type jsonKey struct {
+	Key         string          `json:"key"`
+	KeyEncoding boltkv.Encoding `json:"key_encoding,omitempty"`
+}
+
+type jsonCount struct {
+	Count int64 `json:"count"`
+}
+
+type jsonCheckFinding struct {
+	Check   string `json:"check"`
+	Message string `json:"message"`
+}
+
+// jsonKeyValue omits the encodings if key and value are plain UTF-8.
+type jsonKeyValue struct {
+	Key           string          `json:"key"`
+	Value         string          `json:"value"`
+	KeyEncoding   boltkv.Encoding `json:"key_encoding,omitempty"`
+	ValueEncoding boltkv.Encoding `json:"value_encoding,omitempty"`
+}
+
+func newJSONKey(keyEncoding boltkv.Encoding, key []byte) jsonKey {
+	var result jsonKey
+	result.Key, result.KeyEncoding = keyEncoding.Encode(key)
+	if result.KeyEncoding == boltkv.EncodingUTF8 {
+		result.KeyEncoding = ""
+	}
+	return result
+}
+
+func newJSONKeyValue(keyEncoding boltkv.Encoding, key []byte, value []byte) jsonKeyValue {
+	var result jsonKeyValue
+	result.Key, result.KeyEncoding = keyEncoding.Encode(key)
+	result.Value, result.ValueEncoding = boltkv.EncodingUTF8.Encode(value)
+	if result.KeyEncoding == boltkv.EncodingUTF8 {
+		result.KeyEncoding = ""
+	}
+	if result.ValueEncoding == boltkv.EncodingUTF8 {
+		result.ValueEncoding = ""
+	}
+	return result
+}
+
+// jsonWriter writes all results as one JSON array, or one object per line if lines is set.
+type jsonWriter struct {
+	w           *bufio.Writer
+	keyEncoding boltkv.Encoding
+	lines       bool
+	count       int
+}
+
+func (j *jsonWriter) Bucket(bucketName libkv.BucketName) error {
+	return j.write(jsonBucket{Bucket: boltkv.EncodingUTF8.Text(bucketName.Bytes())})
+}
+
+func (j *jsonWriter) BucketStats(stats libkv.BucketStats) error {
+	return j.write(jsonBucket{
+		Bucket: boltkv.EncodingUTF8.Text(stats.Name.Bytes()),
+		Keys:   &stats.KeyCount,
+		SizeB:  &stats.SizeB,
+	})
+}
+
+func (j *jsonWriter) Fingerprint(fingerprint boltkv.Fingerprint) error {
+	return j.write(jsonFingerprint{
+		Bucket:      boltkv.EncodingUTF8.Text(fingerprint.Bucket.Bytes()),
+		Keys:        fingerprint.Keys,
+		Algorithm:   string(fingerprint.Algorithm),
+		Fingerprint: hex.EncodeToString(fingerprint.Hash),
+	})
+}
+
+func (j *jsonWriter) Value(key []byte, value []byte) error {
+	return j.KeyValue(key, value)
+}
+
+func (j *jsonWriter) KeyValue(key []byte, value []byte) error {
+	return j.write(newJSONKeyValue(j.keyEncoding, key, value))
+}
+
+func (j *jsonWriter) Key(key []byte) error {
+	return j.write(newJSONKey(j.keyEncoding, key))
+}
+
+func (j *jsonWriter) Count(count int64) error {
+	return j.write(jsonCount{Count: count})
+}
+
+func (j *jsonWriter) CheckFinding(finding boltkv.CheckFinding) error {
+	return j.write(jsonCheckFinding{Check: finding.Check, Message: finding.Message})
+}
+
+func (j *jsonWriter) write(value interface{}) error {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	separator := ",\n"
+	switch {
+	case j.lines && j.count == 0:
+		separator = ""
+	case j.lines:
+		separator = "\n"
+	case j.count == 0:
+		separator = "[\n"
+	}
+	j.count++
+	if _, err := j.w.WriteString(separator); err != nil {
+		return err
+	}
+	_, err = j.w.Write(content)
+	return err
+}
+
+func (j *jsonWriter) Close() error {
+	end := "\n]\n"
+	switch {
+	case j.lines && j.count == 0:
+		end = ""
+	case j.lines:
+		end = "\n"
+	case j.count == 0:
+		end = "[]\n"
+	}
+	if _, err := j.w.WriteString(end); err != nil {
+		return err
+	}
+	return j.w.Flush()
+}
+
+// csvWriter writes a header line before the first record and whenever the record type changes.
+type csvWriter struct {
+	w           *bufio.Writer
+	csv         *csv.Writer
+	keyEncoding boltkv.Encoding
+	header      string
+}
+
+func (c *csvWriter) Bucket(bucketName libkv.BucketName) error {
+	return c.write([]string{"bucket"}, boltkv.EncodingUTF8.Text(bucketName.Bytes()))
+}
+
+func (c *csvWriter) BucketStats(stats libkv.BucketStats) error {
+	return c.write(
+		[]string{"bucket", "keys", "size"},
+		boltkv.EncodingUTF8.Text(stats.Name.Bytes()),
+		strconv.FormatInt(stats.KeyCount, 10),
+		strconv.FormatInt(stats.SizeB, 10),
+	)
+}
+
+func (c *csvWriter) Fingerprint(fingerprint boltkv.Fingerprint) error {
+	return c.write(
+		[]string{"bucket", "keys", "algorithm", "fingerprint"},
+		boltkv.EncodingUTF8.Text(fingerprint.Bucket.Bytes()),
+		strconv.FormatInt(fingerprint.Keys, 10),
+		string(fingerprint.Algorithm),
+		hex.EncodeToString(fingerprint.Hash),
+	)
+}
+
+func (c *csvWriter) Value(key []byte, value []byte) error {
+	return c.KeyValue(key, value)
+}
+
+func (c *csvWriter) KeyValue(key []byte, value []byte) error {
+	kv := newJSONKeyValue(c.keyEncoding, key, value)
+	return c.write(
+		[]string{"key", "value", "key_encoding", "value_encoding"},
+		kv.Key,
+		kv.Value,
+		string(kv.KeyEncoding),
+		string(kv.ValueEncoding),
+	)
+}
+
+func (c *csvWriter) Key(key []byte) error {
+	k := newJSONKey(c.keyEncoding, key)
+	return c.write([]string{"key", "key_encoding"}, k.Key, string(k.KeyEncoding))
+}
+
+func (c *csvWriter) Count(count int64) error {
+	return c.write([]string{"count"}, strconv.FormatInt(count, 10))
+}
+
+func (c *csvWriter) CheckFinding(finding boltkv.CheckFinding) error {
+	return c.write([]string{"check", "message"}, finding.Check, finding.Message)
+}
+
+func (c *csvWriter) write(header []string, values ...string) error {
+	if key := strings.Join(header, ","); key != c.header {
+		c.header = key
+		if err := c.csv.Write(header); err != nil {
+			return err
+		}
+	}
+	return c.csv.Write(values)
+}
+
+func (c *csvWriter) Close() error {
+	c.csv.Flush()
+	if err := c.csv.Error(); err != nil {
+		return err
+	}
+	return c.w.Flush()
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+	"github.com/bborbe/boltkv/cli"
+)
+
+var _ = Describe("Writer", func() {
+	var ctx context.Context
+	var buf *bytes.Buffer
+	var writer cli.Writer
+	var format cli.Format
+	JustBeforeEach(func() {
+		ctx = context.Background()
+		buf = &bytes.Buffer{}
+		var err error
+		writer, err = cli.NewWriter(ctx, buf, format, boltkv.EncodingUTF8)
+		Expect(err).To(BeNil())
+		Expect(writer.Bucket(libkv.NewBucketName("a"))).To(Succeed())
+		Expect(writer.BucketStats(libkv.BucketStats{
+			Name:     libkv.NewBucketName("b"),
+			KeyCount: 2,
+			SizeB:    3,
+		})).To(Succeed())
+		Expect(writer.Value([]byte("k"), []byte("v"))).To(Succeed())
+		Expect(writer.KeyValue([]byte("k"), []byte("v"))).To(Succeed())
+		Expect(writer.Key([]byte("k"))).To(Succeed())
+		Expect(writer.Count(7)).To(Succeed())
+		Expect(writer.CheckFinding(boltkv.CheckFinding{Check: "bolt", Message: "broken"})).To(Succeed())
+		Expect(writer.Close()).To(Succeed())
+	})
+	Context("text", func() {
+		BeforeEach(func() {
+			format = cli.FormatText
+		})
+		It("writes lines", func() {
+			Expect(buf.String()).To(Equal("a\nb\t2\t3\nv\nk = v\nk\n7\nbolt: broken\n"))
+		})
+	})
+	Context("json", func() {
+		BeforeEach(func() {
+			format = cli.FormatJSON
+		})
+		It("writes an array", func() {
+			Expect(buf.String()).To(Equal(`[
+{"bucket":"a"},
+{"bucket":"b","keys":2,"size":3},
+{"key":"k","value":"v"},
+{"key":"k","value":"v"},
+{"key":"k"},
+{"count":7},
+{"check":"bolt","message":"broken"}
+]
+`))
+		})
+	})
+	Context("jsonl", func() {
+		BeforeEach(func() {
+			format = cli.FormatJSONL
+		})
+		It("writes one object per line", func() {
+			Expect(buf.String()).To(Equal(`{"bucket":"a"}
+{"bucket":"b","keys":2,"size":3}
+{"key":"k","value":"v"}
+{"key":"k","value":"v"}
+{"key":"k"}
+{"count":7}
+{"check":"bolt","message":"broken"}
+`))
+		})
+	})
+	Context("csv", func() {
+		BeforeEach(func() {
+			format = cli.FormatCSV
+		})
+		It("writes a header per record type", func() {
+			Expect(buf.String()).To(Equal(`bucket
+a
+bucket,keys,size
+b,2,3
+key,value,key_encoding,value_encoding
+k,v,,
+k,v,,
+key,key_encoding
+k,
+count
+7
+check,message
+bolt,broken
+`))
+		})
+	})
+	Context("hex", func() {
+		BeforeEach(func() {
+			format = cli.FormatHex
+		})
+		It("encodes values", func() {
+			Expect(buf.String()).To(Equal("a\nb\t2\t3\n76\nk\t76\nk\n7\nbolt: broken\n"))
+		})
+	})
+	Context("base64", func() {
+		BeforeEach(func() {
+			format = cli.FormatBase64
+		})
+		It("encodes values", func() {
+			Expect(buf.String()).To(Equal("a\nb\t2\t3\ndg==\nk\tdg==\nk\n7\nbolt: broken\n"))
+		})
+	})
+	DescribeTable("binary data",
+		func(format cli.Format, keyEncoding boltkv.Encoding, expected string) {
+			buf := &bytes.Buffer{}
+			writer, err := cli.NewWriter(context.Background(), buf, format, keyEncoding)
+			Expect(err).To(BeNil())
+			Expect(writer.KeyValue([]byte{0, 0, 0, 0, 0, 0, 1, 0}, []byte{0xff, 'a'})).To(Succeed())
+			Expect(writer.Close()).To(Succeed())
+			Expect(buf.String()).To(Equal(expected))
+		},
+		Entry("text", cli.FormatText, boltkv.EncodingUTF8,
+			"\"\\x00\\x00\\x00\\x00\\x00\\x00\\x01\\x00\" = \"\\xffa\"\n"),
+		Entry("text uint64", cli.FormatText, boltkv.EncodingUint64, "256 = \"\\xffa\"\n"),
+		Entry("hex", cli.FormatHex, boltkv.EncodingHex, "0000000000000100\tff61\n"),
+		Entry("jsonl", cli.FormatJSONL, boltkv.EncodingUTF8,
+			`{"key":"\u0000\u0000\u0000\u0000\u0000\u0000\u0001\u0000",`+
+				`"value":"/2E=","value_encoding":"base64"}`+"\n"),
+		Entry("jsonl uint64", cli.FormatJSONL, boltkv.EncodingUint64,
+			`{"key":"256","value":"/2E=","key_encoding":"uint64","value_encoding":"base64"}`+"\n"),
+		Entry("csv", cli.FormatCSV, boltkv.EncodingHex,
+			"key,value,key_encoding,value_encoding\n0000000000000100,/2E=,hex,base64\n"),
+	)
+	DescribeTable("fingerprints",
+		func(format cli.Format, expected string) {
+			buf := &bytes.Buffer{}
+			writer, err := cli.NewWriter(context.Background(), buf, format, boltkv.EncodingUTF8)
+			Expect(err).To(BeNil())
+			Expect(writer.Fingerprint(boltkv.Fingerprint{
+				Bucket:    libkv.NewBucketName("a"),
+				Algorithm: boltkv.FingerprintAlgorithmSHA256,
+				Keys:      2,
+				Hash:      []byte{0xab, 0xcd},
+			})).To(Succeed())
+			Expect(writer.Close()).To(Succeed())
+			Expect(buf.String()).To(Equal(expected))
+		},
+		Entry("text", cli.FormatText, "a\t2\tsha256:abcd\n"),
+		Entry("jsonl", cli.FormatJSONL,
+			`{"bucket":"a","keys":2,"algorithm":"sha256","fingerprint":"abcd"}`+"\n"),
+		Entry("csv", cli.FormatCSV, "bucket,keys,algorithm,fingerprint\na,2,sha256,abcd\n"),
+	)
+	It("writes an empty json array", func() {
+		buf := &bytes.Buffer{}
+		writer, err := cli.NewWriter(context.Background(), buf, cli.FormatJSON, boltkv.EncodingUTF8)
+		Expect(err).To(BeNil())
+		Expect(writer.Close()).To(Succeed())
+		Expect(buf.String()).To(Equal("[]\n"))
+	})
+	It("returns error for unknown formats", func() {
+		_, err := cli.ParseFormat(context.Background(), "xml")
+		Expect(err).NotTo(BeNil())
+	})
+})
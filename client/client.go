@@ -0,0 +1,247 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package client implements libkv.DB on top of the HTTP API of the server package.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+
+	"github.com/bborbe/boltkv/server"
+)
+
+// DefaultPageSize is the number of pairs an iterator fetches per request.
+const DefaultPageSize = 100
+
+// Backend is reported in libkv.Stats.
+const Backend = "boltkv-remote"
+
+type contextKey string
+
+const stateCtxKey contextKey = "state"
+
+// Options configures New.
+type Options struct {
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// Token is sent as bearer token if not empty.
+	Token string
+	// PageSize is the number of pairs an iterator fetches per request,
+	// defaults to DefaultPageSize.
+	PageSize int
+}
+
+// New returns a libkv.DB using the server at url.
+//
+// Update buffers all writes of its callback and submits them as one atomic
+// server.Batch after the callback returns, reads inside the callback see the
+// buffered writes. Reads are served by the server per request, so neither
+// View nor Update is isolated from concurrent writers. Iterators fetch pages
+// through the server's scan cursors.
+func New(url string, opts Options) libkv.DB {
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = DefaultPageSize
+	}
+	return &db{
+		url:  strings.TrimSuffix(url, "/"),
+		opts: opts,
+	}
+}
+
+type db struct {
+	url  string
+	opts Options
+}
+
+func (d *db) Update(ctx context.Context, fn func(ctx context.Context, tx libkv.Tx) error) error {
+	if ctx.Value(stateCtxKey) != nil {
+		return errors.Wrapf(ctx, libkv.TransactionAlreadyOpenError, "transaction already open")
+	}
+	tx := newTx(ctx, d, true)
+	if err := fn(context.WithValue(ctx, stateCtxKey, true), tx); err != nil {
+		return errors.Wrapf(ctx, err, "db update failed")
+	}
+	if tx.err != nil {
+		return errors.Wrapf(ctx, tx.err, "db update failed")
+	}
+	if len(tx.operations) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(server.Batch{Operations: tx.operations})
+	if err != nil {
+		return errors.Wrapf(ctx, err, "marshal batch failed")
+	}
+	if err := d.do(ctx, http.MethodPost, "/batch", nil, body, nil); err != nil {
+		return errors.Wrapf(ctx, err, "db update failed")
+	}
+	return nil
+}
+
+func (d *db) View(ctx context.Context, fn func(ctx context.Context, tx libkv.Tx) error) error {
+	if ctx.Value(stateCtxKey) != nil {
+		return errors.Wrapf(ctx, libkv.TransactionAlreadyOpenError, "transaction already open")
+	}
+	tx := newTx(ctx, d, false)
+	if err := fn(context.WithValue(ctx, stateCtxKey, true), tx); err != nil {
+		return errors.Wrapf(ctx, err, "db view failed")
+	}
+	if tx.err != nil {
+		return errors.Wrapf(ctx, tx.err, "db view failed")
+	}
+	return nil
+}
+
+// Sync does nothing, the server syncs every Update.
+func (d *db) Sync() error {
+	return nil
+}
+
+// Close releases idle connections.
+func (d *db) Close() error {
+	d.opts.HTTPClient.CloseIdleConnections()
+	return nil
+}
+
+// Remove does nothing, the database is owned by the server.
+func (d *db) Remove() error {
+	return nil
+}
+
+func (d *db) Stats(ctx context.Context) (*libkv.Stats, error) {
+	var buckets []bucketResponse
+	if err := d.do(ctx, http.MethodGet, "/buckets", nil, nil, &buckets); err != nil {
+		return nil, errors.Wrapf(ctx, err, "list buckets failed")
+	}
+	return newStats(buckets, false), nil
+}
+
+func (d *db) StatsDetailed(ctx context.Context) (*libkv.Stats, error) {
+	var buckets []bucketResponse
+	if err := d.do(ctx, http.MethodGet, "/stats", nil, nil, &buckets); err != nil {
+		return nil, errors.Wrapf(ctx, err, "get stats failed")
+	}
+	return newStats(buckets, true), nil
+}
+
+type bucketResponse struct {
+	Bucket string `json:"bucket"`
+	Keys   int64  `json:"keys"`
+	SizeB  int64  `json:"size"`
+}
+
+func newStats(buckets []bucketResponse, detailed bool) *libkv.Stats {
+	stats := &libkv.Stats{
+		Backend:  Backend,
+		Detailed: detailed,
+		Buckets:  make([]libkv.BucketStats, 0, len(buckets)),
+	}
+	for _, bucket := range buckets {
+		stats.SizeB += bucket.SizeB
+		stats.Buckets = append(stats.Buckets, libkv.BucketStats{
+			Name:     libkv.NewBucketName(bucket.Bucket),
+			KeyCount: bucket.Keys,
+			SizeB:    bucket.SizeB,
+		})
+	}
+	return stats
+}
+
+// bucketPath returns the path of the bucket, with the key if not nil.
+func bucketPath(bucketName libkv.BucketName, key []byte) string {
+	path := "/buckets/" + url.PathEscape(bucketName.String())
+	if key != nil {
+		path += "/keys/" + hex.EncodeToString(key)
+	}
+	return path
+}
+
+// do sends the request and decodes a JSON response into result if not nil.
+// Error responses are mapped to the libkv errors by their code.
+func (d *db) do(
+	ctx context.Context,
+	method string,
+	path string,
+	query url.Values,
+	body []byte,
+	result interface{},
+) error {
+	resp, err := d.send(ctx, method, path, query, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return errors.Wrapf(ctx, err, "decode %s %s failed", method, path)
+	}
+	return nil
+}
+
+// send returns the response of a successful request, the caller closes its body.
+func (d *db) send(
+	ctx context.Context,
+	method string,
+	path string,
+	query url.Values,
+	body []byte,
+) (*http.Response, error) {
+	target := d.url + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "create request failed")
+	}
+	if d.opts.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+d.opts.Token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := d.opts.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "%s %s failed", method, path)
+	}
+	if resp.StatusCode < http.StatusBadRequest {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	content, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var errorResponse server.ErrorResponse
+	_ = json.Unmarshal(content, &errorResponse)
+	switch errorResponse.Code {
+	case server.ErrorCodeBucketNotFound:
+		err = libkv.BucketNotFoundError
+	case server.ErrorCodeBucketAlreadyExists:
+		err = libkv.BucketAlreadyExistsError
+	case server.ErrorCodeKeyNotFound:
+		err = libkv.KeyNotFoundError
+	default:
+		return nil, errors.Errorf(
+			ctx,
+			"%s %s failed with status %d: %s",
+			method,
+			path,
+			resp.StatusCode,
+			content,
+		)
+	}
+	return nil, errors.Wrapf(ctx, err, "%s %s failed: %s", method, path, errorResponse.Error)
+}
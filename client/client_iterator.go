@@ -0,0 +1,237 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"bytes"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+
+	"github.com/bborbe/boltkv/server"
+)
+
+func newRemoteIterator(
+	tx *tx,
+	bucketName libkv.BucketName,
+	reverse bool,
+) libkv.Iterator {
+	return &remoteIterator{
+		tx:         tx,
+		bucketName: bucketName,
+		reverse:    reverse,
+	}
+}
+
+// remoteIterator pages through a bucket with the scan cursors of the server.
+// A failed request ends the iteration and fails the enclosing View or Update.
+type remoteIterator struct {
+	tx         *tx
+	bucketName libkv.BucketName
+	reverse    bool
+
+	query url.Values
+	pairs []server.Pair
+	index int
+	// cursor is the server cursor of the next page, empty on the last page
+	cursor string
+}
+
+func (r *remoteIterator) Close() {
+	r.pairs = nil
+	r.cursor = ""
+}
+
+func (r *remoteIterator) Item() libkv.Item {
+	if !r.Valid() {
+		return nil
+	}
+	pair := r.pairs[r.index]
+	return libkv.NewByteItem(pair.Key, pair.Value)
+}
+
+func (r *remoteIterator) Next() {
+	r.index++
+	if r.index >= len(r.pairs) && r.cursor != "" {
+		query := url.Values{}
+		for key, values := range r.query {
+			query[key] = values
+		}
+		query.Set("cursor", r.cursor)
+		r.fetch(query)
+	}
+}
+
+func (r *remoteIterator) Valid() bool {
+	return r.index < len(r.pairs)
+}
+
+func (r *remoteIterator) Rewind() {
+	r.start(url.Values{})
+}
+
+// Seek moves to the first key >= key, or the last key <= key for reverse iterators.
+func (r *remoteIterator) Seek(key []byte) {
+	query := url.Values{}
+	if r.reverse {
+		// end is exclusive
+		query.Set("end", hex.EncodeToString(append(bytes.Clone(key), 0)))
+	} else if len(key) > 0 {
+		query.Set("start", hex.EncodeToString(key))
+	}
+	r.start(query)
+}
+
+func (r *remoteIterator) start(query url.Values) {
+	query.Set("key_encoding", "hex")
+	query.Set("limit", strconv.Itoa(r.tx.db.opts.PageSize))
+	if r.reverse {
+		query.Set("reverse", "true")
+	}
+	r.query = query
+	r.fetch(query)
+}
+
+func (r *remoteIterator) fetch(query url.Values) {
+	var page server.Page
+	ctx := r.tx.ctx
+	err := r.tx.db.do(ctx, http.MethodGet, bucketPath(r.bucketName, nil)+"/keys", query, nil, &page)
+	if err != nil {
+		r.tx.fail(errors.Wrapf(ctx, err, "fetch page of %s failed", r.bucketName))
+		page = server.Page{}
+	}
+	r.pairs = page.Pairs
+	r.cursor = page.Cursor
+	r.index = 0
+}
+
+type emptyIterator struct{}
+
+func (emptyIterator) Close()           {}
+func (emptyIterator) Item() libkv.Item { return nil }
+func (emptyIterator) Next()            {}
+func (emptyIterator) Valid() bool      { return false }
+func (emptyIterator) Rewind()          {}
+func (emptyIterator) Seek(key []byte)  {}
+
+// overlayEntry is a buffered write, a nil value deletes the key.
+type overlayEntry struct {
+	key   []byte
+	value []byte
+}
+
+func newMergeIterator(
+	remote libkv.Iterator,
+	writes map[string][]byte,
+	reverse bool,
+) libkv.Iterator {
+	overlay := make([]overlayEntry, 0, len(writes))
+	for key, value := range writes {
+		overlay = append(overlay, overlayEntry{key: []byte(key), value: value})
+	}
+	m := &mergeIterator{
+		remote:  remote,
+		overlay: overlay,
+		reverse: reverse,
+	}
+	sort.Slice(overlay, func(i, j int) bool {
+		return m.before(overlay[i].key, overlay[j].key)
+	})
+	return m
+}
+
+// mergeIterator merges the buffered writes of a tx into the keys of the server,
+// buffered values shadow server values of the same key.
+type mergeIterator struct {
+	remote  libkv.Iterator
+	overlay []overlayEntry
+	reverse bool
+
+	pos         int
+	current     libkv.Item
+	fromOverlay bool
+}
+
+// before reports whether a comes before b in iteration order.
+func (m *mergeIterator) before(a []byte, b []byte) bool {
+	if m.reverse {
+		return bytes.Compare(a, b) > 0
+	}
+	return bytes.Compare(a, b) < 0
+}
+
+func (m *mergeIterator) Close() {
+	m.remote.Close()
+}
+
+func (m *mergeIterator) Item() libkv.Item {
+	return m.current
+}
+
+func (m *mergeIterator) Next() {
+	if m.current == nil {
+		return
+	}
+	if m.fromOverlay {
+		m.pos++
+	} else {
+		m.remote.Next()
+	}
+	m.settle()
+}
+
+func (m *mergeIterator) Valid() bool {
+	return m.current != nil
+}
+
+func (m *mergeIterator) Rewind() {
+	m.remote.Rewind()
+	m.pos = 0
+	m.settle()
+}
+
+func (m *mergeIterator) Seek(key []byte) {
+	m.remote.Seek(key)
+	m.pos = sort.Search(len(m.overlay), func(i int) bool {
+		return !m.before(m.overlay[i].key, key)
+	})
+	m.settle()
+}
+
+// settle selects the next item of remote and overlay, skipping deleted keys.
+func (m *mergeIterator) settle() {
+	for {
+		remoteValid := m.remote.Valid()
+		if m.pos >= len(m.overlay) {
+			m.current, m.fromOverlay = nil, false
+			if remoteValid {
+				m.current = m.remote.Item()
+			}
+			return
+		}
+		entry := m.overlay[m.pos]
+		if remoteValid {
+			remoteKey := m.remote.Item().Key()
+			if m.before(remoteKey, entry.key) {
+				m.current, m.fromOverlay = m.remote.Item(), false
+				return
+			}
+			if bytes.Equal(remoteKey, entry.key) {
+				m.remote.Next()
+			}
+		}
+		if entry.value == nil {
+			m.pos++
+			continue
+		}
+		m.current, m.fromOverlay = libkv.NewByteItem(entry.key, entry.value), true
+		return
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+	"github.com/bborbe/boltkv/client"
+	"github.com/bborbe/boltkv/server"
+)
+
+var _ = Describe("Iterator", func() {
+	var ctx context.Context
+	var boltDB boltkv.DB
+	var httpServer *httptest.Server
+	var db libkv.DB
+	var bucketName libkv.BucketName
+	value := func(item libkv.Item) []byte {
+		var result []byte
+		Expect(item.Value(func(val []byte) error {
+			result = bytes.Clone(val)
+			return nil
+		})).To(Succeed())
+		return result
+	}
+	keys := func(it libkv.Iterator) []string {
+		var result []string
+		for ; it.Valid(); it.Next() {
+			result = append(result, string(it.Item().Key()))
+		}
+		return result
+	}
+	BeforeEach(func() {
+		ctx = context.Background()
+		var err error
+		boltDB, err = boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		bucketName = libkv.NewBucketName("a")
+		err = boltDB.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.CreateBucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			for _, key := range []string{"b", "d", "f", "h", "j"} {
+				if err := bucket.Put(ctx, []byte(key), []byte("v"+key)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		Expect(err).To(BeNil())
+		httpServer = httptest.NewServer(server.NewHandler(boltDB, server.Options{}))
+		db = client.New(httpServer.URL, client.Options{
+			HTTPClient: httpServer.Client(),
+			PageSize:   2,
+		})
+	})
+	AfterEach(func() {
+		_ = db.Close()
+		httpServer.Close()
+		_ = boltDB.Close()
+		_ = boltDB.Remove()
+	})
+	view := func(fn func(bucket libkv.Bucket)) {
+		err := db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			fn(bucket)
+			return nil
+		})
+		Expect(err).To(BeNil())
+	}
+	update := func(fn func(bucket libkv.Bucket)) {
+		err := db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			Expect(bucket.Put(ctx, []byte("a"), []byte("va"))).To(Succeed())
+			Expect(bucket.Put(ctx, []byte("d"), []byte("new"))).To(Succeed())
+			Expect(bucket.Delete(ctx, []byte("f"))).To(Succeed())
+			Expect(bucket.Put(ctx, []byte("g"), []byte("vg"))).To(Succeed())
+			Expect(bucket.Put(ctx, []byte("k"), []byte("vk"))).To(Succeed())
+			fn(bucket)
+			return nil
+		})
+		Expect(err).To(BeNil())
+	}
+	It("pages forward", func() {
+		view(func(bucket libkv.Bucket) {
+			it := bucket.Iterator()
+			defer it.Close()
+			it.Rewind()
+			Expect(keys(it)).To(Equal([]string{"b", "d", "f", "h", "j"}))
+		})
+	})
+	It("pages reverse", func() {
+		view(func(bucket libkv.Bucket) {
+			it := bucket.IteratorReverse()
+			defer it.Close()
+			it.Rewind()
+			Expect(keys(it)).To(Equal([]string{"j", "h", "f", "d", "b"}))
+		})
+	})
+	It("seeks", func() {
+		view(func(bucket libkv.Bucket) {
+			it := bucket.Iterator()
+			defer it.Close()
+			it.Seek([]byte("e"))
+			Expect(keys(it)).To(Equal([]string{"f", "h", "j"}))
+			it = bucket.IteratorReverse()
+			defer it.Close()
+			it.Seek([]byte("f"))
+			Expect(keys(it)).To(Equal([]string{"f", "d", "b"}))
+		})
+	})
+	It("merges buffered writes forward", func() {
+		update(func(bucket libkv.Bucket) {
+			it := bucket.Iterator()
+			defer it.Close()
+			it.Rewind()
+			Expect(keys(it)).To(Equal([]string{"a", "b", "d", "g", "h", "j", "k"}))
+			it.Seek([]byte("c"))
+			Expect(it.Valid()).To(BeTrue())
+			Expect(value(it.Item())).To(Equal([]byte("new")))
+		})
+	})
+	It("merges buffered writes reverse", func() {
+		update(func(bucket libkv.Bucket) {
+			it := bucket.IteratorReverse()
+			defer it.Close()
+			it.Rewind()
+			Expect(keys(it)).To(Equal([]string{"k", "j", "h", "g", "d", "b", "a"}))
+			it.Seek([]byte("f"))
+			Expect(keys(it)).To(Equal([]string{"d", "b", "a"}))
+		})
+	})
+	It("fails the transaction if a page cannot be fetched", func() {
+		handler := server.NewHandler(boltDB, server.Options{})
+		failing := httptest.NewServer(http.HandlerFunc(
+			func(resp http.ResponseWriter, req *http.Request) {
+				if req.URL.Query().Get("cursor") != "" {
+					http.Error(resp, "banana", http.StatusInternalServerError)
+					return
+				}
+				handler.ServeHTTP(resp, req)
+			},
+		))
+		defer failing.Close()
+		db := client.New(failing.URL, client.Options{
+			HTTPClient: failing.Client(),
+			PageSize:   2,
+		})
+		var seen []string
+		err := db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			it := bucket.Iterator()
+			defer it.Close()
+			it.Rewind()
+			seen = keys(it)
+			return nil
+		})
+		Expect(err).To(MatchError(ContainSubstring("fetch page of a failed")))
+		Expect(seen).To(Equal([]string{"b", "d"}))
+	})
+})
@@ -0,0 +1,199 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+	"github.com/bborbe/boltkv/client"
+	"github.com/bborbe/boltkv/server"
+)
+
+var _ = Describe("Client", func() {
+	var ctx context.Context
+	var boltDB boltkv.DB
+	var opts server.Options
+	var httpServer *httptest.Server
+	var db libkv.DB
+	var provider libkv.ProviderFunc = func(ctx context.Context) (libkv.DB, error) {
+		return db, nil
+	}
+	get := func(bucketName string, key string) []byte {
+		var value []byte
+		err := boltDB.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.Bucket(ctx, libkv.NewBucketName(bucketName))
+			if err != nil {
+				return err
+			}
+			item, err := bucket.Get(ctx, []byte(key))
+			if err != nil {
+				return err
+			}
+			return item.Value(func(val []byte) error {
+				value = bytes.Clone(val)
+				return nil
+			})
+		})
+		Expect(err).To(BeNil())
+		return value
+	}
+	BeforeEach(func() {
+		ctx = context.Background()
+		var err error
+		boltDB, err = boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		opts = server.Options{}
+	})
+	JustBeforeEach(func() {
+		httpServer = httptest.NewServer(server.NewHandler(boltDB, opts))
+		db = client.New(httpServer.URL, client.Options{
+			HTTPClient: httpServer.Client(),
+			Token:      "secret",
+			PageSize:   2,
+		})
+	})
+	AfterEach(func() {
+		_ = db.Close()
+		httpServer.Close()
+		_ = boltDB.Close()
+		_ = boltDB.Remove()
+	})
+	Context("libkv", func() {
+		libkv.BucketTestSuite(provider)
+		libkv.BasicTestSuite(provider)
+		libkv.IteratorTestSuite(provider)
+	})
+	It("applies all writes of an update atomically", func() {
+		err := db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.CreateBucket(ctx, libkv.NewBucketName("a"))
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(ctx, []byte("k1"), []byte("v1")); err != nil {
+				return err
+			}
+			_, err = tx.CreateBucket(ctx, libkv.NewBucketName("b"))
+			return err
+		})
+		Expect(err).To(BeNil())
+		Expect(get("a", "k1")).To(Equal([]byte("v1")))
+
+		err = db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.Bucket(ctx, libkv.NewBucketName("a"))
+			if err != nil {
+				return err
+			}
+			return bucket.Put(ctx, []byte("k1"), []byte("v2"))
+		})
+		Expect(err).To(BeNil())
+		Expect(get("a", "k1")).To(Equal([]byte("v2")))
+	})
+	It("submits nothing if the update fails", func() {
+		err := db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			if _, err := tx.CreateBucket(ctx, libkv.NewBucketName("a")); err != nil {
+				return err
+			}
+			return errors.New(ctx, "banana")
+		})
+		Expect(err).NotTo(BeNil())
+		err = db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			_, err := tx.Bucket(ctx, libkv.NewBucketName("a"))
+			return err
+		})
+		Expect(errors.Is(err, libkv.BucketNotFoundError)).To(BeTrue())
+	})
+	It("reads buffered writes inside an update", func() {
+		err := db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.CreateBucket(ctx, libkv.NewBucketName("a"))
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(ctx, []byte("k1"), []byte("v1")); err != nil {
+				return err
+			}
+			item, err := bucket.Get(ctx, []byte("k1"))
+			if err != nil {
+				return err
+			}
+			Expect(item.Exists()).To(BeTrue())
+			Expect(item.Value(func(val []byte) error {
+				Expect(val).To(Equal([]byte("v1")))
+				return nil
+			})).To(Succeed())
+			Expect(boltDB.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+				_, err := tx.Bucket(ctx, libkv.NewBucketName("a"))
+				return err
+			})).NotTo(Succeed())
+			return nil
+		})
+		Expect(err).To(BeNil())
+	})
+	It("maps server errors to libkv errors", func() {
+		err := db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			_, err := tx.CreateBucket(ctx, libkv.NewBucketName("a"))
+			return err
+		})
+		Expect(err).To(BeNil())
+		err = db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			_, err := tx.CreateBucket(ctx, libkv.NewBucketName("a"))
+			return err
+		})
+		Expect(errors.Is(err, libkv.BucketAlreadyExistsError)).To(BeTrue())
+		err = db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			return tx.DeleteBucket(ctx, libkv.NewBucketName("b"))
+		})
+		Expect(errors.Is(err, libkv.BucketNotFoundError)).To(BeTrue())
+	})
+	It("rejects writes in a view", func() {
+		err := db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			_, err := tx.CreateBucket(ctx, libkv.NewBucketName("a"))
+			return err
+		})
+		Expect(errors.Is(err, client.ReadOnlyTransactionError)).To(BeTrue())
+	})
+	It("rejects nested transactions", func() {
+		err := db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			return db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+				return nil
+			})
+		})
+		Expect(errors.Is(err, libkv.TransactionAlreadyOpenError)).To(BeTrue())
+	})
+	Context("with token", func() {
+		BeforeEach(func() {
+			opts.Token = "other"
+		})
+		It("fails", func() {
+			err := db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+				_, err := tx.ListBucketNames(ctx)
+				return err
+			})
+			Expect(err).NotTo(BeNil())
+		})
+	})
+	It("returns stats", func() {
+		err := db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.CreateBucket(ctx, libkv.NewBucketName("a"))
+			if err != nil {
+				return err
+			}
+			return bucket.Put(ctx, []byte("k1"), []byte("v1"))
+		})
+		Expect(err).To(BeNil())
+		stats, err := db.StatsDetailed(ctx)
+		Expect(err).To(BeNil())
+		Expect(stats.Backend).To(Equal(client.Backend))
+		Expect(stats.Buckets).To(HaveLen(1))
+		Expect(stats.Buckets[0].KeyCount).To(Equal(int64(1)))
+	})
+})
@@ -0,0 +1,276 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+
+	"github.com/bborbe/boltkv/server"
+)
+
+// ReadOnlyTransactionError is returned for writes inside a View.
+var ReadOnlyTransactionError = errors.New(context.Background(), "transaction is read-only")
+
+func newTx(ctx context.Context, db *db, writable bool) *tx {
+	return &tx{
+		ctx:      ctx,
+		db:       db,
+		writable: writable,
+		buckets:  map[string]*txBucket{},
+		known:    map[string]bool{},
+	}
+}
+
+type tx struct {
+	// ctx is used by iterators, Iterator() does not take a context
+	ctx        context.Context
+	db         *db
+	writable   bool
+	operations []server.Operation
+	// buckets holds the buckets changed in this tx
+	buckets map[string]*txBucket
+	// known caches the server buckets seen to exist
+	known map[string]bool
+	// err is the first failed read of an iterator, returned by View and Update
+	err error
+}
+
+// txBucket holds the buffered writes of a bucket.
+type txBucket struct {
+	// fresh is set if the bucket was created in this tx, the server state is ignored
+	fresh   bool
+	deleted bool
+	// writes maps keys to values, nil for deleted keys
+	writes map[string][]byte
+}
+
+// local reports whether the server state of the bucket is replaced by this tx.
+func (t *txBucket) local() bool {
+	return t.fresh || t.deleted
+}
+
+// fail records err of a read that cannot return it, the first one is kept.
+func (t *tx) fail(err error) {
+	if t.err == nil {
+		t.err = err
+	}
+}
+
+func (t *tx) record(operation server.Operation) error {
+	if !t.writable {
+		return errors.Wrapf(t.ctx, ReadOnlyTransactionError, "%s failed", operation.Type)
+	}
+	t.operations = append(t.operations, operation)
+	return nil
+}
+
+// exists reports whether the bucket exists including the buffered writes.
+func (t *tx) exists(ctx context.Context, name libkv.BucketName) (bool, error) {
+	if state, ok := t.buckets[name.String()]; ok {
+		return !state.deleted, nil
+	}
+	if t.known[name.String()] {
+		return true, nil
+	}
+	err := t.db.do(ctx, http.MethodGet, bucketPath(name, nil), nil, nil, nil)
+	if errors.Is(err, libkv.BucketNotFoundError) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(ctx, err, "get bucket failed")
+	}
+	t.known[name.String()] = true
+	return true, nil
+}
+
+func (t *tx) Bucket(ctx context.Context, name libkv.BucketName) (libkv.Bucket, error) {
+	exists, err := t.exists(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.Wrapf(ctx, libkv.BucketNotFoundError, "bucket %s not found", name)
+	}
+	return &bucket{tx: t, name: bytes.Clone(name)}, nil
+}
+
+func (t *tx) CreateBucket(ctx context.Context, name libkv.BucketName) (libkv.Bucket, error) {
+	exists, err := t.exists(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.Wrapf(ctx, libkv.BucketAlreadyExistsError, "bucket %s exists", name)
+	}
+	err = t.record(server.Operation{Type: server.OperationCreateBucket, Bucket: name})
+	if err != nil {
+		return nil, err
+	}
+	t.buckets[name.String()] = &txBucket{fresh: true, writes: map[string][]byte{}}
+	return &bucket{tx: t, name: bytes.Clone(name)}, nil
+}
+
+func (t *tx) CreateBucketIfNotExists(
+	ctx context.Context,
+	name libkv.BucketName,
+) (libkv.Bucket, error) {
+	exists, err := t.exists(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return &bucket{tx: t, name: bytes.Clone(name)}, nil
+	}
+	return t.CreateBucket(ctx, name)
+}
+
+func (t *tx) DeleteBucket(ctx context.Context, name libkv.BucketName) error {
+	exists, err := t.exists(ctx, name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errors.Wrapf(ctx, libkv.BucketNotFoundError, "bucket %s not found", name)
+	}
+	err = t.record(server.Operation{Type: server.OperationDeleteBucket, Bucket: name})
+	if err != nil {
+		return err
+	}
+	t.buckets[name.String()] = &txBucket{deleted: true}
+	return nil
+}
+
+func (t *tx) ListBucketNames(ctx context.Context) (libkv.BucketNames, error) {
+	var buckets []bucketResponse
+	if err := t.db.do(ctx, http.MethodGet, "/buckets", nil, nil, &buckets); err != nil {
+		return nil, errors.Wrapf(ctx, err, "list buckets failed")
+	}
+	names := map[string]bool{}
+	for _, bucket := range buckets {
+		names[bucket.Bucket] = true
+	}
+	for name, state := range t.buckets {
+		names[name] = !state.deleted
+	}
+	result := libkv.BucketNames{}
+	for name, exists := range names {
+		if exists {
+			result = append(result, libkv.NewBucketName(name))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return bytes.Compare(result[i], result[j]) < 0
+	})
+	return result, nil
+}
+
+// state returns the buffered writes of the bucket, creating them if missing.
+func (t *tx) state(name libkv.BucketName) *txBucket {
+	state, ok := t.buckets[name.String()]
+	if !ok {
+		state = &txBucket{}
+		t.buckets[name.String()] = state
+	}
+	if state.writes == nil {
+		state.writes = map[string][]byte{}
+	}
+	return state
+}
+
+type bucket struct {
+	tx   *tx
+	name libkv.BucketName
+}
+
+func (b *bucket) Put(ctx context.Context, key []byte, value []byte) error {
+	if len(key) == 0 {
+		return errors.Errorf(ctx, "key required")
+	}
+	err := b.tx.record(server.Operation{
+		Type:   server.OperationPut,
+		Bucket: b.name,
+		Key:    bytes.Clone(key),
+		Value:  bytes.Clone(value),
+	})
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		value = []byte{}
+	}
+	b.tx.state(b.name).writes[string(key)] = bytes.Clone(value)
+	return nil
+}
+
+func (b *bucket) Delete(ctx context.Context, key []byte) error {
+	err := b.tx.record(server.Operation{
+		Type:   server.OperationDelete,
+		Bucket: b.name,
+		Key:    bytes.Clone(key),
+	})
+	if err != nil {
+		return err
+	}
+	b.tx.state(b.name).writes[string(key)] = nil
+	return nil
+}
+
+func (b *bucket) Get(ctx context.Context, key []byte) (libkv.Item, error) {
+	if state, ok := b.tx.buckets[b.name.String()]; ok {
+		if value, ok := state.writes[string(key)]; ok || state.local() {
+			return libkv.NewByteItem(key, value), nil
+		}
+	}
+	if len(key) == 0 {
+		return libkv.NewByteItem(key, nil), nil
+	}
+	resp, err := b.tx.db.send(
+		ctx,
+		http.MethodGet,
+		bucketPath(b.name, key),
+		url.Values{"key_encoding": {"hex"}},
+		nil,
+	)
+	if errors.Is(err, libkv.KeyNotFoundError) {
+		return libkv.NewByteItem(key, nil), nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "get failed")
+	}
+	defer resp.Body.Close()
+	value, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "read value failed")
+	}
+	return libkv.NewByteItem(key, value), nil
+}
+
+func (b *bucket) Iterator() libkv.Iterator {
+	return b.iterator(false)
+}
+
+func (b *bucket) IteratorReverse() libkv.Iterator {
+	return b.iterator(true)
+}
+
+func (b *bucket) iterator(reverse bool) libkv.Iterator {
+	state := b.tx.buckets[b.name.String()]
+	if state == nil {
+		return newRemoteIterator(b.tx, b.name, reverse)
+	}
+	var remote libkv.Iterator = emptyIterator{}
+	if !state.local() {
+		remote = newRemoteIterator(b.tx, b.name, reverse)
+	}
+	return newMergeIterator(remote, state.writes, reverse)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/golang/glog"
 
 	"github.com/bborbe/boltkv"
+	"github.com/bborbe/boltkv/cli"
 )
 
 func main() {
@@ -30,16 +31,14 @@ type application struct {
 }
 
 func (a *application) Run(ctx context.Context, sentryClient libsentry.Client) error {
-	db, err := boltkv.OpenDir(ctx, a.DataDir)
+	db, err := cli.OpenDB(ctx, a.DataDir, false)
 	if err != nil {
 		return errors.Wrapf(ctx, err, "open failed")
 	}
+	defer db.Close()
 	bucketName := libkv.BucketName(a.Bucket)
-	err = db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
-		return tx.DeleteBucket(ctx, bucketName)
-	})
-	if err != nil {
-		return errors.Wrapf(ctx, err, "view failed")
+	if err := boltkv.DeleteBucket(ctx, db, bucketName); err != nil {
+		return errors.Wrapf(ctx, err, "delete bucket failed")
 	}
 	glog.V(2).Infof("delete bucket %s completed", bucketName)
 	return nil
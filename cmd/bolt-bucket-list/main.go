@@ -6,16 +6,15 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"os"
 
 	"github.com/bborbe/errors"
-	libkv "github.com/bborbe/kv"
 	libsentry "github.com/bborbe/sentry"
 	"github.com/bborbe/service"
 	"github.com/golang/glog"
 
 	"github.com/bborbe/boltkv"
+	"github.com/bborbe/boltkv/cli"
 )
 
 func main() {
@@ -24,28 +23,31 @@ func main() {
 }
 
 type application struct {
-	SentryDSN   string `required:"false" arg:"sentry-dsn"   env:"SENTRY_DSN"   usage:"SentryDSN"      display:"length"`
+	SentryDSN   string `required:"false" arg:"sentry-dsn"   env:"SENTRY_DSN"   usage:"SentryDSN"                             display:"length"`
 	SentryProxy string `required:"false" arg:"sentry-proxy" env:"SENTRY_PROXY" usage:"Sentry Proxy"`
 	DataDir     string `required:"true"  arg:"datadir"      env:"DATADIR"      usage:"data directory"`
+	Format      string `required:"false" arg:"format"       env:"FORMAT"       usage:"text, json, jsonl, csv, hex or base64" default:"text"`
 }
 
 func (a *application) Run(ctx context.Context, sentryClient libsentry.Client) error {
-	db, err := boltkv.OpenDir(ctx, a.DataDir)
+	format, err := cli.ParseFormat(ctx, a.Format)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "parse format failed")
+	}
+	db, err := cli.OpenDB(ctx, a.DataDir, true)
 	if err != nil {
 		return errors.Wrapf(ctx, err, "open failed")
 	}
-	err = db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
-		bucketNames, err := tx.ListBucketNames(ctx)
-		if err != nil {
-			return errors.Wrapf(ctx, err, "list bucketNames failed")
-		}
-		for _, bucketName := range bucketNames {
-			fmt.Println(bucketName.String())
-		}
-		return nil
-	})
+	defer db.Close()
+	writer, err := cli.NewWriter(ctx, os.Stdout, format, boltkv.EncodingUTF8)
 	if err != nil {
-		return errors.Wrapf(ctx, err, "view failed")
+		return errors.Wrapf(ctx, err, "create writer failed")
+	}
+	if err := boltkv.ListBuckets(ctx, db, writer.Bucket); err != nil {
+		return errors.Wrapf(ctx, err, "list buckets failed")
+	}
+	if err := writer.Close(); err != nil {
+		return errors.Wrapf(ctx, err, "close writer failed")
 	}
 	glog.V(4).Infof("done")
 	return nil
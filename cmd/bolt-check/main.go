@@ -0,0 +1,52 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/bborbe/errors"
+	libsentry "github.com/bborbe/sentry"
+	"github.com/bborbe/service"
+	"github.com/golang/glog"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bborbe/boltkv"
+)
+
+func main() {
+	app := &application{}
+	os.Exit(service.Main(context.Background(), app, &app.SentryDSN, &app.SentryProxy))
+}
+
+type application struct {
+	SentryDSN   string `required:"false" arg:"sentry-dsn"   env:"SENTRY_DSN"   usage:"SentryDSN"      display:"length"`
+	SentryProxy string `required:"false" arg:"sentry-proxy" env:"SENTRY_PROXY" usage:"Sentry Proxy"`
+	DataDir     string `required:"true"  arg:"datadir"      env:"DATADIR"      usage:"data directory"`
+}
+
+func (a *application) Run(ctx context.Context, sentryClient libsentry.Client) error {
+	db, err := boltkv.OpenDir(ctx, a.DataDir, func(opts *bolt.Options) {
+		opts.ReadOnly = true
+	})
+	if err != nil {
+		return errors.Wrapf(ctx, err, "open failed")
+	}
+	defer db.Close()
+	findings, err := boltkv.Check(ctx, db)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "check failed")
+	}
+	for _, finding := range findings {
+		fmt.Println(finding.String())
+	}
+	if findings.Corrupted() {
+		return errors.Errorf(ctx, "database corrupted: %d findings", len(findings))
+	}
+	glog.V(2).Infof("check completed, no corruption found")
+	return nil
+}
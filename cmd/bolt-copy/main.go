@@ -0,0 +1,78 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	libsentry "github.com/bborbe/sentry"
+	"github.com/bborbe/service"
+	"github.com/golang/glog"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bborbe/boltkv"
+)
+
+func main() {
+	app := &application{}
+	os.Exit(service.Main(context.Background(), app, &app.SentryDSN, &app.SentryProxy))
+}
+
+type application struct {
+	SentryDSN     string `required:"false" arg:"sentry-dsn"     env:"SENTRY_DSN"     usage:"SentryDSN"                             display:"length"`
+	SentryProxy   string `required:"false" arg:"sentry-proxy"   env:"SENTRY_PROXY"   usage:"Sentry Proxy"`
+	SourceDataDir string `required:"true"  arg:"source-datadir" env:"SOURCE_DATADIR" usage:"source data directory"`
+	TargetDataDir string `required:"true"  arg:"target-datadir" env:"TARGET_DATADIR" usage:"target data directory"`
+	Buckets       string `required:"false" arg:"buckets"        env:"BUCKETS"        usage:"comma separated buckets, all if empty"`
+	BatchSize     int    `required:"false" arg:"batch-size"     env:"BATCH_SIZE"     usage:"keys per transaction"                  default:"1000"`
+}
+
+func (a *application) Run(ctx context.Context, sentryClient libsentry.Client) error {
+	src, err := boltkv.OpenDir(ctx, a.SourceDataDir, func(opts *bolt.Options) {
+		opts.ReadOnly = true
+	})
+	if err != nil {
+		return errors.Wrapf(ctx, err, "open source failed")
+	}
+	defer src.Close()
+
+	dst, err := boltkv.OpenDir(ctx, a.TargetDataDir)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "open target failed")
+	}
+	defer dst.Close()
+
+	err = boltkv.Copy(ctx, src, dst, boltkv.CopyOptions{
+		Buckets:   a.bucketNames(),
+		BatchSize: a.BatchSize,
+		Progress: func(ctx context.Context, progress boltkv.CopyProgress) {
+			glog.V(2).Infof(
+				"copied %d keys of bucket %s (%d total)",
+				progress.BucketKeys,
+				progress.Bucket,
+				progress.TotalKeys,
+			)
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(ctx, err, "copy failed")
+	}
+	glog.V(2).Infof("copy completed")
+	return nil
+}
+
+func (a *application) bucketNames() []libkv.BucketName {
+	var result []libkv.BucketName
+	for _, name := range strings.Split(a.Buckets, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			result = append(result, libkv.NewBucketName(name))
+		}
+	}
+	return result
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	libsentry "github.com/bborbe/sentry"
+	"github.com/bborbe/service"
+	"github.com/golang/glog"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bborbe/boltkv"
+)
+
+func main() {
+	app := &application{}
+	os.Exit(service.Main(context.Background(), app, &app.SentryDSN, &app.SentryProxy))
+}
+
+type application struct {
+	SentryDSN   string `required:"false" arg:"sentry-dsn"   env:"SENTRY_DSN"   usage:"SentryDSN"                                 display:"length"`
+	SentryProxy string `required:"false" arg:"sentry-proxy" env:"SENTRY_PROXY" usage:"Sentry Proxy"`
+	OldDataDir  string `required:"true"  arg:"old-datadir"  env:"OLD_DATADIR"  usage:"data directory of the old database"`
+	NewDataDir  string `required:"true"  arg:"new-datadir"  env:"NEW_DATADIR"  usage:"data directory of the new database"`
+	Buckets     string `required:"false" arg:"buckets"      env:"BUCKETS"      usage:"comma separated buckets, all if empty"`
+	HashValues  bool   `required:"false" arg:"hash-values"  env:"HASH_VALUES"  usage:"print SHA-256 of values instead of values"`
+	Output      string `required:"false" arg:"output"       env:"OUTPUT"       usage:"output file, stdout if empty"`
+}
+
+func (a *application) Run(ctx context.Context, sentryClient libsentry.Client) error {
+	readOnly := func(opts *bolt.Options) {
+		opts.ReadOnly = true
+	}
+	oldDB, err := boltkv.OpenDir(ctx, a.OldDataDir, readOnly)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "open old failed")
+	}
+	defer oldDB.Close()
+
+	newDB, err := boltkv.OpenDir(ctx, a.NewDataDir, readOnly)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "open new failed")
+	}
+	defer newDB.Close()
+
+	var w io.Writer = os.Stdout
+	if a.Output != "" {
+		file, err := os.Create(a.Output)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "create output failed")
+		}
+		defer file.Close()
+		w = file
+	}
+	result, err := boltkv.WriteDiff(ctx, oldDB, newDB, boltkv.DiffOptions{
+		Buckets:    a.bucketNames(),
+		HashValues: a.HashValues,
+	}, w)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "diff failed")
+	}
+	glog.V(2).Infof(
+		"diff completed: %d added, %d removed, %d changed",
+		result.Added,
+		result.Removed,
+		result.Changed,
+	)
+	return nil
+}
+
+func (a *application) bucketNames() []libkv.BucketName {
+	var result []libkv.BucketName
+	for _, name := range strings.Split(a.Buckets, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			result = append(result, libkv.NewBucketName(name))
+		}
+	}
+	return result
+}
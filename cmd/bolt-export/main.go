@@ -0,0 +1,80 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	libsentry "github.com/bborbe/sentry"
+	"github.com/bborbe/service"
+	"github.com/golang/glog"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bborbe/boltkv"
+)
+
+func main() {
+	app := &application{}
+	os.Exit(service.Main(context.Background(), app, &app.SentryDSN, &app.SentryProxy))
+}
+
+type application struct {
+	SentryDSN   string `required:"false" arg:"sentry-dsn"   env:"SENTRY_DSN"   usage:"SentryDSN"                             display:"length"`
+	SentryProxy string `required:"false" arg:"sentry-proxy" env:"SENTRY_PROXY" usage:"Sentry Proxy"`
+	DataDir     string `required:"true"  arg:"datadir"      env:"DATADIR"      usage:"data directory"`
+	Buckets     string `required:"false" arg:"buckets"      env:"BUCKETS"      usage:"comma separated buckets, all if empty"`
+	Output      string `required:"false" arg:"output"       env:"OUTPUT"       usage:"output file, stdout if empty"`
+}
+
+func (a *application) Run(ctx context.Context, sentryClient libsentry.Client) error {
+	db, err := boltkv.OpenDir(ctx, a.DataDir, func(opts *bolt.Options) {
+		opts.ReadOnly = true
+	})
+	if err != nil {
+		return errors.Wrapf(ctx, err, "open failed")
+	}
+	defer db.Close()
+
+	if a.Output == "" {
+		if err := boltkv.Export(ctx, db, os.Stdout, a.bucketNames()...); err != nil {
+			return errors.Wrapf(ctx, err, "export failed")
+		}
+	} else if err := a.exportFile(ctx, db); err != nil {
+		return err
+	}
+	glog.V(2).Infof("export completed")
+	return nil
+}
+
+// exportFile writes the export to Output, the close error is returned because
+// it can report a failed write.
+func (a *application) exportFile(ctx context.Context, db boltkv.DB) error {
+	file, err := os.Create(a.Output)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "create %s failed", a.Output)
+	}
+	if err := boltkv.Export(ctx, db, file, a.bucketNames()...); err != nil {
+		_ = file.Close()
+		return errors.Wrapf(ctx, err, "export failed")
+	}
+	if err := file.Close(); err != nil {
+		return errors.Wrapf(ctx, err, "close %s failed", a.Output)
+	}
+	return nil
+}
+
+func (a *application) bucketNames() []libkv.BucketName {
+	var result []libkv.BucketName
+	for _, name := range strings.Split(a.Buckets, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			result = append(result, libkv.NewBucketName(name))
+		}
+	}
+	return result
+}
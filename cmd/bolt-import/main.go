@@ -0,0 +1,59 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/bborbe/errors"
+	libsentry "github.com/bborbe/sentry"
+	"github.com/bborbe/service"
+	"github.com/golang/glog"
+
+	"github.com/bborbe/boltkv"
+)
+
+func main() {
+	app := &application{}
+	os.Exit(service.Main(context.Background(), app, &app.SentryDSN, &app.SentryProxy))
+}
+
+type application struct {
+	SentryDSN   string `required:"false" arg:"sentry-dsn"   env:"SENTRY_DSN"   usage:"SentryDSN"                            display:"length"`
+	SentryProxy string `required:"false" arg:"sentry-proxy" env:"SENTRY_PROXY" usage:"Sentry Proxy"`
+	DataDir     string `required:"true"  arg:"datadir"      env:"DATADIR"      usage:"data directory"`
+	Input       string `required:"false" arg:"input"        env:"INPUT"        usage:"input file, stdin if empty"`
+	Mode        string `required:"false" arg:"mode"         env:"MODE"         usage:"merge, overwrite or fail-on-conflict" default:"fail-on-conflict"`
+	BatchSize   int    `required:"false" arg:"batch-size"   env:"BATCH_SIZE"   usage:"records per transaction"              default:"1000"`
+}
+
+func (a *application) Run(ctx context.Context, sentryClient libsentry.Client) error {
+	db, err := boltkv.OpenDir(ctx, a.DataDir)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "open failed")
+	}
+	defer db.Close()
+
+	var r io.Reader = os.Stdin
+	if a.Input != "" {
+		file, err := os.Open(a.Input)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "open %s failed", a.Input)
+		}
+		defer file.Close()
+		r = file
+	}
+	result, err := boltkv.Import(ctx, db, r, boltkv.ImportOptions{
+		Mode:      boltkv.ImportMode(a.Mode),
+		BatchSize: a.BatchSize,
+	})
+	if err != nil {
+		return errors.Wrapf(ctx, err, "import failed")
+	}
+	glog.V(2).Infof("import completed (written %d, skipped %d)", result.Written, result.Skipped)
+	return nil
+}
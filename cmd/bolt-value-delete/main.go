@@ -15,6 +15,7 @@ import (
 	"github.com/golang/glog"
 
 	"github.com/bborbe/boltkv"
+	"github.com/bborbe/boltkv/cli"
 )
 
 func main() {
@@ -23,27 +24,64 @@ func main() {
 }
 
 type application struct {
-	SentryDSN   string `required:"false" arg:"sentry-dsn"   env:"SENTRY_DSN"   usage:"SentryDSN"      display:"length"`
+	SentryDSN   string `required:"false" arg:"sentry-dsn"   env:"SENTRY_DSN"   usage:"SentryDSN"                                                            display:"length"`
 	SentryProxy string `required:"false" arg:"sentry-proxy" env:"SENTRY_PROXY" usage:"Sentry Proxy"`
 	DataDir     string `required:"true"  arg:"datadir"      env:"DATADIR"      usage:"data directory"`
 	Bucket      string `required:"true"  arg:"bucket"       env:"BUCKET"       usage:"bucket name"`
-	Key         string `required:"true"  arg:"key"          env:"KEY"          usage:"key to write"`
+	Key         string `required:"false" arg:"key"          env:"KEY"          usage:"key to delete"`
+	KeyEncoding string `required:"false" arg:"key-encoding" env:"KEY_ENCODING" usage:"utf8, hex or uint64"                                                  default:"utf8"`
+	Prefix      string `required:"false" arg:"prefix"       env:"PREFIX"       usage:"delete keys starting with prefix"`
+	Start       string `required:"false" arg:"start"        env:"START"        usage:"first key to delete (inclusive)"`
+	End         string `required:"false" arg:"end"          env:"END"          usage:"last key to delete (exclusive)"`
+	Regex       string `required:"false" arg:"regex"        env:"REGEX"        usage:"delete keys matching the regular expression"`
+	OlderThan   string `required:"false" arg:"older-than"   env:"OLDER_THAN"   usage:"delete keys with a time prefix older than a duration or RFC3339 time"`
+	TimeFormat  string `required:"false" arg:"time-format"  env:"TIME_FORMAT"  usage:"rfc3339, unix, unixmilli or unixnano"                                 default:"rfc3339"`
+	Limit       int    `required:"false" arg:"limit"        env:"LIMIT"        usage:"maximum number of keys to delete, 0 for all"                          default:"0"`
+	BatchSize   int    `required:"false" arg:"batch-size"   env:"BATCH_SIZE"   usage:"keys per transaction"                                                 default:"1000"`
+	DryRun      bool   `required:"false" arg:"dry-run"      env:"DRY_RUN"      usage:"print the selected keys without deleting"`
+	Format      string `required:"false" arg:"format"       env:"FORMAT"       usage:"text, json, jsonl, csv, hex or base64"                                default:"text"`
 }
 
 func (a *application) Run(ctx context.Context, sentryClient libsentry.Client) error {
-	db, err := boltkv.OpenDir(ctx, a.DataDir)
+	format, err := cli.ParseFormat(ctx, a.Format)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "parse format failed")
+	}
+	keyEncoding, err := boltkv.ParseKeyEncoding(ctx, a.KeyEncoding)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "parse key encoding failed")
+	}
+	timeFormat, err := cli.ParseKeyTimeFormat(ctx, a.TimeFormat)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "parse time format failed")
+	}
+	db, err := cli.OpenDB(ctx, a.DataDir, false)
 	if err != nil {
 		return errors.Wrapf(ctx, err, "open failed")
 	}
-	err = db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
-		bucket, err := tx.CreateBucketIfNotExists(ctx, libkv.BucketName(a.Bucket))
-		if err != nil {
-			return errors.Wrapf(ctx, err, "get bucket failed")
-		}
-		return bucket.Delete(ctx, []byte(a.Key))
-	})
+	defer db.Close()
+	writer, err := cli.NewWriter(ctx, os.Stdout, format, keyEncoding)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "create writer failed")
+	}
+	err = cli.Delete(ctx, db, libkv.BucketName(a.Bucket), cli.DeleteRequest{
+		Key:         a.Key,
+		KeyEncoding: keyEncoding,
+		Prefix:      a.Prefix,
+		Start:       a.Start,
+		End:         a.End,
+		Regex:       a.Regex,
+		OlderThan:   a.OlderThan,
+		TimeFormat:  timeFormat,
+		Limit:       a.Limit,
+		BatchSize:   a.BatchSize,
+		DryRun:      a.DryRun,
+	}, writer)
 	if err != nil {
-		return errors.Wrapf(ctx, err, "view failed")
+		return errors.Wrapf(ctx, err, "delete value failed")
+	}
+	if err := writer.Close(); err != nil {
+		return errors.Wrapf(ctx, err, "close writer failed")
 	}
 	glog.V(4).Infof("done")
 	return nil
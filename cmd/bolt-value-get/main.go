@@ -6,7 +6,6 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"os"
 
 	"github.com/bborbe/errors"
@@ -16,6 +15,7 @@ import (
 	"github.com/golang/glog"
 
 	"github.com/bborbe/boltkv"
+	"github.com/bborbe/boltkv/cli"
 )
 
 func main() {
@@ -24,34 +24,49 @@ func main() {
 }
 
 type application struct {
-	SentryDSN   string `required:"false" arg:"sentry-dsn"   env:"SENTRY_DSN"   usage:"SentryDSN"      display:"length"`
+	SentryDSN   string `required:"false" arg:"sentry-dsn"   env:"SENTRY_DSN"   usage:"SentryDSN"                             display:"length"`
 	SentryProxy string `required:"false" arg:"sentry-proxy" env:"SENTRY_PROXY" usage:"Sentry Proxy"`
 	DataDir     string `required:"true"  arg:"datadir"      env:"DATADIR"      usage:"data directory"`
 	Bucket      string `required:"true"  arg:"bucket"       env:"BUCKET"       usage:"bucket name"`
 	Key         string `required:"true"  arg:"key"          env:"KEY"          usage:"key read"`
+	Format      string `required:"false" arg:"format"       env:"FORMAT"       usage:"text, json, jsonl, csv, hex or base64" default:"text"`
+	KeyEncoding string `required:"false" arg:"key-encoding" env:"KEY_ENCODING" usage:"utf8, hex or uint64"                   default:"utf8"`
 }
 
 func (a *application) Run(ctx context.Context, sentryClient libsentry.Client) error {
-	db, err := boltkv.OpenDir(ctx, a.DataDir)
+	format, err := cli.ParseFormat(ctx, a.Format)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "parse format failed")
+	}
+	keyEncoding, err := boltkv.ParseKeyEncoding(ctx, a.KeyEncoding)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "parse key encoding failed")
+	}
+	key, err := keyEncoding.Decode(ctx, a.Key)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "decode key failed")
+	}
+	db, err := cli.OpenDB(ctx, a.DataDir, true)
 	if err != nil {
 		return errors.Wrapf(ctx, err, "open failed")
 	}
-	err = db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
-		bucket, err := tx.Bucket(ctx, libkv.BucketName(a.Bucket))
-		if err != nil {
-			return errors.Wrapf(ctx, err, "get bucket failed")
-		}
-		v, err := bucket.Get(ctx, []byte(a.Key))
-		if err != nil {
-			return errors.Wrapf(ctx, err, "get key failed")
-		}
-		return v.Value(func(val []byte) error {
-			fmt.Printf("value: %s", string(val))
-			return nil
-		})
-	})
+	defer db.Close()
+	value, err := boltkv.GetValue(ctx, db, libkv.BucketName(a.Bucket), key)
 	if err != nil {
-		return errors.Wrapf(ctx, err, "view failed")
+		return errors.Wrapf(ctx, err, "get value failed")
+	}
+	if value == nil {
+		return errors.Wrapf(ctx, libkv.KeyNotFoundError, "key %s not found", a.Key)
+	}
+	writer, err := cli.NewWriter(ctx, os.Stdout, format, keyEncoding)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "create writer failed")
+	}
+	if err := writer.Value(key, value); err != nil {
+		return errors.Wrapf(ctx, err, "write value failed")
+	}
+	if err := writer.Close(); err != nil {
+		return errors.Wrapf(ctx, err, "close writer failed")
 	}
 	glog.V(4).Infof("done")
 	return nil
@@ -6,7 +6,6 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"os"
 
 	"github.com/bborbe/errors"
@@ -16,6 +15,7 @@ import (
 	"github.com/golang/glog"
 
 	"github.com/bborbe/boltkv"
+	"github.com/bborbe/boltkv/cli"
 )
 
 func main() {
@@ -24,31 +24,52 @@ func main() {
 }
 
 type application struct {
-	SentryDSN   string `required:"false" arg:"sentry-dsn"   env:"SENTRY_DSN"   usage:"SentryDSN"      display:"length"`
+	SentryDSN   string `required:"false" arg:"sentry-dsn"   env:"SENTRY_DSN"   usage:"SentryDSN"                             display:"length"`
 	SentryProxy string `required:"false" arg:"sentry-proxy" env:"SENTRY_PROXY" usage:"Sentry Proxy"`
 	DataDir     string `required:"true"  arg:"datadir"      env:"DATADIR"      usage:"data directory"`
 	Bucket      string `required:"true"  arg:"bucket"       env:"BUCKET"       usage:"bucket name"`
+	Format      string `required:"false" arg:"format"       env:"FORMAT"       usage:"text, json, jsonl, csv, hex or base64" default:"text"`
+	KeyEncoding string `required:"false" arg:"key-encoding" env:"KEY_ENCODING" usage:"utf8, hex or uint64"                   default:"utf8"`
+	Prefix      string `required:"false" arg:"prefix"       env:"PREFIX"       usage:"only keys starting with prefix"`
+	Start       string `required:"false" arg:"start"        env:"START"        usage:"first key (inclusive)"`
+	End         string `required:"false" arg:"end"          env:"END"          usage:"last key (exclusive)"`
+	Limit       int    `required:"false" arg:"limit"        env:"LIMIT"        usage:"maximum number of keys, 0 for all"     default:"0"`
+	Reverse     bool   `required:"false" arg:"reverse"      env:"REVERSE"      usage:"list in reverse key order"`
+	KeysOnly    bool   `required:"false" arg:"keys-only"    env:"KEYS_ONLY"    usage:"print keys without values"`
+	Count       bool   `required:"false" arg:"count"        env:"COUNT"        usage:"print the number of keys only"`
 }
 
 func (a *application) Run(ctx context.Context, sentryClient libsentry.Client) error {
-	db, err := boltkv.OpenDir(ctx, a.DataDir)
+	format, err := cli.ParseFormat(ctx, a.Format)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "parse format failed")
+	}
+	keyEncoding, err := boltkv.ParseKeyEncoding(ctx, a.KeyEncoding)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "parse key encoding failed")
+	}
+	opts, err := cli.ParseListOptions(ctx, keyEncoding, a.Prefix, a.Start, a.End)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "parse list options failed")
+	}
+	opts.Limit = a.Limit
+	opts.Reverse = a.Reverse
+	opts.KeysOnly = a.KeysOnly
+	opts.Count = a.Count
+	db, err := cli.OpenDB(ctx, a.DataDir, true)
 	if err != nil {
 		return errors.Wrapf(ctx, err, "open failed")
 	}
-	err = db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
-		bucket, err := tx.Bucket(ctx, libkv.BucketName(a.Bucket))
-		if err != nil {
-			return errors.Wrapf(ctx, err, "get bucket failed")
-		}
-		return libkv.ForEach(ctx, bucket, func(item libkv.Item) error {
-			return item.Value(func(val []byte) error {
-				fmt.Printf("%s = %s\n", string(item.Key()), string(val))
-				return nil
-			})
-		})
-	})
+	defer db.Close()
+	writer, err := cli.NewWriter(ctx, os.Stdout, format, keyEncoding)
 	if err != nil {
-		return errors.Wrapf(ctx, err, "view failed")
+		return errors.Wrapf(ctx, err, "create writer failed")
+	}
+	if err := cli.List(ctx, db, libkv.BucketName(a.Bucket), opts, writer); err != nil {
+		return errors.Wrapf(ctx, err, "list values failed")
+	}
+	if err := writer.Close(); err != nil {
+		return errors.Wrapf(ctx, err, "close writer failed")
 	}
 	glog.V(4).Infof("done")
 	return nil
@@ -15,6 +15,7 @@ import (
 	"github.com/golang/glog"
 
 	"github.com/bborbe/boltkv"
+	"github.com/bborbe/boltkv/cli"
 )
 
 func main() {
@@ -23,29 +24,44 @@ func main() {
 }
 
 type application struct {
-	SentryDSN   string `required:"false" arg:"sentry-dsn"   env:"SENTRY_DSN"   usage:"SentryDSN"      display:"length"`
-	SentryProxy string `required:"false" arg:"sentry-proxy" env:"SENTRY_PROXY" usage:"Sentry Proxy"`
-	DataDir     string `required:"true"  arg:"datadir"      env:"DATADIR"      usage:"data directory"`
-	Bucket      string `required:"true"  arg:"bucket"       env:"BUCKET"       usage:"bucket name"`
-	Key         string `required:"true"  arg:"key"          env:"KEY"          usage:"key to write"`
-	Value       string `required:"true"  arg:"value"        env:"VALUE"        usage:"value to write"`
+	SentryDSN      string `required:"false" arg:"sentry-dsn"       env:"SENTRY_DSN"       usage:"SentryDSN"                                     display:"length"`
+	SentryProxy    string `required:"false" arg:"sentry-proxy"     env:"SENTRY_PROXY"     usage:"Sentry Proxy"`
+	DataDir        string `required:"true"  arg:"datadir"          env:"DATADIR"          usage:"data directory"`
+	Bucket         string `required:"true"  arg:"bucket"           env:"BUCKET"           usage:"bucket name"`
+	Key            string `required:"false" arg:"key"              env:"KEY"              usage:"key to write"`
+	Value          string `required:"false" arg:"value"            env:"VALUE"            usage:"value to write"`
+	ValueFile      string `required:"false" arg:"value-file"       env:"VALUE_FILE"       usage:"read the value from file"`
+	Stdin          bool   `required:"false" arg:"stdin"            env:"STDIN"            usage:"read the value from stdin"`
+	KeyEncoding    string `required:"false" arg:"key-encoding"     env:"KEY_ENCODING"     usage:"utf8, hex or uint64"                           default:"utf8"`
+	ValueEncoding  string `required:"false" arg:"value-encoding"   env:"VALUE_ENCODING"   usage:"utf8, hex or base64"                           default:"utf8"`
+	NoCreateBucket bool   `required:"false" arg:"no-create-bucket" env:"NO_CREATE_BUCKET" usage:"fail if the bucket is missing"`
+	Bulk           string `required:"false" arg:"bulk"             env:"BULK"             usage:"read key value pairs from stdin, tsv or jsonl"`
+	BatchSize      int    `required:"false" arg:"batch-size"       env:"BATCH_SIZE"       usage:"pairs per transaction in bulk mode"            default:"1000"`
 }
 
 func (a *application) Run(ctx context.Context, sentryClient libsentry.Client) error {
-	db, err := boltkv.OpenDir(ctx, a.DataDir)
+	keyEncoding, err := boltkv.ParseKeyEncoding(ctx, a.KeyEncoding)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "parse key encoding failed")
+	}
+	req, err := cli.ParseSetRequest(ctx, a.Key, keyEncoding, a.ValueEncoding, a.Bulk)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "parse request failed")
+	}
+	req.Input.Value = a.Value
+	req.Input.File = a.ValueFile
+	req.Input.Stdin = a.Stdin
+	req.BatchSize = a.BatchSize
+	req.NoCreateBucket = a.NoCreateBucket
+	db, err := cli.OpenDB(ctx, a.DataDir, false)
 	if err != nil {
 		return errors.Wrapf(ctx, err, "open failed")
 	}
-	err = db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
-		bucket, err := tx.CreateBucketIfNotExists(ctx, libkv.BucketName(a.Bucket))
-		if err != nil {
-			return errors.Wrapf(ctx, err, "get bucket failed")
-		}
-		return bucket.Put(ctx, []byte(a.Key), []byte(a.Value))
-	})
+	defer db.Close()
+	written, err := cli.Set(ctx, db, libkv.BucketName(a.Bucket), req, os.Stdin)
 	if err != nil {
-		return errors.Wrapf(ctx, err, "view failed")
+		return errors.Wrapf(ctx, err, "set value failed")
 	}
-	glog.V(4).Infof("done")
+	glog.V(2).Infof("%d keys written", written)
 	return nil
 }
@@ -0,0 +1,108 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/bborbe/errors"
+	libsentry "github.com/bborbe/sentry"
+	"github.com/bborbe/service"
+	"github.com/golang/glog"
+
+	"github.com/bborbe/boltkv/bench"
+)
+
+func main() {
+	app := &application{}
+	os.Exit(service.Main(context.Background(), app, &app.SentryDSN, &app.SentryProxy))
+}
+
+type application struct {
+	SentryDSN   string  `required:"false" arg:"sentry-dsn"   env:"SENTRY_DSN"   usage:"SentryDSN"                                                     display:"length"`
+	SentryProxy string  `required:"false" arg:"sentry-proxy" env:"SENTRY_PROXY" usage:"Sentry Proxy"`
+	Baseline    string  `required:"true"  arg:"baseline"     env:"BASELINE"     usage:"go test -bench output to compare with"`
+	Input       string  `required:"false" arg:"input"        env:"INPUT"        usage:"go test -bench output to use instead of running the benchmarks"`
+	Package     string  `required:"false" arg:"package"      env:"PACKAGE"      usage:"package of the benchmarks"                                     default:"."`
+	Bench       string  `required:"false" arg:"bench"        env:"BENCH"        usage:"regexp of the benchmarks to run"                               default:"."`
+	Count       int     `required:"false" arg:"count"        env:"COUNT"        usage:"runs of each benchmark, the median is compared"                default:"5"`
+	Benchtime   string  `required:"false" arg:"benchtime"    env:"BENCHTIME"    usage:"run time of each benchmark"                                    default:"1s"`
+	Threshold   float64 `required:"false" arg:"threshold"    env:"THRESHOLD"    usage:"max increase of ns/op and allocs/op in percent before failing" default:"10"`
+	Update      bool    `required:"false" arg:"update"       env:"UPDATE"       usage:"write the results to the baseline instead of comparing"        default:"false"`
+}
+
+func (a *application) Run(ctx context.Context, sentryClient libsentry.Client) error {
+	output, err := a.results(ctx)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "get results failed")
+	}
+	current, err := bench.Parse(ctx, bytes.NewReader(output))
+	if err != nil {
+		return errors.Wrapf(ctx, err, "parse results failed")
+	}
+	if len(current) == 0 {
+		return errors.Errorf(ctx, "no benchmark results found")
+	}
+	if a.Update {
+		if err := os.WriteFile(a.Baseline, output, 0600); err != nil {
+			return errors.Wrapf(ctx, err, "write baseline %s failed", a.Baseline)
+		}
+		glog.V(2).Infof("baseline %s updated with %d benchmarks", a.Baseline, len(current))
+		return nil
+	}
+	file, err := os.Open(a.Baseline)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "open baseline %s failed", a.Baseline)
+	}
+	defer file.Close()
+	baseline, err := bench.Parse(ctx, file)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "parse baseline %s failed", a.Baseline)
+	}
+	comparisons := bench.Compare(baseline, current)
+	if err := comparisons.Write(os.Stdout, a.Threshold); err != nil {
+		return errors.Wrapf(ctx, err, "write report failed")
+	}
+	if regressions := comparisons.Regressions(a.Threshold); len(regressions) > 0 {
+		return errors.Errorf(ctx, "%d benchmarks regressed", len(regressions))
+	}
+	return nil
+}
+
+// results reads Input or runs the benchmarks and returns the go test output.
+func (a *application) results(ctx context.Context) ([]byte, error) {
+	if a.Input != "" {
+		return os.ReadFile(a.Input)
+	}
+	// #nosec G204 -- arguments are passed to go test by the user
+	cmd := exec.CommandContext(
+		ctx,
+		"go",
+		"test",
+		"-run=^$",
+		"-bench="+a.Bench,
+		"-benchmem",
+		"-count="+strconv.Itoa(a.Count),
+		"-benchtime="+a.Benchtime,
+		a.Package,
+	)
+	cmd.Stderr = os.Stderr
+	glog.V(2).Infof("run %s", cmd.String())
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "go test failed: %s", lastLine(output))
+	}
+	return output, nil
+}
+
+// lastLine returns the last non-empty line of output for error messages.
+func lastLine(output []byte) string {
+	lines := bytes.Split(bytes.TrimSpace(output), []byte("\n"))
+	return string(lines[len(lines)-1])
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bborbe/errors"
+	libsentry "github.com/bborbe/sentry"
+	"github.com/bborbe/service"
+
+	"github.com/bborbe/boltkv/crashtest"
+)
+
+func main() {
+	if crashtest.IsWorker() {
+		if err := crashtest.RunWorker(context.Background(), os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	app := &application{}
+	os.Exit(service.Main(context.Background(), app, &app.SentryDSN, &app.SentryProxy))
+}
+
+type application struct {
+	SentryDSN     string        `required:"false" arg:"sentry-dsn"      env:"SENTRY_DSN"      usage:"SentryDSN"                                   display:"length"`
+	SentryProxy   string        `required:"false" arg:"sentry-proxy"    env:"SENTRY_PROXY"    usage:"Sentry Proxy"`
+	DataDir       string        `required:"true"  arg:"datadir"         env:"DATADIR"         usage:"data directory of the test database"`
+	NoSync        bool          `required:"false" arg:"nosync"          env:"NOSYNC"          usage:"open the database with NoSync in the worker" default:"false"`
+	Runs          int           `required:"false" arg:"runs"            env:"RUNS"            usage:"number of workers to kill"                   default:"10"`
+	MaxKillDelay  time.Duration `required:"false" arg:"max-kill-delay"  env:"MAX_KILL_DELAY"  usage:"max time a worker runs before it is killed"  default:"500ms"`
+	KeysPerUpdate int           `required:"false" arg:"keys-per-update" env:"KEYS_PER_UPDATE" usage:"keys written by each update"                 default:"8"`
+	Seed          int64         `required:"false" arg:"seed"            env:"SEED"            usage:"seed of the kill delays, 0 picks one"        default:"0"`
+}
+
+func (a *application) Run(ctx context.Context, sentryClient libsentry.Client) error {
+	if err := os.MkdirAll(a.DataDir, 0700); err != nil {
+		return errors.Wrapf(ctx, err, "create %s failed", a.DataDir)
+	}
+	report, err := crashtest.Run(ctx, crashtest.Options{
+		Path:          filepath.Join(a.DataDir, "bolt.db"),
+		NoSync:        a.NoSync,
+		Runs:          a.Runs,
+		MaxKillDelay:  a.MaxKillDelay,
+		KeysPerUpdate: a.KeysPerUpdate,
+		Seed:          a.Seed,
+	})
+	if report != nil {
+		fmt.Printf(
+			"seed %d: %d runs, %d updates acknowledged, %d committed, %d lost\n",
+			report.Seed,
+			report.Runs,
+			report.Acknowledged,
+			report.Committed,
+			report.Lost,
+		)
+	}
+	if err != nil {
+		return errors.Wrapf(ctx, err, "crash test failed")
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"net"
+	"os"
+
+	"github.com/bborbe/errors"
+	libsentry "github.com/bborbe/sentry"
+	"github.com/bborbe/service"
+	"github.com/golang/glog"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bborbe/boltkv"
+	"github.com/bborbe/boltkv/resp"
+)
+
+func main() {
+	app := &application{}
+	os.Exit(service.Main(context.Background(), app, &app.SentryDSN, &app.SentryProxy))
+}
+
+type application struct {
+	SentryDSN   string `required:"false" arg:"sentry-dsn"   env:"SENTRY_DSN"   usage:"SentryDSN"                          display:"length"`
+	SentryProxy string `required:"false" arg:"sentry-proxy" env:"SENTRY_PROXY" usage:"Sentry Proxy"`
+	DataDir     string `required:"true"  arg:"datadir"      env:"DATADIR"      usage:"data directory"`
+	Listen      string `required:"false" arg:"listen"       env:"LISTEN"       usage:"address to listen on"               default:"localhost:6380"`
+	Bucket      string `required:"false" arg:"bucket"       env:"BUCKET"       usage:"bucket selected by new connections" default:"0"`
+	ReadOnly    bool   `required:"false" arg:"readonly"     env:"READONLY"     usage:"open read-only, writes fail"        default:"false"`
+}
+
+func (a *application) Run(ctx context.Context, sentryClient libsentry.Client) error {
+	db, err := boltkv.OpenDir(ctx, a.DataDir, func(opts *bolt.Options) {
+		opts.ReadOnly = a.ReadOnly
+	})
+	if err != nil {
+		return errors.Wrapf(ctx, err, "open failed")
+	}
+	defer db.Close()
+
+	listener, err := net.Listen("tcp", a.Listen)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "listen on %s failed", a.Listen)
+	}
+	glog.V(2).Infof("listen on %s", listener.Addr())
+	if err := resp.NewServer(db, resp.Options{Bucket: a.Bucket}).Serve(ctx, listener); err != nil {
+		return errors.Wrapf(ctx, err, "serve failed")
+	}
+	glog.V(2).Infof("server stopped")
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/bborbe/errors"
+	libsentry "github.com/bborbe/sentry"
+	"github.com/bborbe/service"
+	"github.com/golang/glog"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bborbe/boltkv"
+	"github.com/bborbe/boltkv/server"
+)
+
+func main() {
+	app := &application{}
+	os.Exit(service.Main(context.Background(), app, &app.SentryDSN, &app.SentryProxy))
+}
+
+type application struct {
+	SentryDSN       string `required:"false" arg:"sentry-dsn"        env:"SENTRY_DSN"        usage:"SentryDSN"                         display:"length"`
+	SentryProxy     string `required:"false" arg:"sentry-proxy"      env:"SENTRY_PROXY"      usage:"Sentry Proxy"`
+	DataDir         string `required:"true"  arg:"datadir"           env:"DATADIR"           usage:"data directory"`
+	Listen          string `required:"false" arg:"listen"            env:"LISTEN"            usage:"address to listen on"              default:":9090"`
+	Token           string `required:"false" arg:"token"             env:"TOKEN"             usage:"bearer token, empty disables auth" display:"length"`
+	MaxRequestBytes int64  `required:"false" arg:"max-request-bytes" env:"MAX_REQUEST_BYTES" usage:"max request body size"             default:"1048576"`
+	ReadOnly        bool   `required:"false" arg:"readonly"          env:"READONLY"          usage:"open read-only and reject writes"  default:"false"`
+}
+
+func (a *application) Run(ctx context.Context, sentryClient libsentry.Client) error {
+	db, err := boltkv.OpenDir(ctx, a.DataDir, func(opts *bolt.Options) {
+		opts.ReadOnly = a.ReadOnly
+	})
+	if err != nil {
+		return errors.Wrapf(ctx, err, "open failed")
+	}
+	defer db.Close()
+
+	httpServer := &http.Server{
+		Addr: a.Listen,
+		Handler: server.NewHandler(db, server.Options{
+			Token:           a.Token,
+			MaxRequestBytes: a.MaxRequestBytes,
+			ReadOnly:        a.ReadOnly,
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errs := make(chan error, 1)
+	go func() {
+		glog.V(2).Infof("listen on %s", a.Listen)
+		errs <- httpServer.ListenAndServe()
+	}()
+	select {
+	case err := <-errs:
+		return errors.Wrapf(ctx, err, "listen on %s failed", a.Listen)
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return errors.Wrapf(ctx, err, "shutdown failed")
+	}
+	glog.V(2).Infof("server stopped")
+	return nil
+}
@@ -0,0 +1,231 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package crashtest checks that a boltkv database stays consistent when the
+// process writing it is killed. A worker process commits numbered Updates and
+// acknowledges each on stdout; Run kills it at random points, reopens the file
+// and verifies that every acknowledged Update is present and no partial one
+// is visible.
+package crashtest
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"math/rand"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bborbe/errors"
+	"github.com/golang/glog"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bborbe/boltkv"
+)
+
+const (
+	// DefaultRuns is the number of worker processes killed by Run.
+	DefaultRuns = 10
+	// DefaultMaxKillDelay bounds the random lifetime of a worker.
+	DefaultMaxKillDelay = 500 * time.Millisecond
+	// DefaultKeysPerUpdate is the number of keys written by each Update.
+	DefaultKeysPerUpdate = 8
+)
+
+// ackPrefix starts the stdout line a worker writes after each committed Update.
+const ackPrefix = "ack "
+
+// Options configures Run.
+type Options struct {
+	// Path of the database, kept over all runs.
+	Path string
+	// NoSync opens the database in the worker with bolt's NoSync.
+	NoSync bool
+	// Runs is the number of workers to kill, defaults to DefaultRuns.
+	Runs int
+	// MaxKillDelay bounds the random time a worker runs before it is killed,
+	// defaults to DefaultMaxKillDelay.
+	MaxKillDelay time.Duration
+	// KeysPerUpdate is the number of keys written by each Update, defaults to
+	// DefaultKeysPerUpdate.
+	KeysPerUpdate int
+	// Seed of the kill delays, zero picks one from the clock.
+	Seed int64
+	// Command starts a worker, a program calling RunWorker if IsWorker returns
+	// true. Defaults to the current executable without arguments.
+	Command []string
+}
+
+// Report is the result of Run.
+type Report struct {
+	// Seed of the kill delays, pass it in Options to repeat the runs.
+	Seed int64
+	// Runs is the number of killed workers.
+	Runs int
+	// Acknowledged is the number of Updates the workers reported as committed.
+	Acknowledged int64
+	// Committed is the number of Updates found in the database after the last run.
+	Committed int64
+	// Lost is the number of acknowledged Updates missing after a kill. Run
+	// fails on lost Updates unless NoSync is set.
+	Lost int64
+}
+
+// Run kills opts.Runs workers at random points, verifies the database after
+// each kill and returns an error on the first violation.
+func Run(ctx context.Context, opts Options) (*Report, error) {
+	opts = withDefaults(opts)
+	if opts.Path == "" {
+		return nil, errors.Errorf(ctx, "path missing")
+	}
+	command := opts.Command
+	if len(command) == 0 {
+		executable, err := os.Executable()
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "get executable failed")
+		}
+		command = []string{executable}
+	}
+	report := &Report{Seed: opts.Seed}
+	if report.Seed == 0 {
+		report.Seed = time.Now().UnixNano()
+	}
+	random := rand.New(rand.NewSource(report.Seed)) // #nosec G404 -- reproducible test delays
+	var committed int64
+	for report.Runs < opts.Runs {
+		delay := time.Duration(random.Int63n(int64(opts.MaxKillDelay) + 1))
+		acknowledged, err := runWorker(ctx, command, opts, delay)
+		if err != nil {
+			return report, errors.Wrapf(ctx, err, "run %d failed", report.Runs)
+		}
+		report.Runs++
+		if acknowledged > committed {
+			report.Acknowledged += acknowledged - committed
+		}
+		committed, err = verifyFile(ctx, opts.Path, opts.KeysPerUpdate)
+		if err != nil {
+			return report, errors.Wrapf(ctx, err, "verify after run %d failed", report.Runs)
+		}
+		report.Committed = committed
+		if lost := acknowledged - committed; lost > 0 {
+			if !opts.NoSync {
+				return report, errors.Errorf(
+					ctx,
+					"%d acknowledged updates lost after run %d, seed %d",
+					lost,
+					report.Runs,
+					report.Seed,
+				)
+			}
+			report.Lost += lost
+		}
+		glog.V(2).Infof(
+			"run %d killed after %v with %d updates acknowledged and %d committed",
+			report.Runs,
+			delay,
+			acknowledged,
+			committed,
+		)
+	}
+	return report, nil
+}
+
+func withDefaults(opts Options) Options {
+	if opts.Runs <= 0 {
+		opts.Runs = DefaultRuns
+	}
+	if opts.MaxKillDelay <= 0 {
+		opts.MaxKillDelay = DefaultMaxKillDelay
+	}
+	if opts.KeysPerUpdate <= 0 {
+		opts.KeysPerUpdate = DefaultKeysPerUpdate
+	}
+	return opts
+}
+
+// runWorker starts a worker, kills it after delay and returns the number of
+// Updates it acknowledged in total.
+func runWorker(
+	ctx context.Context,
+	command []string,
+	opts Options,
+	delay time.Duration,
+) (int64, error) {
+	// #nosec G204 -- the command is configured by the caller
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Env = append(os.Environ(), workerEnv(opts)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return 0, errors.Wrapf(ctx, err, "pipe stdout failed")
+	}
+	if err := cmd.Start(); err != nil {
+		return 0, errors.Wrapf(ctx, err, "start worker failed")
+	}
+
+	var acknowledged int64
+	var wg sync.WaitGroup
+	wg.Add(1)
+	exited := make(chan struct{})
+	go func() {
+		defer wg.Done()
+		defer close(exited)
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !strings.HasPrefix(line, ackPrefix) {
+				continue
+			}
+			count, err := strconv.ParseInt(strings.TrimPrefix(line, ackPrefix), 10, 64)
+			if err == nil && count > acknowledged {
+				acknowledged = count
+			}
+		}
+	}()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		_ = cmd.Process.Kill()
+		wg.Wait()
+		_ = cmd.Wait()
+		return 0, errors.Wrapf(ctx, ctx.Err(), "run canceled")
+	case <-exited:
+		// stdout closed before the kill, the worker failed
+		err := cmd.Wait()
+		return 0, errors.Errorf(ctx, "worker exited early: %v: %s", err, stderr.String())
+	case <-timer.C:
+	}
+	if err := cmd.Process.Kill(); err != nil {
+		return 0, errors.Wrapf(ctx, err, "kill worker failed")
+	}
+	wg.Wait()
+	_ = cmd.Wait()
+	return acknowledged, nil
+}
+
+// verifyFile reopens the database like after a restart and verifies it.
+func verifyFile(ctx context.Context, path string, keysPerUpdate int) (int64, error) {
+	db, err := boltkv.OpenFile(ctx, path, func(opts *bolt.Options) {
+		opts.Timeout = time.Second
+	})
+	if err != nil {
+		return 0, errors.Wrapf(ctx, err, "reopen failed")
+	}
+	defer db.Close()
+	committed, findings, err := Verify(ctx, db, keysPerUpdate)
+	if err != nil {
+		return 0, errors.Wrapf(ctx, err, "verify failed")
+	}
+	if findings.Corrupted() {
+		return 0, errors.Errorf(ctx, "database inconsistent: %v", findings)
+	}
+	return committed, nil
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crashtest_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv/crashtest"
+)
+
+// TestMain runs the test binary as worker if started by crashtest.Run.
+func TestMain(m *testing.M) {
+	if crashtest.IsWorker() {
+		if err := crashtest.RunWorker(context.Background(), os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestCrashtest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Crashtest Suite")
+}
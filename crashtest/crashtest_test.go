@@ -0,0 +1,58 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crashtest_test
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv/crashtest"
+)
+
+var _ = Describe("Run", func() {
+	var ctx context.Context
+	var opts crashtest.Options
+	BeforeEach(func() {
+		ctx = context.Background()
+		opts = crashtest.Options{
+			Path:          filepath.Join(GinkgoT().TempDir(), "crash.db"),
+			Runs:          5,
+			MaxKillDelay:  200 * time.Millisecond,
+			KeysPerUpdate: 4,
+			Seed:          42,
+		}
+	})
+	It("keeps all acknowledged updates", func() {
+		report, err := crashtest.Run(ctx, opts)
+		Expect(err).To(BeNil())
+		Expect(report.Runs).To(Equal(5))
+		Expect(report.Seed).To(Equal(int64(42)))
+		Expect(report.Lost).To(BeZero())
+		Expect(report.Committed).To(BeNumerically(">=", report.Acknowledged))
+	})
+	It("runs with NoSync", func() {
+		opts.NoSync = true
+		report, err := crashtest.Run(ctx, opts)
+		Expect(err).To(BeNil())
+		Expect(report.Runs).To(Equal(5))
+		// a killed process leaves its writes in the page cache
+		Expect(report.Lost).To(BeZero())
+	})
+	It("fails if the worker exits early", func() {
+		opts.Command = []string{"false"}
+		opts.MaxKillDelay = time.Minute
+		_, err := crashtest.Run(ctx, opts)
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("worker exited early"))
+	})
+	It("fails without path", func() {
+		_, err := crashtest.Run(ctx, crashtest.Options{})
+		Expect(err).NotTo(BeNil())
+	})
+})
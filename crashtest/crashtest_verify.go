@@ -0,0 +1,150 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crashtest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+
+	"github.com/bborbe/boltkv"
+)
+
+// InvariantName is the CheckFinding.Check value of workload violations.
+const InvariantName = "crashtest"
+
+// BucketName is the bucket written by Workload.
+var BucketName = libkv.NewBucketName("crashtest")
+
+// committedKey holds the number of committed Updates, it sorts after all
+// update keys.
+var committedKey = []byte("committed")
+
+// updateKey returns the key i of update, sorted by update.
+func updateKey(update int64, i int) []byte {
+	return []byte(fmt.Sprintf("%016d/%04d", update, i))
+}
+
+func parseUpdateKey(key []byte) (int64, bool) {
+	pos := bytes.IndexByte(key, '/')
+	if pos < 0 {
+		return 0, false
+	}
+	update, err := strconv.ParseInt(string(key[:pos]), 10, 64)
+	return update, err == nil
+}
+
+func getCommitted(ctx context.Context, bucket libkv.Bucket) (int64, error) {
+	item, err := bucket.Get(ctx, committedKey)
+	if err != nil {
+		return 0, errors.Wrapf(ctx, err, "get committed failed")
+	}
+	if !item.Exists() {
+		return 0, nil
+	}
+	var committed int64
+	err = item.Value(func(value []byte) error {
+		committed, err = strconv.ParseInt(string(value), 10, 64)
+		return err
+	})
+	if err != nil {
+		return 0, errors.Wrapf(ctx, err, "parse committed failed")
+	}
+	return committed, nil
+}
+
+// Verify runs the page-level check of db and the invariant of the Workload:
+// all committed Updates are complete and no other Update is visible. It
+// returns the number of committed Updates.
+func Verify(
+	ctx context.Context,
+	db boltkv.DB,
+	keysPerUpdate int,
+) (int64, boltkv.CheckFindings, error) {
+	var committed int64
+	invariant := boltkv.NewInvariant(
+		InvariantName,
+		func(ctx context.Context, tx libkv.Tx) ([]string, error) {
+			var messages []string
+			var err error
+			committed, messages, err = checkWorkload(ctx, tx, keysPerUpdate)
+			return messages, err
+		},
+	)
+	findings, err := boltkv.NewChecker(db, invariant).Check(ctx)
+	if err != nil {
+		return 0, nil, errors.Wrapf(ctx, err, "check failed")
+	}
+	return committed, findings, nil
+}
+
+func checkWorkload(
+	ctx context.Context,
+	tx libkv.Tx,
+	keysPerUpdate int,
+) (int64, []string, error) {
+	bucket, err := tx.Bucket(ctx, BucketName)
+	if errors.Is(err, libkv.BucketNotFoundError) {
+		return 0, nil, nil
+	}
+	if err != nil {
+		return 0, nil, errors.Wrapf(ctx, err, "get bucket failed")
+	}
+	committed, err := getCommitted(ctx, bucket)
+	if err != nil {
+		return 0, nil, err
+	}
+	var messages []string
+	counts := make(map[int64]int)
+	err = libkv.ForEach(ctx, bucket, func(item libkv.Item) error {
+		if bytes.Equal(item.Key(), committedKey) {
+			return nil
+		}
+		update, ok := parseUpdateKey(item.Key())
+		if !ok {
+			messages = append(messages, fmt.Sprintf("unexpected key %q", item.Key()))
+			return nil
+		}
+		counts[update]++
+		return item.Value(func(value []byte) error {
+			if string(value) != strconv.FormatInt(update, 10) {
+				messages = append(
+					messages,
+					fmt.Sprintf("key %q has value %q of another update", item.Key(), value),
+				)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return 0, nil, errors.Wrapf(ctx, err, "read bucket failed")
+	}
+	for update := int64(0); update < committed; update++ {
+		if count := counts[update]; count != keysPerUpdate {
+			messages = append(
+				messages,
+				fmt.Sprintf("update %d has %d of %d keys", update, count, keysPerUpdate),
+			)
+		}
+		delete(counts, update)
+	}
+	visible := make([]int64, 0, len(counts))
+	for update := range counts {
+		visible = append(visible, update)
+	}
+	sort.Slice(visible, func(i, j int) bool { return visible[i] < visible[j] })
+	for _, update := range visible {
+		messages = append(
+			messages,
+			fmt.Sprintf("update %d is visible but %d updates are committed", update, committed),
+		)
+	}
+	return committed, messages, nil
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crashtest_test
+
+import (
+	"bytes"
+	"context"
+
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+	"github.com/bborbe/boltkv/crashtest"
+)
+
+var _ = Describe("Verify", func() {
+	var ctx context.Context
+	var db boltkv.DB
+	BeforeEach(func() {
+		ctx = context.Background()
+		var err error
+		db, err = boltkv.OpenTempWithOptions(ctx, boltkv.TempOptions{
+			Dir:           GinkgoT().TempDir(),
+			RemoveOnClose: true,
+		})
+		Expect(err).To(BeNil())
+		DeferCleanup(db.Close)
+	})
+	workload := func(updates int64) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		ack := &cancelWriter{cancel: cancel, limit: updates}
+		Expect(crashtest.Workload(ctx, db, 3, ack)).To(BeNil())
+	}
+	update := func(fn func(bucket libkv.Bucket) error) {
+		Expect(db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.Bucket(ctx, crashtest.BucketName)
+			if err != nil {
+				return err
+			}
+			return fn(bucket)
+		})).To(BeNil())
+	}
+	It("accepts an empty database", func() {
+		committed, findings, err := crashtest.Verify(ctx, db, 3)
+		Expect(err).To(BeNil())
+		Expect(committed).To(BeZero())
+		Expect(findings).To(BeEmpty())
+	})
+	It("accepts complete updates", func() {
+		workload(5)
+		committed, findings, err := crashtest.Verify(ctx, db, 3)
+		Expect(err).To(BeNil())
+		Expect(committed).To(Equal(int64(5)))
+		Expect(findings).To(BeEmpty())
+	})
+	It("continues after the committed updates", func() {
+		workload(2)
+		workload(3)
+		committed, findings, err := crashtest.Verify(ctx, db, 3)
+		Expect(err).To(BeNil())
+		Expect(committed).To(Equal(int64(5)))
+		Expect(findings).To(BeEmpty())
+	})
+	It("reports a partial update", func() {
+		workload(2)
+		update(func(bucket libkv.Bucket) error {
+			return bucket.Delete(ctx, []byte("0000000000000001/0002"))
+		})
+		_, findings, err := crashtest.Verify(ctx, db, 3)
+		Expect(err).To(BeNil())
+		Expect(findings).To(Equal(boltkv.CheckFindings{{
+			Check:   crashtest.InvariantName,
+			Message: "update 1 has 2 of 3 keys",
+		}}))
+	})
+	It("reports an uncommitted update", func() {
+		workload(2)
+		update(func(bucket libkv.Bucket) error {
+			return bucket.Put(ctx, []byte("0000000000000002/0000"), []byte("2"))
+		})
+		_, findings, err := crashtest.Verify(ctx, db, 3)
+		Expect(err).To(BeNil())
+		Expect(findings).To(Equal(boltkv.CheckFindings{{
+			Check:   crashtest.InvariantName,
+			Message: "update 2 is visible but 2 updates are committed",
+		}}))
+	})
+})
+
+// cancelWriter cancels the workload after limit acknowledged updates.
+type cancelWriter struct {
+	cancel context.CancelFunc
+	limit  int64
+	buffer bytes.Buffer
+	count  int64
+}
+
+func (c *cancelWriter) Write(p []byte) (int, error) {
+	c.count++
+	if c.count >= c.limit {
+		c.cancel()
+	}
+	return c.buffer.Write(p)
+}
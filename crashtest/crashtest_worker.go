@@ -0,0 +1,117 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crashtest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bborbe/boltkv"
+)
+
+// Environment of a worker started by Run.
+const (
+	PathEnv          = "BOLTKV_CRASHTEST_PATH"
+	NoSyncEnv        = "BOLTKV_CRASHTEST_NOSYNC"
+	KeysPerUpdateEnv = "BOLTKV_CRASHTEST_KEYS_PER_UPDATE"
+)
+
+func workerEnv(opts Options) []string {
+	return []string{
+		PathEnv + "=" + opts.Path,
+		NoSyncEnv + "=" + strconv.FormatBool(opts.NoSync),
+		KeysPerUpdateEnv + "=" + strconv.Itoa(opts.KeysPerUpdate),
+	}
+}
+
+// IsWorker returns true if the process was started as worker by Run.
+func IsWorker() bool {
+	return os.Getenv(PathEnv) != ""
+}
+
+// RunWorker opens the database configured by the environment and commits
+// Updates until ctx is canceled or the process is killed. After each commit
+// it writes the number of committed Updates to ack.
+func RunWorker(ctx context.Context, ack io.Writer) error {
+	noSync, err := strconv.ParseBool(os.Getenv(NoSyncEnv))
+	if err != nil {
+		return errors.Wrapf(ctx, err, "parse %s failed", NoSyncEnv)
+	}
+	keysPerUpdate, err := strconv.Atoi(os.Getenv(KeysPerUpdateEnv))
+	if err != nil {
+		return errors.Wrapf(ctx, err, "parse %s failed", KeysPerUpdateEnv)
+	}
+	db, err := boltkv.OpenFile(ctx, os.Getenv(PathEnv), func(opts *bolt.Options) {
+		opts.NoSync = noSync
+	})
+	if err != nil {
+		return errors.Wrapf(ctx, err, "open failed")
+	}
+	defer db.Close()
+	return Workload(ctx, db, keysPerUpdate, ack)
+}
+
+// Workload commits Updates writing keysPerUpdate keys each and the number of
+// committed Updates, continuing after the Updates already in db.
+func Workload(ctx context.Context, db libkv.DB, keysPerUpdate int, ack io.Writer) error {
+	committed, err := readCommitted(ctx, db)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "read committed failed")
+	}
+	for ctx.Err() == nil {
+		update := committed
+		err := db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.CreateBucketIfNotExists(ctx, BucketName)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "create bucket failed")
+			}
+			value := []byte(strconv.FormatInt(update, 10))
+			for i := 0; i < keysPerUpdate; i++ {
+				if err := bucket.Put(ctx, updateKey(update, i), value); err != nil {
+					return errors.Wrapf(ctx, err, "put failed")
+				}
+			}
+			count := []byte(strconv.FormatInt(update+1, 10))
+			if err := bucket.Put(ctx, committedKey, count); err != nil {
+				return errors.Wrapf(ctx, err, "put committed failed")
+			}
+			return nil
+		})
+		if err != nil {
+			return errors.Wrapf(ctx, err, "update %d failed", update)
+		}
+		committed = update + 1
+		if _, err := fmt.Fprintf(ack, "%s%d\n", ackPrefix, committed); err != nil {
+			return errors.Wrapf(ctx, err, "write ack failed")
+		}
+	}
+	return nil
+}
+
+func readCommitted(ctx context.Context, db libkv.DB) (int64, error) {
+	var committed int64
+	err := db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		bucket, err := tx.Bucket(ctx, BucketName)
+		if errors.Is(err, libkv.BucketNotFoundError) {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(ctx, err, "get bucket failed")
+		}
+		committed, err = getCommitted(ctx, bucket)
+		return err
+	})
+	if err != nil {
+		return 0, errors.Wrapf(ctx, err, "view failed")
+	}
+	return committed, nil
+}
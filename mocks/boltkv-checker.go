@@ -0,0 +1,156 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bborbe/boltkv"
+)
+
+type BoltkvChecker struct {
+	CheckStub        func(context.Context) (boltkv.CheckFindings, error)
+	checkMutex       sync.RWMutex
+	checkArgsForCall []struct {
+		arg1 context.Context
+	}
+	checkReturns struct {
+		result1 boltkv.CheckFindings
+		result2 error
+	}
+	checkReturnsOnCall map[int]struct {
+		result1 boltkv.CheckFindings
+		result2 error
+	}
+	RegisterStub        func(...boltkv.Invariant)
+	registerMutex       sync.RWMutex
+	registerArgsForCall []struct {
+		arg1 []boltkv.Invariant
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *BoltkvChecker) Check(arg1 context.Context) (boltkv.CheckFindings, error) {
+	fake.checkMutex.Lock()
+	ret, specificReturn := fake.checkReturnsOnCall[len(fake.checkArgsForCall)]
+	fake.checkArgsForCall = append(fake.checkArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.CheckStub
+	fakeReturns := fake.checkReturns
+	fake.recordInvocation("Check", []interface{}{arg1})
+	fake.checkMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *BoltkvChecker) CheckCallCount() int {
+	fake.checkMutex.RLock()
+	defer fake.checkMutex.RUnlock()
+	return len(fake.checkArgsForCall)
+}
+
+func (fake *BoltkvChecker) CheckCalls(stub func(context.Context) (boltkv.CheckFindings, error)) {
+	fake.checkMutex.Lock()
+	defer fake.checkMutex.Unlock()
+	fake.CheckStub = stub
+}
+
+func (fake *BoltkvChecker) CheckArgsForCall(i int) context.Context {
+	fake.checkMutex.RLock()
+	defer fake.checkMutex.RUnlock()
+	argsForCall := fake.checkArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *BoltkvChecker) CheckReturns(result1 boltkv.CheckFindings, result2 error) {
+	fake.checkMutex.Lock()
+	defer fake.checkMutex.Unlock()
+	fake.CheckStub = nil
+	fake.checkReturns = struct {
+		result1 boltkv.CheckFindings
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *BoltkvChecker) CheckReturnsOnCall(i int, result1 boltkv.CheckFindings, result2 error) {
+	fake.checkMutex.Lock()
+	defer fake.checkMutex.Unlock()
+	fake.CheckStub = nil
+	if fake.checkReturnsOnCall == nil {
+		fake.checkReturnsOnCall = make(map[int]struct {
+			result1 boltkv.CheckFindings
+			result2 error
+		})
+	}
+	fake.checkReturnsOnCall[i] = struct {
+		result1 boltkv.CheckFindings
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *BoltkvChecker) Register(arg1 ...boltkv.Invariant) {
+	fake.registerMutex.Lock()
+	fake.registerArgsForCall = append(fake.registerArgsForCall, struct {
+		arg1 []boltkv.Invariant
+	}{arg1})
+	stub := fake.RegisterStub
+	fake.recordInvocation("Register", []interface{}{arg1})
+	fake.registerMutex.Unlock()
+	if stub != nil {
+		fake.RegisterStub(arg1...)
+	}
+}
+
+func (fake *BoltkvChecker) RegisterCallCount() int {
+	fake.registerMutex.RLock()
+	defer fake.registerMutex.RUnlock()
+	return len(fake.registerArgsForCall)
+}
+
+func (fake *BoltkvChecker) RegisterCalls(stub func(...boltkv.Invariant)) {
+	fake.registerMutex.Lock()
+	defer fake.registerMutex.Unlock()
+	fake.RegisterStub = stub
+}
+
+func (fake *BoltkvChecker) RegisterArgsForCall(i int) []boltkv.Invariant {
+	fake.registerMutex.RLock()
+	defer fake.registerMutex.RUnlock()
+	argsForCall := fake.registerArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *BoltkvChecker) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.checkMutex.RLock()
+	defer fake.checkMutex.RUnlock()
+	fake.registerMutex.RLock()
+	defer fake.registerMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *BoltkvChecker) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ boltkv.Checker = new(BoltkvChecker)
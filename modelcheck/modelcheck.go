@@ -0,0 +1,134 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package modelcheck runs random sequences of bucket, key and iterator
+// operations against a libkv.DB and a sorted in-memory reference model and
+// shrinks failing sequences to a minimal reproduction.
+//
+// Like bolt cursors, iterators are only compared after Rewind or Seek
+// following a write to their bucket.
+package modelcheck
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	"github.com/golang/glog"
+)
+
+const (
+	// DefaultSequences is the number of sequences run by Check.
+	DefaultSequences = 100
+	// DefaultLength is the number of operations per sequence.
+	DefaultLength = 200
+)
+
+// NewDB returns an empty database for a single sequence, Check closes it.
+type NewDB func(ctx context.Context) (libkv.DB, error)
+
+// Options configures Check.
+type Options struct {
+	// Seed of the first sequence, the following use Seed+1, Seed+2, ...
+	// Zero picks one from the clock.
+	Seed int64
+	// Sequences is the number of sequences to run, defaults to DefaultSequences.
+	Sequences int
+	// Length is the number of operations per sequence, defaults to DefaultLength.
+	Length int
+}
+
+// FailureError is returned by Check for a sequence the database fails.
+type FailureError struct {
+	// Seed generates the failing sequence, pass it with Sequences 1 to repeat it.
+	Seed int64
+	// Sequence is the shrunk sequence, still failing with Err.
+	Sequence Sequence
+	// Length is the length of the generated sequence before shrinking.
+	Length int
+	// Err is the difference reported for Sequence.
+	Err error
+}
+
+func (f *FailureError) Error() string {
+	return fmt.Sprintf(
+		"sequence of seed %d failed, shrunk from %d to %d operations:\n%s\n%v",
+		f.Seed,
+		f.Length,
+		len(f.Sequence),
+		f.Sequence,
+		f.Err,
+	)
+}
+
+// Check runs random sequences, each against a new database, and returns a
+// *FailureError with the shrunk sequence for the first one failing.
+func Check(ctx context.Context, newDB NewDB, opts Options) error {
+	if opts.Seed == 0 {
+		opts.Seed = time.Now().UnixNano()
+	}
+	if opts.Sequences <= 0 {
+		opts.Sequences = DefaultSequences
+	}
+	if opts.Length <= 0 {
+		opts.Length = DefaultLength
+	}
+	for i := 0; i < opts.Sequences; i++ {
+		seed := opts.Seed + int64(i)
+		sequence := Generate(rand.New(rand.NewSource(seed)), opts.Length) // #nosec G404 -- reproducible
+		err := runNew(ctx, newDB, sequence)
+		if err == nil {
+			continue
+		}
+		if ctx.Err() != nil {
+			return errors.Wrapf(ctx, ctx.Err(), "check canceled")
+		}
+		glog.V(2).Infof("sequence of seed %d failed, shrinking: %v", seed, err)
+		shrunk, err := Shrink(ctx, newDB, sequence, err)
+		return &FailureError{
+			Seed:     seed,
+			Sequence: shrunk,
+			Length:   len(sequence),
+			Err:      err,
+		}
+	}
+	return nil
+}
+
+// Shrink removes operations from the failing sequence as long as it keeps
+// failing, first in large chunks, then one by one. It returns the minimal
+// sequence found and its error, err belongs to sequence.
+func Shrink(ctx context.Context, newDB NewDB, sequence Sequence, err error) (Sequence, error) {
+	for chunk := len(sequence) / 2; chunk >= 1 && ctx.Err() == nil; {
+		removed := false
+		for start := 0; start+chunk <= len(sequence) && ctx.Err() == nil; {
+			candidate := make(Sequence, 0, len(sequence)-chunk)
+			candidate = append(candidate, sequence[:start]...)
+			candidate = append(candidate, sequence[start+chunk:]...)
+			if candidateErr := runNew(ctx, newDB, candidate); candidateErr != nil {
+				sequence, err = candidate, candidateErr
+				removed = true
+				continue
+			}
+			start += chunk
+		}
+		if !removed {
+			chunk /= 2
+		}
+	}
+	return sequence, err
+}
+
+// runNew runs sequence against a new database and closes it.
+func runNew(ctx context.Context, newDB NewDB, sequence Sequence) error {
+	db, err := newDB(ctx)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "create db failed")
+	}
+	defer db.Close()
+	return Run(ctx, db, sequence)
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package modelcheck
+
+import (
+	"bytes"
+	"sort"
+)
+
+// model is the reference: buckets of sorted keys held in plain maps.
+type model map[string]map[string][]byte
+
+func (m model) clone() model {
+	result := make(model, len(m))
+	for name, bucket := range m {
+		copied := make(map[string][]byte, len(bucket))
+		for key, value := range bucket {
+			copied[key] = value
+		}
+		result[name] = copied
+	}
+	return result
+}
+
+func (m model) bucketNames() []string {
+	result := make([]string, 0, len(m))
+	for name := range m {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (m model) keys(bucket string) []string {
+	result := make([]string, 0, len(m[bucket]))
+	for key := range m[bucket] {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// modelIterator is the expected state of an iterator slot.
+type modelIterator struct {
+	bucket  string
+	reverse bool
+	// positioned is false until Rewind or Seek and after writes to the
+	// bucket, which invalidate bolt cursors.
+	positioned bool
+	// key is the current key, nil if the iterator is not valid.
+	key []byte
+}
+
+func (i *modelIterator) valid() bool {
+	return i.key != nil
+}
+
+func (i *modelIterator) rewind(m model) {
+	keys := m.keys(i.bucket)
+	i.positioned = true
+	i.key = nil
+	if len(keys) == 0 {
+		return
+	}
+	if i.reverse {
+		i.key = []byte(keys[len(keys)-1])
+		return
+	}
+	i.key = []byte(keys[0])
+}
+
+// seek moves to the first key >= key, or the last key <= key if reverse.
+func (i *modelIterator) seek(m model, key []byte) {
+	i.positioned = true
+	i.key = nil
+	keys := m.keys(i.bucket)
+	if i.reverse {
+		for pos := len(keys) - 1; pos >= 0; pos-- {
+			if bytes.Compare([]byte(keys[pos]), key) <= 0 {
+				i.key = []byte(keys[pos])
+				return
+			}
+		}
+		return
+	}
+	for _, candidate := range keys {
+		if bytes.Compare([]byte(candidate), key) >= 0 {
+			i.key = []byte(candidate)
+			return
+		}
+	}
+}
+
+func (i *modelIterator) next(m model) {
+	current := i.key
+	i.key = nil
+	keys := m.keys(i.bucket)
+	if i.reverse {
+		for pos := len(keys) - 1; pos >= 0; pos-- {
+			if bytes.Compare([]byte(keys[pos]), current) < 0 {
+				i.key = []byte(keys[pos])
+				return
+			}
+		}
+		return
+	}
+	for _, candidate := range keys {
+		if bytes.Compare([]byte(candidate), current) > 0 {
+			i.key = []byte(candidate)
+			return
+		}
+	}
+}
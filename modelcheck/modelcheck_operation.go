@@ -0,0 +1,157 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package modelcheck
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// OperationKind is the call an Operation makes.
+type OperationKind string
+
+const (
+	OperationCreateBucket            OperationKind = "CreateBucket"
+	OperationCreateBucketIfNotExists OperationKind = "CreateBucketIfNotExists"
+	OperationDeleteBucket            OperationKind = "DeleteBucket"
+	OperationListBuckets             OperationKind = "ListBuckets"
+	OperationPut                     OperationKind = "Put"
+	OperationDelete                  OperationKind = "Delete"
+	OperationGet                     OperationKind = "Get"
+	// OperationIterator opens an iterator on Bucket in slot Iterator,
+	// closing the iterator previously in the slot.
+	OperationIterator OperationKind = "Iterator"
+	OperationRewind   OperationKind = "Rewind"
+	OperationSeek     OperationKind = "Seek"
+	OperationNext     OperationKind = "Next"
+	// OperationCommit ends the current Update, the next operation starts a new one.
+	OperationCommit OperationKind = "Commit"
+	// OperationRollback fails the current Update, discarding its writes.
+	OperationRollback OperationKind = "Rollback"
+)
+
+// Operation is a single step of a Sequence.
+type Operation struct {
+	Kind     OperationKind
+	Bucket   string
+	Key      []byte
+	Value    []byte
+	Iterator int
+	Reverse  bool
+}
+
+func (o Operation) String() string {
+	switch o.Kind {
+	case OperationCreateBucket, OperationCreateBucketIfNotExists, OperationDeleteBucket:
+		return fmt.Sprintf("%s(%s)", o.Kind, o.Bucket)
+	case OperationPut:
+		return fmt.Sprintf("%s(%s, %q, %q)", o.Kind, o.Bucket, o.Key, o.Value)
+	case OperationDelete, OperationGet:
+		return fmt.Sprintf("%s(%s, %q)", o.Kind, o.Bucket, o.Key)
+	case OperationIterator:
+		if o.Reverse {
+			return fmt.Sprintf("IteratorReverse(it%d, %s)", o.Iterator, o.Bucket)
+		}
+		return fmt.Sprintf("%s(it%d, %s)", o.Kind, o.Iterator, o.Bucket)
+	case OperationRewind, OperationNext:
+		return fmt.Sprintf("%s(it%d)", o.Kind, o.Iterator)
+	case OperationSeek:
+		return fmt.Sprintf("%s(it%d, %q)", o.Kind, o.Iterator, o.Key)
+	default:
+		return string(o.Kind)
+	}
+}
+
+// Sequence is a list of operations run by Run.
+type Sequence []Operation
+
+// String returns one operation per line, numbered like in failure messages.
+func (s Sequence) String() string {
+	lines := make([]string, len(s))
+	for i, operation := range s {
+		lines[i] = fmt.Sprintf("%3d: %s", i, operation)
+	}
+	return strings.Join(lines, "\n")
+}
+
+const (
+	bucketCount   = 3
+	iteratorSlots = 3
+	keyAlphabet   = "abc"
+)
+
+// operationWeights controls how often Generate picks each kind. Iterator
+// moves dominate to reach many positions between writes.
+var operationWeights = []struct {
+	kind   OperationKind
+	weight int
+}{
+	{OperationCreateBucket, 2},
+	{OperationCreateBucketIfNotExists, 3},
+	{OperationDeleteBucket, 1},
+	{OperationListBuckets, 2},
+	{OperationPut, 20},
+	{OperationDelete, 8},
+	{OperationGet, 8},
+	{OperationIterator, 5},
+	{OperationRewind, 8},
+	{OperationSeek, 15},
+	{OperationNext, 25},
+	{OperationCommit, 2},
+	{OperationRollback, 1},
+}
+
+// Generate returns a random sequence of length operations. Keys are short
+// and drawn from a small alphabet, so writes collide and seeks often fall
+// between, before or after the stored keys.
+func Generate(random *rand.Rand, length int) Sequence {
+	total := 0
+	for _, entry := range operationWeights {
+		total += entry.weight
+	}
+	result := make(Sequence, length)
+	for i := range result {
+		pick := random.Intn(total)
+		var kind OperationKind
+		for _, entry := range operationWeights {
+			if pick < entry.weight {
+				kind = entry.kind
+				break
+			}
+			pick -= entry.weight
+		}
+		result[i] = Operation{
+			Kind:     kind,
+			Bucket:   fmt.Sprintf("b%d", random.Intn(bucketCount)),
+			Key:      generateKey(random),
+			Value:    generateValue(random),
+			Iterator: random.Intn(iteratorSlots),
+			Reverse:  random.Intn(2) == 0,
+		}
+	}
+	return result
+}
+
+func generateKey(random *rand.Rand) []byte {
+	key := make([]byte, 1+random.Intn(3))
+	for i := range key {
+		key[i] = keyAlphabet[random.Intn(len(keyAlphabet))]
+	}
+	switch random.Intn(20) {
+	case 0:
+		key = append(key, 0x00)
+	case 1:
+		key = append(key, 0xff)
+	}
+	return key
+}
+
+func generateValue(random *rand.Rand) []byte {
+	if random.Intn(10) == 0 {
+		return []byte{}
+	}
+	return []byte(fmt.Sprintf("v%d", random.Intn(1000)))
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package modelcheck_test
+
+import (
+	"math/rand"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv/modelcheck"
+)
+
+var _ = Describe("Generate", func() {
+	It("is reproducible by seed", func() {
+		first := modelcheck.Generate(rand.New(rand.NewSource(7)), 50)
+		second := modelcheck.Generate(rand.New(rand.NewSource(7)), 50)
+		Expect(first).To(HaveLen(50))
+		Expect(first).To(Equal(second))
+	})
+	It("generates all kinds", func() {
+		kinds := map[modelcheck.OperationKind]bool{}
+		for _, operation := range modelcheck.Generate(rand.New(rand.NewSource(1)), 2000) {
+			kinds[operation.Kind] = true
+		}
+		Expect(kinds).To(HaveLen(13))
+	})
+})
+
+var _ = Describe("Sequence", func() {
+	It("prints one numbered operation per line", func() {
+		sequence := modelcheck.Sequence{
+			{Kind: modelcheck.OperationPut, Bucket: "b0", Key: []byte("a"), Value: []byte("v")},
+			{Kind: modelcheck.OperationIterator, Bucket: "b0", Iterator: 1, Reverse: true},
+			{Kind: modelcheck.OperationSeek, Iterator: 1, Key: []byte("a\xff")},
+			{Kind: modelcheck.OperationCommit},
+		}
+		Expect(sequence.String()).To(Equal(`  0: Put(b0, "a", "v")
+  1: IteratorReverse(it1, b0)
+  2: Seek(it1, "a\xff")
+  3: Commit`))
+	})
+})
@@ -0,0 +1,346 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package modelcheck
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+)
+
+var errRollback = errors.New(context.Background(), "rollback")
+
+// Run applies sequence to db, starting a new Update after each
+// OperationCommit and OperationRollback, and compares every result with the
+// reference model. After the last operation it compares the whole content in
+// a View. It returns an error describing the first difference.
+func Run(ctx context.Context, db libkv.DB, sequence Sequence) error {
+	committed := model{}
+	for start := 0; start < len(sequence); {
+		end := start
+		for end < len(sequence) &&
+			sequence[end].Kind != OperationCommit &&
+			sequence[end].Kind != OperationRollback {
+			end++
+		}
+		rollback := end < len(sequence) && sequence[end].Kind == OperationRollback
+		r := &runner{
+			model:     committed.clone(),
+			iterators: map[int]*runnerIterator{},
+		}
+		err := db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			defer r.closeIterators()
+			for i := start; i < end; i++ {
+				if err := r.apply(ctx, tx, i, sequence[i]); err != nil {
+					return err
+				}
+			}
+			if rollback {
+				return errRollback
+			}
+			return nil
+		})
+		switch {
+		case rollback && errors.Is(err, errRollback):
+		case err != nil:
+			return err
+		default:
+			committed = r.model
+		}
+		start = end + 1
+	}
+	return verifyCommitted(ctx, db, committed)
+}
+
+type runnerIterator struct {
+	iterator libkv.Iterator
+	model    *modelIterator
+}
+
+type runner struct {
+	model     model
+	iterators map[int]*runnerIterator
+}
+
+func (r *runner) closeIterators() {
+	for slot, iterator := range r.iterators {
+		iterator.iterator.Close()
+		delete(r.iterators, slot)
+	}
+}
+
+func (r *runner) apply(ctx context.Context, tx libkv.Tx, index int, operation Operation) error {
+	mismatch := func(format string, args ...interface{}) error {
+		return errors.Errorf(
+			ctx,
+			"operation %d %s: %s",
+			index,
+			operation,
+			fmt.Sprintf(format, args...),
+		)
+	}
+	_, exists := r.model[operation.Bucket]
+	name := libkv.NewBucketName(operation.Bucket)
+	switch operation.Kind {
+	case OperationCreateBucket:
+		_, err := tx.CreateBucket(ctx, name)
+		if exists {
+			return expectError(err, libkv.BucketAlreadyExistsError, mismatch)
+		}
+		if err != nil {
+			return mismatch("unexpected error %v", err)
+		}
+		r.model[operation.Bucket] = map[string][]byte{}
+		return nil
+	case OperationCreateBucketIfNotExists:
+		if _, err := tx.CreateBucketIfNotExists(ctx, name); err != nil {
+			return mismatch("unexpected error %v", err)
+		}
+		if !exists {
+			r.model[operation.Bucket] = map[string][]byte{}
+		}
+		return nil
+	case OperationDeleteBucket:
+		err := tx.DeleteBucket(ctx, name)
+		if !exists {
+			return expectError(err, libkv.BucketNotFoundError, mismatch)
+		}
+		if err != nil {
+			return mismatch("unexpected error %v", err)
+		}
+		delete(r.model, operation.Bucket)
+		for slot, iterator := range r.iterators {
+			if iterator.model.bucket == operation.Bucket {
+				iterator.iterator.Close()
+				delete(r.iterators, slot)
+			}
+		}
+		return nil
+	case OperationListBuckets:
+		names, err := tx.ListBucketNames(ctx)
+		if err != nil {
+			return mismatch("unexpected error %v", err)
+		}
+		return compareBucketNames(names, r.model, mismatch)
+	case OperationPut, OperationDelete, OperationGet, OperationIterator:
+		bucket, err := tx.Bucket(ctx, name)
+		if !exists {
+			return expectError(err, libkv.BucketNotFoundError, mismatch)
+		}
+		if err != nil {
+			return mismatch("unexpected error %v", err)
+		}
+		return r.applyBucket(ctx, bucket, operation, mismatch)
+	case OperationRewind, OperationSeek, OperationNext:
+		iterator, ok := r.iterators[operation.Iterator]
+		if !ok {
+			return nil
+		}
+		return r.applyIterator(iterator, operation, mismatch)
+	default:
+		return mismatch("unknown operation")
+	}
+}
+
+func (r *runner) applyBucket(
+	ctx context.Context,
+	bucket libkv.Bucket,
+	operation Operation,
+	mismatch func(format string, args ...interface{}) error,
+) error {
+	switch operation.Kind {
+	case OperationPut:
+		if err := bucket.Put(ctx, operation.Key, operation.Value); err != nil {
+			return mismatch("unexpected error %v", err)
+		}
+		r.model[operation.Bucket][string(operation.Key)] = operation.Value
+		r.unposition(operation.Bucket)
+		return nil
+	case OperationDelete:
+		if err := bucket.Delete(ctx, operation.Key); err != nil {
+			return mismatch("unexpected error %v", err)
+		}
+		delete(r.model[operation.Bucket], string(operation.Key))
+		r.unposition(operation.Bucket)
+		return nil
+	case OperationGet:
+		item, err := bucket.Get(ctx, operation.Key)
+		if err != nil {
+			return mismatch("unexpected error %v", err)
+		}
+		expected, ok := r.model[operation.Bucket][string(operation.Key)]
+		if item.Exists() != ok {
+			return mismatch("exists is %t, expected %t", item.Exists(), ok)
+		}
+		if !ok {
+			return nil
+		}
+		return compareValue(item, expected, mismatch)
+	default:
+		if previous, ok := r.iterators[operation.Iterator]; ok {
+			previous.iterator.Close()
+		}
+		iterator := bucket.Iterator()
+		if operation.Reverse {
+			iterator = bucket.IteratorReverse()
+		}
+		r.iterators[operation.Iterator] = &runnerIterator{
+			iterator: iterator,
+			model: &modelIterator{
+				bucket:  operation.Bucket,
+				reverse: operation.Reverse,
+			},
+		}
+		return nil
+	}
+}
+
+func (r *runner) applyIterator(
+	iterator *runnerIterator,
+	operation Operation,
+	mismatch func(format string, args ...interface{}) error,
+) error {
+	switch operation.Kind {
+	case OperationRewind:
+		iterator.iterator.Rewind()
+		iterator.model.rewind(r.model)
+	case OperationSeek:
+		iterator.iterator.Seek(operation.Key)
+		iterator.model.seek(r.model, operation.Key)
+	default:
+		// bolt leaves Next undefined before positioning and after the end
+		if !iterator.model.positioned || !iterator.model.valid() {
+			return nil
+		}
+		iterator.iterator.Next()
+		iterator.model.next(r.model)
+	}
+	valid := iterator.iterator.Valid()
+	if valid != iterator.model.valid() {
+		return mismatch(
+			"valid is %t at %s, expected %t at %s",
+			valid,
+			describeKey(valid, iterator.iterator),
+			iterator.model.valid(),
+			describeExpectedKey(iterator.model.key),
+		)
+	}
+	if !valid {
+		return nil
+	}
+	item := iterator.iterator.Item()
+	if !bytes.Equal(item.Key(), iterator.model.key) {
+		return mismatch("key is %q, expected %q", item.Key(), iterator.model.key)
+	}
+	return compareValue(item, r.model[iterator.model.bucket][string(iterator.model.key)], mismatch)
+}
+
+// unposition marks the iterators of bucket as invalidated by a write.
+func (r *runner) unposition(bucket string) {
+	for _, iterator := range r.iterators {
+		if iterator.model.bucket == bucket {
+			iterator.model.positioned = false
+		}
+	}
+}
+
+func describeKey(valid bool, iterator libkv.Iterator) string {
+	if !valid {
+		return "end"
+	}
+	return fmt.Sprintf("%q", iterator.Item().Key())
+}
+
+func describeExpectedKey(key []byte) string {
+	if key == nil {
+		return "end"
+	}
+	return fmt.Sprintf("%q", key)
+}
+
+func expectError(
+	err error,
+	expected error,
+	mismatch func(format string, args ...interface{}) error,
+) error {
+	if !errors.Is(err, expected) {
+		return mismatch("error is %v, expected %v", err, expected)
+	}
+	return nil
+}
+
+func compareValue(
+	item libkv.Item,
+	expected []byte,
+	mismatch func(format string, args ...interface{}) error,
+) error {
+	var value []byte
+	err := item.Value(func(v []byte) error {
+		value = append([]byte{}, v...)
+		return nil
+	})
+	if err != nil {
+		return mismatch("unexpected error %v", err)
+	}
+	if !bytes.Equal(value, expected) {
+		return mismatch("value of %q is %q, expected %q", item.Key(), value, expected)
+	}
+	return nil
+}
+
+func compareBucketNames(
+	names libkv.BucketNames,
+	m model,
+	mismatch func(format string, args ...interface{}) error,
+) error {
+	actual := make([]string, len(names))
+	for i, name := range names {
+		actual[i] = name.String()
+	}
+	expected := m.bucketNames()
+	if strings.Join(actual, ",") != strings.Join(expected, ",") {
+		return mismatch("buckets are %v, expected %v", actual, expected)
+	}
+	return nil
+}
+
+// verifyCommitted compares the content of db with m in a View.
+func verifyCommitted(ctx context.Context, db libkv.DB, m model) error {
+	mismatch := func(format string, args ...interface{}) error {
+		return errors.Errorf(ctx, "committed state: %s", fmt.Sprintf(format, args...))
+	}
+	return db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		names, err := tx.ListBucketNames(ctx)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "list buckets failed")
+		}
+		if err := compareBucketNames(names, m, mismatch); err != nil {
+			return err
+		}
+		for _, name := range m.bucketNames() {
+			bucket, err := tx.Bucket(ctx, libkv.NewBucketName(name))
+			if err != nil {
+				return errors.Wrapf(ctx, err, "get bucket %s failed", name)
+			}
+			var keys []string
+			err = libkv.ForEach(ctx, bucket, func(item libkv.Item) error {
+				keys = append(keys, string(item.Key()))
+				return compareValue(item, m[name][string(item.Key())], mismatch)
+			})
+			if err != nil {
+				return err
+			}
+			expected := m.keys(name)
+			if strings.Join(keys, "\x00") != strings.Join(expected, "\x00") {
+				return mismatch("bucket %s has keys %q, expected %q", name, keys, expected)
+			}
+		}
+		return nil
+	})
+}
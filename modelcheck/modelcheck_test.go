@@ -0,0 +1,170 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package modelcheck_test
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+	"github.com/bborbe/boltkv/modelcheck"
+)
+
+var _ = Describe("Check", func() {
+	var ctx context.Context
+	var newBoltDB modelcheck.NewDB
+	BeforeEach(func() {
+		ctx = context.Background()
+		dir := GinkgoT().TempDir()
+		newBoltDB = func(ctx context.Context) (libkv.DB, error) {
+			return boltkv.OpenTempWithOptions(ctx, boltkv.TempOptions{
+				Dir:           dir,
+				RemoveOnClose: true,
+			})
+		}
+	})
+	It("finds no difference in boltkv", func() {
+		Expect(modelcheck.Check(ctx, newBoltDB, modelcheck.Options{
+			Seed:      1,
+			Sequences: 50,
+		})).To(BeNil())
+	})
+	It("finds no difference in the memory db", func() {
+		Expect(modelcheck.Check(ctx, newMemoryDB, modelcheck.Options{
+			Seed:      1,
+			Sequences: 200,
+		})).To(BeNil())
+	})
+	It("shrinks a failing sequence", func() {
+		err := modelcheck.Check(ctx, newBrokenDB, modelcheck.Options{Seed: 1})
+		var failure *modelcheck.FailureError
+		Expect(errors.As(err, &failure)).To(BeTrue())
+		Expect(failure.Length).To(Equal(modelcheck.DefaultLength))
+		Expect(len(failure.Sequence)).To(BeNumerically("<=", 4))
+		Expect(modelcheck.Run(ctx, newMemoryDBOrFail(ctx), failure.Sequence)).To(BeNil())
+		Expect(err.Error()).To(ContainSubstring("IteratorReverse"))
+	})
+})
+
+var _ = Describe("Run", func() {
+	var ctx context.Context
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+	// reverse Seek between two keys must return the smaller one
+	reverseSeekBetweenKeys := modelcheck.Sequence{
+		{Kind: modelcheck.OperationCreateBucket, Bucket: "b0"},
+		{Kind: modelcheck.OperationPut, Bucket: "b0", Key: []byte("a"), Value: []byte("1")},
+		{Kind: modelcheck.OperationPut, Bucket: "b0", Key: []byte("c"), Value: []byte("2")},
+		{Kind: modelcheck.OperationIterator, Bucket: "b0", Reverse: true},
+		{Kind: modelcheck.OperationSeek, Key: []byte("b")},
+		{Kind: modelcheck.OperationNext},
+		{Kind: modelcheck.OperationSeek, Key: []byte("d")},
+	}
+	It("passes the sequence with boltkv", func() {
+		db, err := boltkv.OpenTempWithOptions(ctx, boltkv.TempOptions{
+			Dir:           GinkgoT().TempDir(),
+			RemoveOnClose: true,
+		})
+		Expect(err).To(BeNil())
+		defer db.Close()
+		Expect(modelcheck.Run(ctx, db, reverseSeekBetweenKeys)).To(BeNil())
+	})
+	It("reports the failing operation", func() {
+		err := modelcheck.Run(ctx, newBrokenDBOrFail(ctx), reverseSeekBetweenKeys)
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring(`operation 4 Seek(it0, "b"): key is "c", expected "a"`))
+	})
+	It("discards rolled back writes", func() {
+		db := newMemoryDBOrFail(ctx)
+		defer db.Close()
+		Expect(modelcheck.Run(ctx, db, modelcheck.Sequence{
+			{Kind: modelcheck.OperationCreateBucket, Bucket: "b0"},
+			{Kind: modelcheck.OperationCommit},
+			{Kind: modelcheck.OperationPut, Bucket: "b0", Key: []byte("a"), Value: []byte("1")},
+			{Kind: modelcheck.OperationRollback},
+			{Kind: modelcheck.OperationGet, Bucket: "b0", Key: []byte("a")},
+			{Kind: modelcheck.OperationDeleteBucket, Bucket: "b0"},
+			{Kind: modelcheck.OperationDeleteBucket, Bucket: "b0"},
+			{Kind: modelcheck.OperationListBuckets},
+		})).To(BeNil())
+	})
+})
+
+func newMemoryDB(ctx context.Context) (libkv.DB, error) {
+	return boltkv.NewMemoryDB(), nil
+}
+
+func newMemoryDBOrFail(ctx context.Context) libkv.DB {
+	return boltkv.NewMemoryDB()
+}
+
+// newBrokenDB returns a memory db whose reverse Seek behaves like a forward
+// Seek, the bug the special case in the reverse iterator prevents.
+func newBrokenDB(ctx context.Context) (libkv.DB, error) {
+	return &brokenDB{DB: boltkv.NewMemoryDB()}, nil
+}
+
+func newBrokenDBOrFail(ctx context.Context) libkv.DB {
+	db, _ := newBrokenDB(ctx)
+	return db
+}
+
+type brokenDB struct {
+	libkv.DB
+}
+
+func (b *brokenDB) Update(
+	ctx context.Context,
+	fn func(ctx context.Context, tx libkv.Tx) error,
+) error {
+	return b.DB.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		return fn(ctx, &brokenTx{Tx: tx})
+	})
+}
+
+type brokenTx struct {
+	libkv.Tx
+}
+
+func (b *brokenTx) Bucket(ctx context.Context, name libkv.BucketName) (libkv.Bucket, error) {
+	bucket, err := b.Tx.Bucket(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &brokenBucket{Bucket: bucket}, nil
+}
+
+type brokenBucket struct {
+	libkv.Bucket
+}
+
+func (b *brokenBucket) IteratorReverse() libkv.Iterator {
+	return &brokenIterator{Iterator: b.Bucket.IteratorReverse(), forward: b.Bucket.Iterator()}
+}
+
+// brokenIterator seeks forward, then continues in reverse from there.
+type brokenIterator struct {
+	libkv.Iterator
+	forward libkv.Iterator
+}
+
+func (b *brokenIterator) Seek(key []byte) {
+	b.forward.Seek(key)
+	if !b.forward.Valid() {
+		b.Iterator.Seek(key)
+		return
+	}
+	found := b.forward.Item().Key()
+	b.Iterator.Seek(found)
+	for b.Iterator.Valid() && !bytes.Equal(b.Iterator.Item().Key(), found) {
+		b.Iterator.Next()
+	}
+}
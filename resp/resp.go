@@ -0,0 +1,182 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package resp serves a libkv database with the Redis protocol (RESP).
+package resp
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	"github.com/golang/glog"
+)
+
+const (
+	// DefaultBucket is selected by new connections, matching Redis database 0.
+	DefaultBucket = "0"
+	// DefaultMaxBulkBytes limits the size of a single argument.
+	DefaultMaxBulkBytes = 64 << 20
+	// DefaultMaxCommandBytes limits the summed size of all arguments of a command.
+	DefaultMaxCommandBytes = 128 << 20
+)
+
+// Options configures NewServer.
+type Options struct {
+	// Bucket is selected by new connections, defaults to DefaultBucket.
+	Bucket string
+	// MaxBulkBytes limits the size of a single argument, defaults to DefaultMaxBulkBytes.
+	MaxBulkBytes int
+	// MaxCommandBytes limits the summed size of all arguments of a command,
+	// defaults to DefaultMaxCommandBytes.
+	MaxCommandBytes int
+}
+
+// Server serves connections with the Redis protocol.
+type Server interface {
+	// Serve accepts connections until ctx is canceled and closes listener and
+	// all connections on return.
+	Serve(ctx context.Context, listener net.Listener) error
+}
+
+// NewServer returns a Server for db supporting a subset of Redis:
+//
+//	PING [message]
+//	QUIT
+//	SELECT bucket                          select the bucket of following commands
+//	GET key
+//	SET key value [NX|XX]
+//	DEL key [key ...]
+//	EXISTS key [key ...]
+//	INCR key
+//	INCRBY key increment
+//	SCAN cursor [MATCH prefix*] [COUNT count]
+//	MULTI, EXEC, DISCARD                   run the queued commands in one Update
+//
+// Each bucket acts as Redis database, SELECT accepts any bucket name and
+// writes create the bucket if missing. Commands outside MULTI run in their
+// own View or Update.
+func NewServer(db libkv.DB, opts Options) Server {
+	if opts.Bucket == "" {
+		opts.Bucket = DefaultBucket
+	}
+	if opts.MaxBulkBytes <= 0 {
+		opts.MaxBulkBytes = DefaultMaxBulkBytes
+	}
+	if opts.MaxCommandBytes <= 0 {
+		opts.MaxCommandBytes = DefaultMaxCommandBytes
+	}
+	return &server{
+		db:    db,
+		opts:  opts,
+		conns: map[net.Conn]struct{}{},
+	}
+}
+
+type server struct {
+	db   libkv.DB
+	opts Options
+
+	mux   sync.Mutex
+	conns map[net.Conn]struct{}
+}
+
+func (s *server) Serve(ctx context.Context, listener net.Listener) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+		s.closeConns()
+	}()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return errors.Wrapf(ctx, err, "accept failed")
+		}
+		if !s.track(ctx, conn) {
+			conn.Close()
+			return nil
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer s.untrack(conn)
+			if err := s.serveConn(ctx, conn); err != nil {
+				glog.Warningf("serve %s failed: %v", conn.RemoteAddr(), err)
+			}
+		}()
+	}
+}
+
+// track registers conn to be closed on shutdown, false if already shutting down.
+func (s *server) track(ctx context.Context, conn net.Conn) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if ctx.Err() != nil {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	return true
+}
+
+func (s *server) untrack(conn net.Conn) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	delete(s.conns, conn)
+	conn.Close()
+}
+
+func (s *server) closeConns() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+func (s *server) serveConn(ctx context.Context, conn net.Conn) error {
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	session := &session{bucket: libkv.NewBucketName(s.opts.Bucket)}
+	var buf []byte
+	for !session.quit {
+		args, err := readCommand(ctx, r, s.opts.MaxBulkBytes, s.opts.MaxCommandBytes)
+		if err != nil {
+			if errors.Is(err, errProtocol) {
+				buf = appendReply(buf[:0], errorReply("ERR Protocol error: "+err.Error()))
+				_, _ = w.Write(buf)
+				_ = w.Flush()
+				return nil
+			}
+			if errors.Is(err, io.EOF) || ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if len(args) == 0 {
+			continue
+		}
+		buf = appendReply(buf[:0], s.execute(ctx, session, args))
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+		// pipelined commands are answered together
+		if r.Buffered() == 0 || session.quit {
+			if err := w.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,452 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+)
+
+// session is the state of a connection.
+type session struct {
+	bucket libkv.BucketName
+	quit   bool
+	// multi is set between MULTI and EXEC or DISCARD
+	multi bool
+	queue [][][]byte
+	// dirty is set if a command could not be queued, EXEC then discards the queue
+	dirty bool
+}
+
+// commandFunc executes a command with its arguments without the name.
+// Invalid input is answered with an errorReply, errors abort the transaction.
+type commandFunc func(
+	ctx context.Context,
+	tx libkv.Tx,
+	session *session,
+	args [][]byte,
+) (interface{}, error)
+
+type command struct {
+	// arity is the number of arguments including the name, -n for at least n
+	arity int
+	// write commands run in an Update
+	write bool
+	// local commands do not access the database and get no tx outside MULTI
+	local bool
+	fn    commandFunc
+}
+
+func (c command) validArity(args [][]byte) bool {
+	if c.arity < 0 {
+		return len(args) >= -c.arity
+	}
+	return len(args) == c.arity
+}
+
+var commands = map[string]command{
+	"PING":   {arity: -1, local: true, fn: ping},
+	"SELECT": {arity: 2, local: true, fn: selectBucket},
+	"GET":    {arity: 2, fn: get},
+	"SET":    {arity: -3, write: true, fn: set},
+	"DEL":    {arity: -2, write: true, fn: del},
+	"EXISTS": {arity: -2, fn: exists},
+	"INCR":   {arity: 2, write: true, fn: incr},
+	"INCRBY": {arity: 3, write: true, fn: incrBy},
+	"SCAN":   {arity: -2, fn: scan},
+}
+
+var errorMessageReplacer = strings.NewReplacer("\r", " ", "\n", " ")
+
+func errorf(format string, args ...interface{}) errorReply {
+	return errorReply(errorMessageReplacer.Replace("ERR " + fmt.Sprintf(format, args...)))
+}
+
+func (s *server) execute(ctx context.Context, session *session, args [][]byte) interface{} {
+	name := strings.ToUpper(string(args[0]))
+	switch {
+	case name == "QUIT":
+		session.quit = true
+		return simpleString("OK")
+	case name == "MULTI" && session.multi:
+		return errorf("MULTI calls can not be nested")
+	case name == "MULTI":
+		session.multi = true
+		return simpleString("OK")
+	case name == "EXEC" && session.multi:
+		return s.exec(ctx, session)
+	case name == "DISCARD" && session.multi:
+		session.multi, session.queue, session.dirty = false, nil, false
+		return simpleString("OK")
+	case name == "EXEC" || name == "DISCARD":
+		return errorf("%s without MULTI", name)
+	}
+	cmd, reply := lookup(name, args)
+	if reply != nil {
+		session.dirty = session.dirty || session.multi
+		return reply
+	}
+	if session.multi {
+		session.queue = append(session.queue, args)
+		return simpleString("QUEUED")
+	}
+	if cmd.local {
+		reply, err := cmd.fn(ctx, nil, session, args[1:])
+		if err != nil {
+			return errorf("%s", err)
+		}
+		return reply
+	}
+	run := s.db.View
+	if cmd.write {
+		run = s.db.Update
+	}
+	err := run(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		var err error
+		reply, err = cmd.fn(ctx, tx, session, args[1:])
+		return err
+	})
+	if err != nil {
+		return errorf("%s", err)
+	}
+	return reply
+}
+
+// lookup returns the command or an errorReply if the command is unknown or
+// has the wrong number of arguments.
+func lookup(name string, args [][]byte) (command, interface{}) {
+	cmd, ok := commands[name]
+	if !ok {
+		return command{}, errorf("unknown command '%s'", args[0])
+	}
+	if !cmd.validArity(args) {
+		return command{}, errorf("wrong number of arguments for '%s' command", strings.ToLower(name))
+	}
+	return cmd, nil
+}
+
+// exec runs the queued commands in one Update.
+func (s *server) exec(ctx context.Context, session *session) interface{} {
+	queue, dirty := session.queue, session.dirty
+	session.multi, session.queue, session.dirty = false, nil, false
+	if dirty {
+		return errorReply("EXECABORT Transaction discarded because of previous errors.")
+	}
+	replies := make([]interface{}, 0, len(queue))
+	err := s.db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		for _, args := range queue {
+			cmd := commands[strings.ToUpper(string(args[0]))]
+			reply, err := cmd.fn(ctx, tx, session, args[1:])
+			if err != nil {
+				return err
+			}
+			replies = append(replies, reply)
+		}
+		return nil
+	})
+	if err != nil {
+		return errorf("%s", err)
+	}
+	return replies
+}
+
+func ping(ctx context.Context, tx libkv.Tx, session *session, args [][]byte) (interface{}, error) {
+	switch len(args) {
+	case 0:
+		return simpleString("PONG"), nil
+	case 1:
+		return args[0], nil
+	default:
+		return errorf("wrong number of arguments for 'ping' command"), nil
+	}
+}
+
+func selectBucket(
+	ctx context.Context,
+	tx libkv.Tx,
+	session *session,
+	args [][]byte,
+) (interface{}, error) {
+	if len(args[0]) == 0 {
+		return errorf("invalid bucket"), nil
+	}
+	session.bucket = libkv.BucketName(bytes.Clone(args[0]))
+	return simpleString("OK"), nil
+}
+
+func get(ctx context.Context, tx libkv.Tx, session *session, args [][]byte) (interface{}, error) {
+	bucket, err := readBucket(ctx, tx, session.bucket)
+	if err != nil {
+		return nil, err
+	}
+	value, ok, err := getValue(ctx, bucket, args[0])
+	if err != nil || !ok {
+		return nilReply{}, err
+	}
+	return value, nil
+}
+
+func set(ctx context.Context, tx libkv.Tx, session *session, args [][]byte) (interface{}, error) {
+	var nx, xx bool
+	for _, option := range args[2:] {
+		switch strings.ToUpper(string(option)) {
+		case "NX":
+			nx = true
+		case "XX":
+			xx = true
+		case "EX", "PX", "EXAT", "PXAT", "KEEPTTL":
+			return errorf("expiration is not supported"), nil
+		default:
+			return errorf("syntax error"), nil
+		}
+	}
+	if nx && xx {
+		return errorf("syntax error"), nil
+	}
+	bucket, err := tx.CreateBucketIfNotExists(ctx, session.bucket)
+	if err != nil {
+		return nil, err
+	}
+	if nx || xx {
+		_, ok, err := getValue(ctx, bucket, args[0])
+		if err != nil {
+			return nil, err
+		}
+		if ok == nx {
+			return nilReply{}, nil
+		}
+	}
+	if err := bucket.Put(ctx, args[0], args[1]); err != nil {
+		return nil, err
+	}
+	return simpleString("OK"), nil
+}
+
+func del(ctx context.Context, tx libkv.Tx, session *session, args [][]byte) (interface{}, error) {
+	bucket, err := readBucket(ctx, tx, session.bucket)
+	if err != nil || bucket == nil {
+		return int64(0), err
+	}
+	var count int64
+	for _, key := range args {
+		_, ok, err := getValue(ctx, bucket, key)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		if err := bucket.Delete(ctx, key); err != nil {
+			return nil, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+func exists(
+	ctx context.Context,
+	tx libkv.Tx,
+	session *session,
+	args [][]byte,
+) (interface{}, error) {
+	bucket, err := readBucket(ctx, tx, session.bucket)
+	if err != nil {
+		return nil, err
+	}
+	var count int64
+	for _, key := range args {
+		_, ok, err := getValue(ctx, bucket, key)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func incr(ctx context.Context, tx libkv.Tx, session *session, args [][]byte) (interface{}, error) {
+	return increment(ctx, tx, session, args[0], 1)
+}
+
+func incrBy(
+	ctx context.Context,
+	tx libkv.Tx,
+	session *session,
+	args [][]byte,
+) (interface{}, error) {
+	delta, err := strconv.ParseInt(string(args[1]), 10, 64)
+	if err != nil {
+		return errorf("value is not an integer or out of range"), nil
+	}
+	return increment(ctx, tx, session, args[0], delta)
+}
+
+// increment adds delta to the decimal value of key, missing keys count as 0.
+func increment(
+	ctx context.Context,
+	tx libkv.Tx,
+	session *session,
+	key []byte,
+	delta int64,
+) (interface{}, error) {
+	bucket, err := tx.CreateBucketIfNotExists(ctx, session.bucket)
+	if err != nil {
+		return nil, err
+	}
+	value, ok, err := getValue(ctx, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	var current int64
+	if ok {
+		current, err = strconv.ParseInt(string(value), 10, 64)
+		if err != nil {
+			return errorf("value is not an integer or out of range"), nil
+		}
+	}
+	if (delta > 0 && current > math.MaxInt64-delta) ||
+		(delta < 0 && current < math.MinInt64-delta) {
+		return errorf("increment or decrement would overflow"), nil
+	}
+	current += delta
+	if err := bucket.Put(ctx, key, []byte(strconv.FormatInt(current, 10))); err != nil {
+		return nil, err
+	}
+	return current, nil
+}
+
+// scan returns keys matching a prefix pattern. The cursor is the number of
+// matching keys returned before, so keys inserted or deleted before the
+// cursor between calls shift the following pages.
+func scan(ctx context.Context, tx libkv.Tx, session *session, args [][]byte) (interface{}, error) {
+	cursor, err := strconv.ParseUint(string(args[0]), 10, 64)
+	if err != nil {
+		return errorf("invalid cursor"), nil
+	}
+	pattern, count := []byte("*"), 10
+	for i := 1; i < len(args); i += 2 {
+		if i+1 == len(args) {
+			return errorf("syntax error"), nil
+		}
+		switch strings.ToUpper(string(args[i])) {
+		case "MATCH":
+			pattern = args[i+1]
+		case "COUNT":
+			count, err = strconv.Atoi(string(args[i+1]))
+			if err != nil || count < 1 {
+				return errorf("syntax error"), nil
+			}
+		default:
+			return errorf("syntax error"), nil
+		}
+	}
+	prefix, exact, ok := parseMatch(pattern)
+	if !ok {
+		return errorf("only MATCH patterns of the form 'prefix*' are supported"), nil
+	}
+	bucket, err := readBucket(ctx, tx, session.bucket)
+	if err != nil {
+		return nil, err
+	}
+	keys := []interface{}{}
+	next := uint64(0)
+	if bucket != nil {
+		keys, next = scanKeys(bucket, prefix, exact, cursor, count)
+	}
+	return []interface{}{[]byte(strconv.FormatUint(next, 10)), keys}, nil
+}
+
+// scanKeys skips cursor keys with prefix and returns up to count keys and the
+// next cursor, 0 if no keys follow.
+func scanKeys(
+	bucket libkv.Bucket,
+	prefix []byte,
+	exact bool,
+	cursor uint64,
+	count int,
+) ([]interface{}, uint64) {
+	keys := []interface{}{}
+	it := bucket.Iterator()
+	defer it.Close()
+	if len(prefix) == 0 {
+		it.Rewind()
+	} else {
+		it.Seek(prefix)
+	}
+	var position uint64
+	for ; it.Valid(); it.Next() {
+		key := it.Item().Key()
+		if !bytes.HasPrefix(key, prefix) {
+			break
+		}
+		if exact && len(key) != len(prefix) {
+			continue
+		}
+		if position >= cursor {
+			if len(keys) == count {
+				return keys, position
+			}
+			keys = append(keys, bytes.Clone(key))
+		}
+		position++
+	}
+	return keys, 0
+}
+
+// parseMatch returns the prefix of a pattern 'prefix*', or the key of a
+// pattern without wildcards with exact set.
+func parseMatch(pattern []byte) ([]byte, bool, bool) {
+	prefix, exact := pattern, true
+	if bytes.HasSuffix(pattern, []byte("*")) {
+		prefix, exact = pattern[:len(pattern)-1], false
+	}
+	if bytes.ContainsAny(prefix, `*?[\`) {
+		return nil, false, false
+	}
+	return prefix, exact, true
+}
+
+// readBucket returns the bucket, nil if it does not exist.
+func readBucket(
+	ctx context.Context,
+	tx libkv.Tx,
+	bucketName libkv.BucketName,
+) (libkv.Bucket, error) {
+	bucket, err := tx.Bucket(ctx, bucketName)
+	if errors.Is(err, libkv.BucketNotFoundError) {
+		return nil, nil
+	}
+	return bucket, err
+}
+
+// getValue returns a copy of the value of key and whether it exists, bucket may be nil.
+func getValue(ctx context.Context, bucket libkv.Bucket, key []byte) ([]byte, bool, error) {
+	if bucket == nil {
+		return nil, false, nil
+	}
+	item, err := bucket.Get(ctx, key)
+	if err != nil {
+		return nil, false, err
+	}
+	if !item.Exists() {
+		return nil, false, nil
+	}
+	var value []byte
+	err = item.Value(func(val []byte) error {
+		value = bytes.Clone(val)
+		return nil
+	})
+	return value, err == nil, err
+}
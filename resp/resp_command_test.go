@@ -0,0 +1,144 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resp_test
+
+import (
+	"context"
+
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+	"github.com/bborbe/boltkv/resp"
+)
+
+var _ = Describe("Commands", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var db boltkv.DB
+	var client *testClient
+	bucketNames := func() []string {
+		var names []string
+		err := db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucketNames, err := tx.ListBucketNames(ctx)
+			for _, name := range bucketNames {
+				names = append(names, name.String())
+			}
+			return err
+		})
+		Expect(err).To(BeNil())
+		return names
+	}
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		var err error
+		db, err = boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		addr, _ := startServer(ctx, db, resp.Options{})
+		client = dial(addr)
+	})
+	AfterEach(func() {
+		cancel()
+		_ = client.conn.Close()
+		_ = db.Close()
+		_ = db.Remove()
+	})
+	It("gets, sets and deletes", func() {
+		Expect(client.do("GET", "k1")).To(BeNil())
+		Expect(bucketNames()).To(BeEmpty())
+		Expect(client.do("SET", "k1", "v1")).To(Equal("+OK"))
+		Expect(bucketNames()).To(Equal([]string{resp.DefaultBucket}))
+		Expect(client.do("GET", "k1")).To(Equal([]byte("v1")))
+		Expect(client.do("EXISTS", "k1", "k2", "k1")).To(Equal(int64(2)))
+		Expect(client.do("DEL", "k1", "k2")).To(Equal(int64(1)))
+		Expect(client.do("GET", "k1")).To(BeNil())
+	})
+	It("sets with NX and XX", func() {
+		Expect(client.do("SET", "k1", "v1", "XX")).To(BeNil())
+		Expect(client.do("SET", "k1", "v1", "NX")).To(Equal("+OK"))
+		Expect(client.do("SET", "k1", "v2", "NX")).To(BeNil())
+		Expect(client.do("SET", "k1", "v3", "xx")).To(Equal("+OK"))
+		Expect(client.do("GET", "k1")).To(Equal([]byte("v3")))
+		Expect(client.do("SET", "k1", "v1", "EX", "10")).To(HavePrefix("-ERR"))
+	})
+	It("increments", func() {
+		Expect(client.do("INCR", "n")).To(Equal(int64(1)))
+		Expect(client.do("INCRBY", "n", "41")).To(Equal(int64(42)))
+		Expect(client.do("INCRBY", "n", "-50")).To(Equal(int64(-8)))
+		Expect(client.do("GET", "n")).To(Equal([]byte("-8")))
+		Expect(client.do("INCRBY", "n", "x")).To(HavePrefix("-ERR value is not an integer"))
+		Expect(client.do("SET", "s", "abc")).To(Equal("+OK"))
+		Expect(client.do("INCR", "s")).To(HavePrefix("-ERR value is not an integer"))
+		Expect(client.do("SET", "max", "9223372036854775807")).To(Equal("+OK"))
+		Expect(client.do("INCR", "max")).To(HavePrefix("-ERR increment or decrement would overflow"))
+	})
+	It("selects buckets", func() {
+		Expect(client.do("SET", "k1", "v1")).To(Equal("+OK"))
+		Expect(client.do("SELECT", "users")).To(Equal("+OK"))
+		Expect(client.do("GET", "k1")).To(BeNil())
+		Expect(client.do("SET", "k1", "v2")).To(Equal("+OK"))
+		Expect(client.do("SELECT", "0")).To(Equal("+OK"))
+		Expect(client.do("GET", "k1")).To(Equal([]byte("v1")))
+		Expect(bucketNames()).To(Equal([]string{"0", "users"}))
+	})
+	It("scans prefixes with cursors", func() {
+		for _, key := range []string{"a1", "b1", "b2", "b3", "b4", "c1"} {
+			Expect(client.do("SET", key, "v")).To(Equal("+OK"))
+		}
+		Expect(client.do("SCAN", "0", "MATCH", "b*", "COUNT", "3")).To(Equal([]interface{}{
+			[]byte("3"),
+			[]interface{}{[]byte("b1"), []byte("b2"), []byte("b3")},
+		}))
+		Expect(client.do("SCAN", "3", "MATCH", "b*", "COUNT", "3")).To(Equal([]interface{}{
+			[]byte("0"),
+			[]interface{}{[]byte("b4")},
+		}))
+		Expect(client.do("SCAN", "0")).To(Equal([]interface{}{
+			[]byte("0"),
+			[]interface{}{
+				[]byte("a1"), []byte("b1"), []byte("b2"), []byte("b3"), []byte("b4"), []byte("c1"),
+			},
+		}))
+		Expect(client.do("SCAN", "0", "MATCH", "c1")).To(Equal([]interface{}{
+			[]byte("0"),
+			[]interface{}{[]byte("c1")},
+		}))
+		Expect(client.do("SCAN", "0", "MATCH", "*1")).To(HavePrefix("-ERR"))
+	})
+	It("runs MULTI/EXEC in one update", func() {
+		Expect(client.do("SET", "n", "1")).To(Equal("+OK"))
+		Expect(client.do("MULTI")).To(Equal("+OK"))
+		Expect(client.do("INCR", "n")).To(Equal("+QUEUED"))
+		Expect(client.do("SELECT", "other")).To(Equal("+QUEUED"))
+		Expect(client.do("SET", "k1", "v1")).To(Equal("+QUEUED"))
+		Expect(client.do("GET", "k1")).To(Equal("+QUEUED"))
+		Expect(client.do("EXEC")).To(Equal([]interface{}{
+			int64(2),
+			"+OK",
+			"+OK",
+			[]byte("v1"),
+		}))
+		Expect(client.do("GET", "k1")).To(Equal([]byte("v1")))
+	})
+	It("discards MULTI", func() {
+		Expect(client.do("MULTI")).To(Equal("+OK"))
+		Expect(client.do("SET", "k1", "v1")).To(Equal("+QUEUED"))
+		Expect(client.do("DISCARD")).To(Equal("+OK"))
+		Expect(client.do("GET", "k1")).To(BeNil())
+		Expect(client.do("EXEC")).To(Equal("-ERR EXEC without MULTI"))
+	})
+	It("aborts EXEC after invalid commands", func() {
+		Expect(client.do("MULTI")).To(Equal("+OK"))
+		Expect(client.do("SET", "k1", "v1")).To(Equal("+QUEUED"))
+		Expect(client.do("GET")).To(HavePrefix("-ERR wrong number of arguments"))
+		Expect(client.do("EXEC")).To(HavePrefix("-EXECABORT"))
+		Expect(client.do("GET", "k1")).To(BeNil())
+	})
+	It("rejects unknown commands", func() {
+		Expect(client.do("FLUSHALL")).To(Equal("-ERR unknown command 'FLUSHALL'"))
+		Expect(client.do("PING", "hello")).To(Equal([]byte("hello")))
+	})
+})
@@ -0,0 +1,164 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"slices"
+	"strconv"
+
+	"github.com/bborbe/errors"
+)
+
+// maxArgs limits the number of arguments of a command.
+const maxArgs = 1 << 16
+
+// maxInlineBytes limits the length of inline commands and array headers.
+const maxInlineBytes = 64 << 10
+
+// bulkChunkBytes is the most a bulk string buffer grows ahead of the received bytes.
+const bulkChunkBytes = 64 << 10
+
+// errProtocol is returned for malformed requests, the connection is closed.
+var errProtocol = errors.New(context.Background(), "protocol error")
+
+// readCommand reads a command as array of bulk strings or as inline command
+// separated by whitespace. Empty inline commands return no arguments.
+// Bulk strings longer than maxBulkBytes and commands whose bulk strings sum
+// up to more than maxCommandBytes are protocol errors.
+func readCommand(
+	ctx context.Context,
+	r *bufio.Reader,
+	maxBulkBytes int,
+	maxCommandBytes int,
+) ([][]byte, error) {
+	line, err := readLine(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 || line[0] != '*' {
+		return bytes.Fields(line), nil
+	}
+	count, err := parseLength(ctx, line[1:], maxArgs)
+	if err != nil {
+		return nil, err
+	}
+	args := make([][]byte, 0, min(count, 64))
+	remaining := maxCommandBytes
+	for i := 0; i < count; i++ {
+		line, err := readLine(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		if len(line) == 0 || line[0] != '$' {
+			return nil, errors.Wrapf(ctx, errProtocol, "expected '$', got %q", line)
+		}
+		length, err := parseLength(ctx, line[1:], maxBulkBytes)
+		if err != nil {
+			return nil, err
+		}
+		if length > remaining {
+			return nil, errors.Wrapf(ctx, errProtocol, "command too large")
+		}
+		remaining -= length
+		arg, err := readBulk(ctx, r, length)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+// readBulk reads a bulk string of length bytes and its CRLF in chunks of
+// bulkChunkBytes, so the buffer grows with the received bytes and not with
+// the length declared by the client.
+func readBulk(ctx context.Context, r *bufio.Reader, length int) ([]byte, error) {
+	arg := make([]byte, 0, min(length+2, bulkChunkBytes))
+	for len(arg) < length+2 {
+		start := len(arg)
+		n := min(length+2-start, bulkChunkBytes)
+		arg = slices.Grow(arg, n)[:start+n]
+		if _, err := io.ReadFull(r, arg[start:]); err != nil {
+			return nil, errors.Wrapf(ctx, err, "read bulk string failed")
+		}
+	}
+	if !bytes.HasSuffix(arg, []byte("\r\n")) {
+		return nil, errors.Wrapf(ctx, errProtocol, "bulk string not terminated by CRLF")
+	}
+	return arg[:length], nil
+}
+
+// readLine returns the next line without the trailing CRLF or LF.
+func readLine(ctx context.Context, r *bufio.Reader) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, isPrefix, err := r.ReadLine()
+		if err != nil {
+			return nil, err
+		}
+		line = append(line, chunk...)
+		if len(line) > maxInlineBytes {
+			return nil, errors.Wrapf(ctx, errProtocol, "line too long")
+		}
+		if !isPrefix {
+			return line, nil
+		}
+	}
+}
+
+func parseLength(ctx context.Context, value []byte, max int) (int, error) {
+	length, err := strconv.Atoi(string(value))
+	if err != nil || length < 0 || length > max {
+		return 0, errors.Wrapf(ctx, errProtocol, "invalid length '%s'", value)
+	}
+	return length, nil
+}
+
+// simpleString is written as +OK.
+type simpleString string
+
+// errorReply is written as -ERR message, the message must not contain CRLF.
+type errorReply string
+
+// nilReply is written as null bulk string.
+type nilReply struct{}
+
+// appendReply appends a simpleString, errorReply, int64, []byte, nilReply or
+// an []interface{} of these to buf.
+func appendReply(buf []byte, reply interface{}) []byte {
+	switch v := reply.(type) {
+	case simpleString:
+		buf = append(buf, '+')
+		buf = append(buf, v...)
+	case errorReply:
+		buf = append(buf, '-')
+		buf = append(buf, v...)
+	case int64:
+		buf = append(buf, ':')
+		buf = strconv.AppendInt(buf, v, 10)
+	case []byte:
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(v)), 10)
+		buf = append(buf, "\r\n"...)
+		buf = append(buf, v...)
+	case nilReply:
+		buf = append(buf, "$-1"...)
+	case []interface{}:
+		buf = append(buf, '*')
+		buf = strconv.AppendInt(buf, int64(len(v)), 10)
+		buf = append(buf, "\r\n"...)
+		for _, element := range v {
+			buf = appendReply(buf, element)
+		}
+		return buf
+	default:
+		buf = append(buf, "-ERR unsupported reply"...)
+	}
+	return append(buf, "\r\n"...)
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resp_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+	"github.com/bborbe/boltkv/resp"
+)
+
+// testClient sends commands as arrays of bulk strings and decodes replies.
+type testClient struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func (c *testClient) send(args ...string) {
+	buf := "*" + strconv.Itoa(len(args)) + "\r\n"
+	for _, arg := range args {
+		buf += "$" + strconv.Itoa(len(arg)) + "\r\n" + arg + "\r\n"
+	}
+	_, err := c.conn.Write([]byte(buf))
+	Expect(err).To(BeNil())
+}
+
+func (c *testClient) do(args ...string) interface{} {
+	c.send(args...)
+	return c.read()
+}
+
+// read returns a reply as string ("+OK", "-ERR ..."), int64, []byte, nil or []interface{}.
+func (c *testClient) read() interface{} {
+	line, err := c.r.ReadString('\n')
+	Expect(err).To(BeNil())
+	line = strings.TrimSuffix(line, "\r\n")
+	switch line[0] {
+	case '+', '-':
+		return line
+	case ':':
+		value, err := strconv.ParseInt(line[1:], 10, 64)
+		Expect(err).To(BeNil())
+		return value
+	case '$':
+		length, err := strconv.Atoi(line[1:])
+		Expect(err).To(BeNil())
+		if length < 0 {
+			return nil
+		}
+		value := make([]byte, length+2)
+		_, err = io.ReadFull(c.r, value)
+		Expect(err).To(BeNil())
+		return value[:length]
+	case '*':
+		length, err := strconv.Atoi(line[1:])
+		Expect(err).To(BeNil())
+		values := []interface{}{}
+		for i := 0; i < length; i++ {
+			values = append(values, c.read())
+		}
+		return values
+	default:
+		Fail("unexpected reply " + line)
+		return nil
+	}
+}
+
+// startServer serves db on a loopback listener until ctx is canceled.
+func startServer(ctx context.Context, db boltkv.DB, opts resp.Options) (string, <-chan error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).To(BeNil())
+	errs := make(chan error, 1)
+	go func() {
+		errs <- resp.NewServer(db, opts).Serve(ctx, listener)
+	}()
+	return listener.Addr().String(), errs
+}
+
+func dial(addr string) *testClient {
+	conn, err := net.DialTimeout("tcp", addr, time.Second)
+	Expect(err).To(BeNil())
+	return &testClient{conn: conn, r: bufio.NewReader(conn)}
+}
+
+var _ = Describe("Server", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var db boltkv.DB
+	var addr string
+	var errs <-chan error
+	var client *testClient
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		var err error
+		db, err = boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		addr, errs = startServer(ctx, db, resp.Options{})
+		client = dial(addr)
+	})
+	AfterEach(func() {
+		cancel()
+		_ = client.conn.Close()
+		_ = db.Close()
+		_ = db.Remove()
+	})
+	It("answers inline commands", func() {
+		_, err := client.conn.Write([]byte("PING\r\n\r\nSET k1 v1\r\nGET k1\r\n"))
+		Expect(err).To(BeNil())
+		Expect(client.read()).To(Equal("+PONG"))
+		Expect(client.read()).To(Equal("+OK"))
+		Expect(client.read()).To(Equal([]byte("v1")))
+	})
+	It("answers pipelined commands in order", func() {
+		for i := 0; i < 100; i++ {
+			client.send("INCR", "counter")
+		}
+		for i := 1; i <= 100; i++ {
+			Expect(client.read()).To(Equal(int64(i)))
+		}
+	})
+	It("closes the connection on protocol errors", func() {
+		_, err := client.conn.Write([]byte("*1\r\n+GET\r\n"))
+		Expect(err).To(BeNil())
+		Expect(client.read()).To(HavePrefix("-ERR Protocol error"))
+		_, err = client.r.ReadByte()
+		Expect(err).To(Equal(io.EOF))
+	})
+	It("reads bulk strings larger than a read chunk", func() {
+		value := strings.Repeat("v", 200<<10)
+		Expect(client.do("SET", "k1", value)).To(Equal("+OK"))
+		Expect(client.do("GET", "k1")).To(Equal([]byte(value)))
+	})
+	Context("with MaxCommandBytes", func() {
+		var limited *testClient
+		BeforeEach(func() {
+			limitedAddr, _ := startServer(ctx, db, resp.Options{
+				Bucket:          "limited",
+				MaxBulkBytes:    8,
+				MaxCommandBytes: 12,
+			})
+			limited = dial(limitedAddr)
+		})
+		AfterEach(func() {
+			_ = limited.conn.Close()
+		})
+		It("accepts commands within the limit", func() {
+			Expect(limited.do("SET", "k1", "v1")).To(Equal("+OK"))
+		})
+		It("rejects bulk strings above MaxBulkBytes", func() {
+			limited.send("SET", "k1", "123456789")
+			Expect(limited.read()).To(HavePrefix("-ERR Protocol error"))
+		})
+		It("rejects commands above MaxCommandBytes", func() {
+			limited.send("SET", "k1", "12345678")
+			Expect(limited.read()).To(HavePrefix("-ERR Protocol error"))
+			_, err := limited.r.ReadByte()
+			Expect(err).To(Equal(io.EOF))
+		})
+	})
+	It("closes the connection on QUIT", func() {
+		Expect(client.do("QUIT")).To(Equal("+OK"))
+		_, err := client.r.ReadByte()
+		Expect(err).To(Equal(io.EOF))
+	})
+	It("stops on cancel", func() {
+		Expect(client.do("PING")).To(Equal("+PONG"))
+		cancel()
+		Eventually(errs).Should(Receive(BeNil()))
+		_, err := client.r.ReadByte()
+		Expect(err).NotTo(BeNil())
+	})
+	It("serves concurrent connections", func() {
+		other := dial(addr)
+		defer other.conn.Close()
+		Expect(client.do("SET", "k1", "v1")).To(Equal("+OK"))
+		Expect(other.do("GET", "k1")).To(Equal([]byte("v1")))
+	})
+})
@@ -0,0 +1,434 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package server exposes a boltkv database over HTTP with JSON responses.
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+	"github.com/golang/glog"
+
+	"github.com/bborbe/boltkv"
+)
+
+const (
+	// DefaultMaxRequestBytes limits the body of write requests.
+	DefaultMaxRequestBytes = 1 << 20
+	// DefaultPageSize is the number of pairs of a scan page without limit.
+	DefaultPageSize = 100
+	// DefaultMaxPageSize is the largest accepted scan limit.
+	DefaultMaxPageSize = 1000
+)
+
+// Options configures NewHandler.
+type Options struct {
+	// Token enables bearer token authentication if not empty.
+	Token string
+	// MaxRequestBytes limits request bodies, defaults to DefaultMaxRequestBytes.
+	MaxRequestBytes int64
+	// MaxPageSize limits scan pages, defaults to DefaultMaxPageSize.
+	MaxPageSize int
+	// ReadOnly rejects all writes with 405.
+	ReadOnly bool
+}
+
+// NewHandler returns the REST API of db:
+//
+//	GET    /buckets                        list buckets
+//	GET    /buckets/{bucket}               404 if the bucket does not exist
+//	PUT    /buckets/{bucket}               create a bucket
+//	DELETE /buckets/{bucket}               delete a bucket
+//	GET    /buckets/{bucket}/keys          scan, see Page
+//	GET    /buckets/{bucket}/keys/{key}    get the raw value
+//	PUT    /buckets/{bucket}/keys/{key}    set the value to the request body
+//	DELETE /buckets/{bucket}/keys/{key}    delete a key
+//	GET    /stats                          key count and size of all buckets
+//	GET    /backup                         download a consistent copy of the database
+//	POST   /batch                          apply a Batch in one Update
+//
+// Keys in paths and the scan parameters prefix, start and end are decoded
+// with the key_encoding parameter, utf8 by default. Reads run in a View.
+func NewHandler(db boltkv.DB, opts Options) http.Handler {
+	if opts.MaxRequestBytes <= 0 {
+		opts.MaxRequestBytes = DefaultMaxRequestBytes
+	}
+	if opts.MaxPageSize <= 0 {
+		opts.MaxPageSize = DefaultMaxPageSize
+	}
+	s := &server{db: db, opts: opts}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /buckets", s.handle(s.listBuckets))
+	mux.HandleFunc("GET /buckets/{bucket}", s.handle(s.getBucket))
+	mux.HandleFunc("PUT /buckets/{bucket}", s.handleWrite(s.createBucket))
+	mux.HandleFunc("DELETE /buckets/{bucket}", s.handleWrite(s.deleteBucket))
+	mux.HandleFunc("GET /buckets/{bucket}/keys", s.handle(s.scan))
+	mux.HandleFunc("GET /buckets/{bucket}/keys/{key}", s.handle(s.get))
+	mux.HandleFunc("PUT /buckets/{bucket}/keys/{key}", s.handleWrite(s.put))
+	mux.HandleFunc("DELETE /buckets/{bucket}/keys/{key}", s.handleWrite(s.delete))
+	mux.HandleFunc("GET /stats", s.handle(s.stats))
+	mux.HandleFunc("GET /backup", s.handle(s.backup))
+	mux.HandleFunc("POST /batch", s.handleWrite(s.batch))
+	return s.authenticate(mux)
+}
+
+type server struct {
+	db   boltkv.DB
+	opts Options
+}
+
+type handlerFunc func(ctx context.Context, resp http.ResponseWriter, req *http.Request) error
+
+// statusError sets the HTTP status of an error returned by a handler.
+type statusError struct {
+	Status int
+	Err    error
+}
+
+func (s statusError) Error() string {
+	return s.Err.Error()
+}
+
+func (s statusError) Unwrap() error {
+	return s.Err
+}
+
+func badRequest(err error) error {
+	return statusError{Status: http.StatusBadRequest, Err: err}
+}
+
+func (s *server) authenticate(next http.Handler) http.Handler {
+	if s.opts.Token == "" {
+		return next
+	}
+	expected := []byte("Bearer " + s.opts.Token)
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		actual := []byte(req.Header.Get("Authorization"))
+		if subtle.ConstantTimeCompare(actual, expected) != 1 {
+			resp.Header().Set("WWW-Authenticate", `Bearer realm="boltkv"`)
+			writeError(resp, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		next.ServeHTTP(resp, req)
+	})
+}
+
+func (s *server) handle(fn handlerFunc) http.HandlerFunc {
+	return func(resp http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		req.Body = http.MaxBytesReader(resp, req.Body, s.opts.MaxRequestBytes)
+		if err := fn(ctx, resp, req); err != nil {
+			status := errorStatus(err)
+			if status >= http.StatusInternalServerError {
+				glog.Warningf("%s %s failed: %v", req.Method, req.URL.Path, err)
+			}
+			writeJSON(resp, status, ErrorResponse{Error: err.Error(), Code: errorCode(err)})
+		}
+	}
+}
+
+func (s *server) handleWrite(fn handlerFunc) http.HandlerFunc {
+	if s.opts.ReadOnly {
+		return func(resp http.ResponseWriter, req *http.Request) {
+			writeError(resp, http.StatusMethodNotAllowed, "server is read-only")
+		}
+	}
+	return s.handle(fn)
+}
+
+func errorStatus(err error) int {
+	var statusErr statusError
+	var maxBytesError *http.MaxBytesError
+	switch {
+	case errors.As(err, &statusErr):
+		return statusErr.Status
+	case errors.As(err, &maxBytesError):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, libkv.BucketNotFoundError), errors.Is(err, libkv.KeyNotFoundError):
+		return http.StatusNotFound
+	case errors.Is(err, libkv.BucketAlreadyExistsError):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Error codes of ErrorResponse for the libkv errors.
+const (
+	ErrorCodeBucketNotFound      = "bucket_not_found"
+	ErrorCodeBucketAlreadyExists = "bucket_already_exists"
+	ErrorCodeKeyNotFound         = "key_not_found"
+)
+
+// ErrorResponse is the body of all failed requests.
+type ErrorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code,omitempty"`
+}
+
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, libkv.BucketNotFoundError):
+		return ErrorCodeBucketNotFound
+	case errors.Is(err, libkv.BucketAlreadyExistsError):
+		return ErrorCodeBucketAlreadyExists
+	case errors.Is(err, libkv.KeyNotFoundError):
+		return ErrorCodeKeyNotFound
+	default:
+		return ""
+	}
+}
+
+func writeError(resp http.ResponseWriter, status int, message string) {
+	writeJSON(resp, status, ErrorResponse{Error: message})
+}
+
+func writeJSON(resp http.ResponseWriter, status int, value interface{}) {
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(status)
+	if err := json.NewEncoder(resp).Encode(value); err != nil {
+		glog.Warningf("write response failed: %v", err)
+	}
+}
+
+func bucketName(req *http.Request) libkv.BucketName {
+	return libkv.NewBucketName(req.PathValue("bucket"))
+}
+
+// keyEncoding returns the key_encoding parameter, utf8 if missing.
+func keyEncoding(ctx context.Context, req *http.Request) (boltkv.Encoding, error) {
+	name := req.URL.Query().Get("key_encoding")
+	if name == "" {
+		return boltkv.EncodingUTF8, nil
+	}
+	encoding, err := boltkv.ParseKeyEncoding(ctx, name)
+	if err != nil {
+		return "", badRequest(err)
+	}
+	return encoding, nil
+}
+
+func decodeParam(ctx context.Context, encoding boltkv.Encoding, value string) ([]byte, error) {
+	if value == "" {
+		return nil, nil
+	}
+	result, err := encoding.Decode(ctx, value)
+	if err != nil {
+		return nil, badRequest(err)
+	}
+	return result, nil
+}
+
+func pathKey(ctx context.Context, req *http.Request) ([]byte, error) {
+	encoding, err := keyEncoding(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	key, err := encoding.Decode(ctx, req.PathValue("key"))
+	if err != nil {
+		return nil, badRequest(err)
+	}
+	return key, nil
+}
+
+type bucketResponse struct {
+	Bucket string `json:"bucket"`
+	Keys   *int64 `json:"keys,omitempty"`
+	SizeB  *int64 `json:"size,omitempty"`
+}
+
+func (s *server) listBuckets(
+	ctx context.Context,
+	resp http.ResponseWriter,
+	req *http.Request,
+) error {
+	result := []bucketResponse{}
+	err := boltkv.ListBuckets(ctx, s.db, func(bucketName libkv.BucketName) error {
+		result = append(result, bucketResponse{Bucket: bucketName.String()})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	writeJSON(resp, http.StatusOK, result)
+	return nil
+}
+
+func (s *server) getBucket(
+	ctx context.Context,
+	resp http.ResponseWriter,
+	req *http.Request,
+) error {
+	err := s.db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		_, err := tx.Bucket(ctx, bucketName(req))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	writeJSON(resp, http.StatusOK, bucketResponse{Bucket: bucketName(req).String()})
+	return nil
+}
+
+func (s *server) createBucket(
+	ctx context.Context,
+	resp http.ResponseWriter,
+	req *http.Request,
+) error {
+	err := s.db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(ctx, bucketName(req))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	resp.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (s *server) deleteBucket(
+	ctx context.Context,
+	resp http.ResponseWriter,
+	req *http.Request,
+) error {
+	if err := boltkv.DeleteBucket(ctx, s.db, bucketName(req)); err != nil {
+		return err
+	}
+	resp.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (s *server) get(
+	ctx context.Context,
+	resp http.ResponseWriter,
+	req *http.Request,
+) error {
+	key, err := pathKey(ctx, req)
+	if err != nil {
+		return err
+	}
+	value, err := boltkv.GetValue(ctx, s.db, bucketName(req), key)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		return errors.Wrapf(ctx, libkv.KeyNotFoundError, "key %q not found", key)
+	}
+	resp.Header().Set("Content-Type", "application/octet-stream")
+	_, err = resp.Write(value)
+	return err
+}
+
+func (s *server) put(
+	ctx context.Context,
+	resp http.ResponseWriter,
+	req *http.Request,
+) error {
+	key, err := pathKey(ctx, req)
+	if err != nil {
+		return err
+	}
+	value, err := io.ReadAll(req.Body)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "read body failed")
+	}
+	err = s.db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(ctx, bucketName(req))
+		if err != nil {
+			return err
+		}
+		return bucket.Put(ctx, key, value)
+	})
+	if err != nil {
+		return err
+	}
+	resp.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (s *server) delete(
+	ctx context.Context,
+	resp http.ResponseWriter,
+	req *http.Request,
+) error {
+	key, err := pathKey(ctx, req)
+	if err != nil {
+		return err
+	}
+	if err := boltkv.DeleteValue(ctx, s.db, bucketName(req), key); err != nil {
+		return err
+	}
+	resp.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (s *server) stats(
+	ctx context.Context,
+	resp http.ResponseWriter,
+	req *http.Request,
+) error {
+	stats, err := boltkv.BucketStats(ctx, s.db)
+	if err != nil {
+		return err
+	}
+	result := make([]bucketResponse, 0, len(stats))
+	for _, stat := range stats {
+		result = append(result, bucketResponse{
+			Bucket: stat.Name.String(),
+			Keys:   &stat.KeyCount,
+			SizeB:  &stat.SizeB,
+		})
+	}
+	writeJSON(resp, http.StatusOK, result)
+	return nil
+}
+
+func (s *server) backup(
+	ctx context.Context,
+	resp http.ResponseWriter,
+	req *http.Request,
+) error {
+	resp.Header().Set("Content-Type", "application/octet-stream")
+	resp.Header().Set("Content-Disposition", `attachment; filename="bolt.db"`)
+	writer := &countingWriter{writer: resp}
+	if _, err := boltkv.Backup(ctx, s.db, writer); err != nil {
+		if writer.written == 0 {
+			// nothing is sent yet, handle reports the error
+			resp.Header().Del("Content-Disposition")
+			return err
+		}
+		// the status is sent already, the client sees a truncated body
+		glog.Warningf("backup failed after %d bytes: %v", writer.written, err)
+	}
+	return nil
+}
+
+// countingWriter counts the bytes written, to know if the status is sent already.
+type countingWriter struct {
+	writer  io.Writer
+	written int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.written += int64(n)
+	return n, err
+}
+
+// isTrue parses boolean query parameters.
+func isTrue(value string) bool {
+	switch strings.ToLower(value) {
+	case "1", "true", "yes":
+		return true
+	default:
+		return false
+	}
+}
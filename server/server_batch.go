@@ -0,0 +1,95 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/bborbe/errors"
+	libkv "github.com/bborbe/kv"
+)
+
+// OperationType is the kind of an Operation.
+type OperationType string
+
+const (
+	// OperationCreateBucket fails with 409 if the bucket exists.
+	OperationCreateBucket OperationType = "create_bucket"
+	// OperationDeleteBucket fails with 404 if the bucket does not exist.
+	OperationDeleteBucket OperationType = "delete_bucket"
+	// OperationPut fails with 404 if the bucket does not exist.
+	OperationPut OperationType = "put"
+	// OperationDelete fails with 404 if the bucket does not exist.
+	OperationDelete OperationType = "delete"
+)
+
+// Operation is a single write of a Batch, bytes are base64 in JSON.
+type Operation struct {
+	Type   OperationType `json:"type"`
+	Bucket []byte        `json:"bucket"`
+	Key    []byte        `json:"key,omitempty"`
+	Value  []byte        `json:"value,omitempty"`
+}
+
+// Batch is the body of POST /batch. All operations are applied in order in
+// one Update, if one fails none is applied.
+type Batch struct {
+	Operations []Operation `json:"operations"`
+}
+
+func (s *server) batch(
+	ctx context.Context,
+	resp http.ResponseWriter,
+	req *http.Request,
+) error {
+	var batch Batch
+	if err := json.NewDecoder(req.Body).Decode(&batch); err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			return err
+		}
+		return badRequest(errors.Wrapf(ctx, err, "decode batch failed"))
+	}
+	err := s.db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+		for i, operation := range batch.Operations {
+			if err := applyOperation(ctx, tx, operation); err != nil {
+				return errors.Wrapf(ctx, err, "operation %d failed", i)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	resp.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func applyOperation(ctx context.Context, tx libkv.Tx, operation Operation) error {
+	bucketName := libkv.BucketName(operation.Bucket)
+	switch operation.Type {
+	case OperationCreateBucket:
+		_, err := tx.CreateBucket(ctx, bucketName)
+		return err
+	case OperationDeleteBucket:
+		return tx.DeleteBucket(ctx, bucketName)
+	case OperationPut:
+		bucket, err := tx.Bucket(ctx, bucketName)
+		if err != nil {
+			return err
+		}
+		return bucket.Put(ctx, operation.Key, operation.Value)
+	case OperationDelete:
+		bucket, err := tx.Bucket(ctx, bucketName)
+		if err != nil {
+			return err
+		}
+		return bucket.Delete(ctx, operation.Key)
+	default:
+		return badRequest(errors.Errorf(ctx, "unknown operation type '%s'", operation.Type))
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+	"github.com/bborbe/boltkv/server"
+)
+
+var _ = Describe("Batch", func() {
+	var ctx context.Context
+	var db boltkv.DB
+	var httpServer *httptest.Server
+	post := func(batch server.Batch) (int, server.ErrorResponse) {
+		body, err := json.Marshal(batch)
+		Expect(err).To(BeNil())
+		resp, err := httpServer.Client().Post(
+			httpServer.URL+"/batch",
+			"application/json",
+			bytes.NewReader(body),
+		)
+		Expect(err).To(BeNil())
+		defer resp.Body.Close()
+		var errorResponse server.ErrorResponse
+		_ = json.NewDecoder(resp.Body).Decode(&errorResponse)
+		return resp.StatusCode, errorResponse
+	}
+	bucketNames := func() libkv.BucketNames {
+		var names libkv.BucketNames
+		err := db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			var err error
+			names, err = tx.ListBucketNames(ctx)
+			return err
+		})
+		Expect(err).To(BeNil())
+		return names
+	}
+	BeforeEach(func() {
+		ctx = context.Background()
+		var err error
+		db, err = boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		httpServer = httptest.NewServer(server.NewHandler(db, server.Options{}))
+	})
+	AfterEach(func() {
+		httpServer.Close()
+		_ = db.Close()
+		_ = db.Remove()
+	})
+	It("applies all operations", func() {
+		status, _ := post(server.Batch{Operations: []server.Operation{
+			{Type: server.OperationCreateBucket, Bucket: []byte("a")},
+			{Type: server.OperationPut, Bucket: []byte("a"), Key: []byte("k1"), Value: []byte("v1")},
+			{Type: server.OperationPut, Bucket: []byte("a"), Key: []byte("k2"), Value: []byte("v2")},
+			{Type: server.OperationDelete, Bucket: []byte("a"), Key: []byte("k1")},
+		}})
+		Expect(status).To(Equal(http.StatusNoContent))
+		var keys []string
+		err := db.View(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.Bucket(ctx, libkv.NewBucketName("a"))
+			if err != nil {
+				return err
+			}
+			it := bucket.Iterator()
+			defer it.Close()
+			for it.Rewind(); it.Valid(); it.Next() {
+				keys = append(keys, string(it.Item().Key()))
+			}
+			return nil
+		})
+		Expect(err).To(BeNil())
+		Expect(keys).To(Equal([]string{"k2"}))
+	})
+	It("applies nothing if an operation fails", func() {
+		status, errorResponse := post(server.Batch{Operations: []server.Operation{
+			{Type: server.OperationCreateBucket, Bucket: []byte("a")},
+			{Type: server.OperationPut, Bucket: []byte("b"), Key: []byte("k1"), Value: []byte("v1")},
+		}})
+		Expect(status).To(Equal(http.StatusNotFound))
+		Expect(errorResponse.Code).To(Equal(server.ErrorCodeBucketNotFound))
+		Expect(bucketNames()).To(BeEmpty())
+	})
+	It("returns conflict for existing buckets", func() {
+		status, errorResponse := post(server.Batch{Operations: []server.Operation{
+			{Type: server.OperationCreateBucket, Bucket: []byte("a")},
+			{Type: server.OperationCreateBucket, Bucket: []byte("a")},
+		}})
+		Expect(status).To(Equal(http.StatusConflict))
+		Expect(errorResponse.Code).To(Equal(server.ErrorCodeBucketAlreadyExists))
+	})
+	It("rejects unknown operations", func() {
+		status, _ := post(server.Batch{Operations: []server.Operation{
+			{Type: "banana", Bucket: []byte("a")},
+		}})
+		Expect(status).To(Equal(http.StatusBadRequest))
+	})
+})
@@ -0,0 +1,108 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+
+	"github.com/bborbe/errors"
+
+	"github.com/bborbe/boltkv"
+)
+
+// Pair is a key and value of a scan page, encoded as base64 in JSON.
+type Pair struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value,omitempty"`
+}
+
+// Page is the response of GET /buckets/{bucket}/keys with the parameters
+// prefix, start (inclusive), end (exclusive), reverse, keys_only, limit and
+// cursor. Cursor is set if more pairs follow; passing it with otherwise
+// unchanged parameters returns the next page.
+type Page struct {
+	Pairs  []Pair `json:"pairs"`
+	Cursor string `json:"cursor,omitempty"`
+}
+
+func (s *server) scan(
+	ctx context.Context,
+	resp http.ResponseWriter,
+	req *http.Request,
+) error {
+	query := req.URL.Query()
+	r, limit, err := s.parseScan(ctx, req)
+	if err != nil {
+		return err
+	}
+	keysOnly := isTrue(query.Get("keys_only"))
+	page := Page{Pairs: []Pair{}}
+	// one more pair than requested tells whether another page follows
+	r.Limit = limit + 1
+	err = boltkv.IterateRange(ctx, s.db, bucketName(req), r, func(key []byte, value []byte) error {
+		if len(page.Pairs) == limit {
+			page.Cursor = base64.RawURLEncoding.EncodeToString(key)
+			return nil
+		}
+		pair := Pair{Key: bytes.Clone(key)}
+		if !keysOnly {
+			pair.Value = bytes.Clone(value)
+		}
+		page.Pairs = append(page.Pairs, pair)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	writeJSON(resp, http.StatusOK, page)
+	return nil
+}
+
+// parseScan returns the range and page size of the scan parameters.
+func (s *server) parseScan(ctx context.Context, req *http.Request) (boltkv.ScanRange, int, error) {
+	query := req.URL.Query()
+	encoding, err := keyEncoding(ctx, req)
+	if err != nil {
+		return boltkv.ScanRange{}, 0, err
+	}
+	var r boltkv.ScanRange
+	if r.Prefix, err = decodeParam(ctx, encoding, query.Get("prefix")); err != nil {
+		return boltkv.ScanRange{}, 0, err
+	}
+	if r.Start, err = decodeParam(ctx, encoding, query.Get("start")); err != nil {
+		return boltkv.ScanRange{}, 0, err
+	}
+	if r.End, err = decodeParam(ctx, encoding, query.Get("end")); err != nil {
+		return boltkv.ScanRange{}, 0, err
+	}
+	r.Reverse = isTrue(query.Get("reverse"))
+	limit := DefaultPageSize
+	if value := query.Get("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return boltkv.ScanRange{}, 0, badRequest(errors.Errorf(ctx, "invalid limit '%s'", value))
+		}
+	}
+	if limit > s.opts.MaxPageSize {
+		limit = s.opts.MaxPageSize
+	}
+	if value := query.Get("cursor"); value != "" {
+		cursor, err := base64.RawURLEncoding.DecodeString(value)
+		if err != nil {
+			return boltkv.ScanRange{}, 0, badRequest(errors.Wrapf(ctx, err, "invalid cursor"))
+		}
+		if r.Reverse {
+			// the cursor is the next key to return, End is exclusive
+			r.End = append(cursor, 0)
+		} else {
+			r.Start = cursor
+		}
+	}
+	return r, limit, nil
+}
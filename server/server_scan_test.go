@@ -0,0 +1,107 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	libkv "github.com/bborbe/kv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+	"github.com/bborbe/boltkv/server"
+)
+
+var _ = Describe("Scan", func() {
+	var ctx context.Context
+	var db boltkv.DB
+	var handler http.Handler
+	scan := func(query string) (int, server.Page) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/buckets/a/keys?"+query, nil))
+		var page server.Page
+		if recorder.Code == http.StatusOK {
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &page)).To(Succeed())
+		}
+		return recorder.Code, page
+	}
+	keys := func(page server.Page) []string {
+		result := []string{}
+		for _, pair := range page.Pairs {
+			result = append(result, string(pair.Key))
+		}
+		return result
+	}
+	BeforeEach(func() {
+		ctx = context.Background()
+		var err error
+		db, err = boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		Expect(db.Update(ctx, func(ctx context.Context, tx libkv.Tx) error {
+			bucket, err := tx.CreateBucket(ctx, libkv.NewBucketName("a"))
+			Expect(err).To(BeNil())
+			for i := 0; i < 5; i++ {
+				key := []byte(fmt.Sprintf("k%d", i))
+				Expect(bucket.Put(ctx, key, key)).To(Succeed())
+			}
+			return bucket.Put(ctx, []byte("x"), []byte("x"))
+		})).To(Succeed())
+		handler = server.NewHandler(db, server.Options{MaxPageSize: 3})
+	})
+	AfterEach(func() {
+		_ = db.Close()
+		_ = db.Remove()
+	})
+	It("returns pairs of a prefix", func() {
+		status, page := scan("prefix=k&limit=2")
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(keys(page)).To(Equal([]string{"k0", "k1"}))
+		Expect(page.Pairs[0].Value).To(Equal([]byte("k0")))
+		Expect(page.Cursor).NotTo(BeEmpty())
+	})
+	It("pages through a range with cursors", func() {
+		var all []string
+		cursor := ""
+		for {
+			status, page := scan("start=k1&end=x&limit=2&cursor=" + url.QueryEscape(cursor))
+			Expect(status).To(Equal(http.StatusOK))
+			all = append(all, keys(page)...)
+			if page.Cursor == "" {
+				break
+			}
+			cursor = page.Cursor
+		}
+		Expect(all).To(Equal([]string{"k1", "k2", "k3", "k4"}))
+	})
+	It("pages backwards", func() {
+		status, page := scan("reverse=true&limit=2&keys_only=true")
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(keys(page)).To(Equal([]string{"x", "k4"}))
+		Expect(page.Pairs[0].Value).To(BeNil())
+		_, page = scan("reverse=true&limit=2&cursor=" + url.QueryEscape(page.Cursor))
+		Expect(keys(page)).To(Equal([]string{"k3", "k2"}))
+	})
+	It("caps the limit at MaxPageSize", func() {
+		_, page := scan("limit=100")
+		Expect(page.Pairs).To(HaveLen(3))
+	})
+	It("returns 400 for invalid parameters", func() {
+		status, _ := scan("limit=-1")
+		Expect(status).To(Equal(http.StatusBadRequest))
+		status, _ = scan("cursor=!")
+		Expect(status).To(Equal(http.StatusBadRequest))
+	})
+	It("returns 404 for missing buckets", func() {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/buckets/b/keys", nil))
+		Expect(recorder.Code).To(Equal(http.StatusNotFound))
+	})
+})
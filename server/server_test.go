@@ -0,0 +1,141 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/boltkv"
+	"github.com/bborbe/boltkv/server"
+)
+
+var _ = Describe("Server", func() {
+	var ctx context.Context
+	var db boltkv.DB
+	var opts server.Options
+	var httpServer *httptest.Server
+	do := func(method string, path string, body string) (int, string) {
+		req, err := http.NewRequestWithContext(
+			ctx,
+			method,
+			httpServer.URL+path,
+			strings.NewReader(body),
+		)
+		Expect(err).To(BeNil())
+		req.Header.Set("Authorization", "Bearer secret")
+		resp, err := httpServer.Client().Do(req)
+		Expect(err).To(BeNil())
+		defer resp.Body.Close()
+		content, err := io.ReadAll(resp.Body)
+		Expect(err).To(BeNil())
+		return resp.StatusCode, string(content)
+	}
+	status := func(method string, path string, body string) int {
+		status, _ := do(method, path, body)
+		return status
+	}
+	BeforeEach(func() {
+		ctx = context.Background()
+		var err error
+		db, err = boltkv.OpenTemp(ctx)
+		Expect(err).To(BeNil())
+		opts = server.Options{}
+	})
+	JustBeforeEach(func() {
+		httpServer = httptest.NewServer(server.NewHandler(db, opts))
+		Expect(status(http.MethodPut, "/buckets/a/keys/k1", "v1")).To(Equal(http.StatusNoContent))
+	})
+	AfterEach(func() {
+		httpServer.Close()
+		_ = db.Close()
+		_ = db.Remove()
+	})
+	It("gets a value", func() {
+		_, body := do(http.MethodGet, "/buckets/a/keys/k1", "")
+		Expect(body).To(Equal("v1"))
+	})
+	It("gets a hex encoded key", func() {
+		code, body := do(http.MethodGet, "/buckets/a/keys/6b31?key_encoding=hex", "")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(body).To(Equal("v1"))
+	})
+	It("returns 404 for missing keys and buckets", func() {
+		Expect(status(http.MethodGet, "/buckets/a/keys/missing", "")).To(Equal(http.StatusNotFound))
+		Expect(status(http.MethodGet, "/buckets/b/keys/k1", "")).To(Equal(http.StatusNotFound))
+	})
+	It("returns 400 for invalid key encodings", func() {
+		Expect(status(http.MethodGet, "/buckets/a/keys/zz?key_encoding=hex", "")).
+			To(Equal(http.StatusBadRequest))
+	})
+	It("deletes a value", func() {
+		Expect(status(http.MethodDelete, "/buckets/a/keys/k1", "")).To(Equal(http.StatusNoContent))
+		Expect(status(http.MethodGet, "/buckets/a/keys/k1", "")).To(Equal(http.StatusNotFound))
+	})
+	It("lists, creates and deletes buckets", func() {
+		Expect(status(http.MethodPut, "/buckets/b", "")).To(Equal(http.StatusNoContent))
+		code, body := do(http.MethodGet, "/buckets", "")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(`[{"bucket":"a"},{"bucket":"b"}]`))
+		Expect(status(http.MethodDelete, "/buckets/b", "")).To(Equal(http.StatusNoContent))
+		Expect(status(http.MethodDelete, "/buckets/b", "")).To(Equal(http.StatusNotFound))
+	})
+	It("returns stats", func() {
+		code, body := do(http.MethodGet, "/stats", "")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(body).To(ContainSubstring(`"bucket":"a","keys":1`))
+	})
+	It("downloads a backup", func() {
+		code, body := do(http.MethodGet, "/backup", "")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(len(body)).To(BeNumerically(">", 0))
+	})
+	It("returns 500 if the backup fails before writing", func() {
+		Expect(db.Close()).To(BeNil())
+		code, body := do(http.MethodGet, "/backup", "")
+		Expect(code).To(Equal(http.StatusInternalServerError))
+		Expect(body).To(ContainSubstring("backup failed"))
+	})
+	Context("with token", func() {
+		BeforeEach(func() {
+			opts.Token = "secret"
+		})
+		It("rejects requests without token", func() {
+			resp, err := httpServer.Client().Get(httpServer.URL + "/buckets")
+			Expect(err).To(BeNil())
+			_ = resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+		})
+		It("accepts requests with token", func() {
+			Expect(status(http.MethodGet, "/buckets/a/keys/k1", "")).To(Equal(http.StatusOK))
+		})
+	})
+	Context("with request limit", func() {
+		BeforeEach(func() {
+			opts.MaxRequestBytes = 4
+		})
+		It("rejects large bodies", func() {
+			Expect(status(http.MethodPut, "/buckets/a/keys/k2", "too large")).
+				To(Equal(http.StatusRequestEntityTooLarge))
+		})
+	})
+	Context("read-only", func() {
+		It("rejects writes", func() {
+			handler := server.NewHandler(db, server.Options{ReadOnly: true})
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/buckets/a/keys/k2", nil))
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+			recorder = httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/buckets/a/keys/k1", nil))
+			Expect(recorder.Body.String()).To(Equal("v1"))
+		})
+	})
+})